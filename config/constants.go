@@ -1,13 +1,57 @@
 package constants
 
+import "time"
+
 const CONTENT_ID_LENGTH = 8
 const MAX_IMAGE_SIZE = 2 * 1024 * 1024   // 2 MB
 const MAX_PROFILE_IMG_SIZE = 1024 * 1024 // 1 MB for profile photos
+const MAX_ROSTER_SIZE = 5 * 1024 * 1024  // 5 MB for roster imports
+const MAX_ROSTER_ROWS = 5000
 
 const DEFAULT_PAGE_SIZE = "25"
 const DEFAULT_SORT_COLUMN = "time_created"
 const DEFAULT_SORT_DESCENDING = "true"
 
+// Levels of comment replies returned with an article
+const DEFAULT_COMMENT_DEPTH = "5"
+const MAX_COMMENT_DEPTH = 10
+
+// Maximum number of tags a thread can be labelled with
+const MAX_THREAD_TAGS = 5
+
+// Number of options a poll can have
+const MIN_POLL_OPTIONS = 2
+const MAX_POLL_OPTIONS = 10
+
+// Token settings
+const ACCESS_TOKEN_TTL = 15 * time.Minute        // Lifetime of JWT access tokens
+const REFRESH_TOKEN_TTL = 14 * 24 * time.Hour    // Lifetime of refresh tokens. Rotated on every refresh.
+const IMPERSONATION_TOKEN_TTL = 15 * time.Minute // Lifetime of impersonation tokens. They cannot be refreshed.
+
+// Brute-force protection on login and registration. Applied per IP and per email.
+const AUTH_MAX_ATTEMPTS = 5                // Failed attempts allowed before lockout
+const AUTH_BASE_LOCKOUT = 30 * time.Second // Lockout after the first failure beyond AUTH_MAX_ATTEMPTS, doubled on each further failure
+const AUTH_MAX_LOCKOUT = time.Hour
+const AUTH_ATTEMPT_WINDOW = time.Hour // Failures are forgotten after this long without another failure
+
+// Roles and their permissions are cached for this long
+const ROLE_CACHE_TTL = time.Minute
+
+// Drafts which have not been edited for DRAFT_RETENTION are deleted. Checked every DRAFT_CLEANUP_INTERVAL.
+const DRAFT_RETENTION = 90 * 24 * time.Hour
+const DRAFT_CLEANUP_INTERVAL = 24 * time.Hour
+
+// Deleted content is permanently removed after TRASH_RETENTION_DAYS days, or DEFAULT_TRASH_RETENTION if it is not set.
+// Checked every TRASH_CLEANUP_INTERVAL.
+const DEFAULT_TRASH_RETENTION = 30 * 24 * time.Hour
+const TRASH_CLEANUP_INTERVAL = 24 * time.Hour
+
+// Key of the authenticated principal set on the gin context by AuthGuard
+const CTX_PRINCIPAL = "principal"
+
+// Placeholder author of content whose author has been removed
+const DELETED_USER_UID = "(deleted)"
+
 // Eduvisor settings
 const EDUVISOR_NAME = "EDUVISOR BOT"
 const EDUVISOR_EMAIL = "onemdp.ntu@gmail.com"
@@ -1,141 +1,210 @@
 package middlewares
 
 import (
-	"fmt"
 	"net/http"
-	"os"
 
 	"github.com/gin-gonic/gin"
+	constants "github.com/ntu-onemdp/onemdp-backend/config"
 	"github.com/ntu-onemdp/onemdp-backend/internal/models"
 	"github.com/ntu-onemdp/onemdp-backend/internal/services"
 	utils "github.com/ntu-onemdp/onemdp-backend/internal/utils"
 )
 
-var API_KEY *string
-
-func init() {
-	key, found := os.LookupEnv("API_KEY")
-	if !found {
-		utils.Logger.Warn().Msg("API KEY not set in env variable.")
-	}
-
-	utils.Logger.Info().Msg("API key loaded. Use the API key (found in .env) to access the backend without JWT tokens.")
-	API_KEY = &key
-}
-
-// Verification middleware for non-public routes. Reject if invalid auth token.
-// User role is the mininmum level of authorization.
-// If the user role is student, it does not perform any database query.
-func AuthGuard(role models.UserRole) gin.HandlerFunc {
+// Verification middleware for non-public routes. Reject if invalid auth token or if the token's session has been revoked.
+// The principal must hold every permission in perms. With no permissions, any active user is allowed.
+// On success, the authenticated principal is set on the context. Retrieve it with GetPrincipal.
+func AuthGuard(perms ...models.Permission) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		utils.Logger.Trace().Msg("AuthGuard triggered")
 
-		// Retrieve api key from header
-		apiKey := c.Request.Header.Get("x-api-key")
-		if apiKey != "" {
-			// Match API key
-			if apiKey == *API_KEY {
-				utils.Logger.Info().Msg("Access via API key granted")
-				c.Next()
-			} else {
-				// If this code is reached, there is an unauthorized attempt to access the backend as user requests use JWT and not API key
-				utils.Logger.Warn().Msg("Incorrect API key.")
-				c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid key"})
-				c.Abort()
-				return
-			}
-		}
+		var principal *models.Principal
 
-		// Retrieve jwt token from auth header
-		tokenString := c.Request.Header.Get("Authorization")
-
-		// Remove "Bearer " prefix if included
-		if len(tokenString) > 7 && tokenString[:7] == "Bearer " {
-			tokenString = tokenString[7:]
+		// Retrieve api key from header. Requests with an api key do not need a jwt.
+		if apiKey := c.Request.Header.Get("x-api-key"); apiKey != "" {
+			principal = authenticateApiKey(c, apiKey)
+		} else {
+			principal = authenticateJwt(c)
 		}
 
-		claim, err := services.JwtHandler.ParseJwt(tokenString)
-
-		if err != nil {
-			utils.Logger.Warn().Msg("Invalid token, rejecting claim.")
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token"})
+		// Response has been written
+		if principal == nil {
 			c.Abort()
 			return
 		}
 
-		utils.Logger.Trace().Msgf("Claim verified for %s", claim.Uid)
-
-		// Pass request if min role is student
-		if role <= models.Student {
-			c.Next()
-			return
+		// Every request made while impersonating is audited, including rejected ones
+		if principal.IsImpersonated() {
+			defer func() {
+				if err := services.Impersonation.Record(principal, c.Request.Method, c.Request.URL.Path, c.Writer.Status(), c.ClientIP()); err != nil {
+					utils.Logger.Error().Err(err).Str("impersonator", principal.ImpersonatorUid).Msg("Error recording impersonated request")
+				}
+			}()
+
+			// Read-only impersonation tokens can only be used to view
+			if principal.ReadOnly && !isReadMethod(c.Request.Method) {
+				utils.Logger.Warn().Str("uid", principal.Uid).Str("impersonator", principal.ImpersonatorUid).Msg("Request rejected, impersonation token is read-only")
+				c.JSON(http.StatusForbidden, gin.H{
+					"error": "Impersonation is read-only",
+					"code":  "IMPERSONATION_READ_ONLY",
+				})
+				c.Abort()
+				return
+			}
 		}
 
-		utils.Logger.Trace().Str("min role", role.String()).Msg("AdminGuard triggered")
-
-		userRole, err := services.Users.GetRole(claim.Uid)
+		// Reject suspended, banned and deactivated users, including api keys they own
+		status, err := services.Users.GetAccountStatus(principal.Uid)
 		if err != nil {
-			utils.Logger.Error().Err(err).Msg("Error fetching user role")
-			c.JSON(http.StatusInternalServerError, gin.H{
-				"error": err,
-			})
+			utils.Logger.Error().Err(err).Str("uid", principal.Uid).Msg("Error fetching account status")
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
 			c.Abort()
 			return
 		}
 
-		utils.Logger.Trace().Str("user role", userRole.String()).Msg("User's role fetched from database")
-
-		// Insufficient permission
-		if userRole < role {
-			utils.Logger.Warn().Str("uid", claim.Uid).Str("user role", userRole.String()).Str("min role", role.String()).Msg("Request rejected, user does not have sufficient permissions")
-			c.JSON(http.StatusUnauthorized, gin.H{
-				"error": "You do not have permissions to access this resource",
+		if status.Status != models.StatusActive {
+			utils.Logger.Warn().Str("uid", principal.Uid).Str("status", status.Status).Msg("Request rejected, account is not active")
+			c.JSON(http.StatusForbidden, gin.H{
+				"error":           status.ErrorMessage(),
+				"code":            status.ErrorCode(),
+				"reason":          status.StatusReason,
+				"suspended_until": status.SuspendedUntil,
 			})
 			c.Abort()
 			return
 		}
 
-		utils.Logger.Trace().Msgf("AdminGuard approved for user %s", claim.Uid)
+		// Insufficient permission
+		for _, perm := range perms {
+			if !principal.Can(perm) {
+				utils.Logger.Warn().Str("uid", principal.Uid).Str("user role", principal.Role).Str("permission", string(perm)).Msg("Request rejected, user does not have sufficient permissions")
+				c.JSON(http.StatusUnauthorized, gin.H{
+					"error": "You do not have permissions to access this resource",
+				})
+				c.Abort()
+				return
+			}
+		}
+
+		utils.Logger.Trace().Str("uid", principal.Uid).Str("method", string(principal.Method)).Msg("AuthGuard approved")
+		c.Set(constants.CTX_PRINCIPAL, principal)
 		c.Next()
 	}
 }
 
-// Verification middleware for admin. Reject if not admin.
-func AdminGuard() gin.HandlerFunc {
-	return func(c *gin.Context) {
-		utils.Logger.Trace().Msg("AdminGuard triggered")
+// Retrieve the principal set by AuthGuard.
+// If there is none (route is not guarded), a 401 Unauthorized response is written, the request is aborted and nil is returned.
+func GetPrincipal(c *gin.Context) *models.Principal {
+	if value, ok := c.Get(constants.CTX_PRINCIPAL); ok {
+		if principal, ok := value.(*models.Principal); ok {
+			return principal
+		}
+	}
 
-		// Retrieve jwt token from auth header
-		tokenString := c.Request.Header.Get("Authorization")
+	utils.Logger.Error().Str("path", c.FullPath()).Msg("No principal found on context. Is the route guarded by AuthGuard?")
+	c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
+		"success": false,
+		"error":   "Unauthorized",
+	})
+	return nil
+}
 
-		// Remove "Bearer " prefix if included
-		if len(tokenString) > 7 && tokenString[:7] == "Bearer " {
-			tokenString = tokenString[7:]
-		}
+// Verify jwt in the Authorization header and that its session is still active.
+// Writes the error response and returns nil if verification fails.
+func authenticateJwt(c *gin.Context) *models.Principal {
+	// Retrieve jwt token from auth header
+	tokenString := c.Request.Header.Get("Authorization")
+
+	claim, err := services.JwtHandler.ParseJwt(tokenString)
+	if err != nil {
+		utils.Logger.Warn().Msg("Invalid token, rejecting claim.")
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token"})
+		return nil
+	}
 
-		claim, err := services.JwtHandler.ParseJwt(tokenString)
+	// Reject tokens whose session has been revoked (logout, admin revocation) or has expired
+	if !services.Sessions.IsActive(claim.Sid) {
+		utils.Logger.Warn().Str("uid", claim.Uid).Msg("Session is no longer active, rejecting claim.")
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Session has expired or has been revoked"})
+		return nil
+	}
 
-		if err != nil {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token"})
-			c.Abort()
-			return
-		}
+	utils.Logger.Trace().Msgf("Claim verified for %s", claim.Uid)
 
-		user, err := services.Users.GetProfile(claim.Uid)
-		if err != nil {
-			utils.Logger.Error().Err(err).Msg("Error retrieving user profile")
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Internal server error"})
-			c.Abort()
-			return
-		}
+	userRole, err := services.Users.GetRole(claim.Uid)
+	if err != nil {
+		utils.Logger.Error().Err(err).Msg("Error fetching user role")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		return nil
+	}
 
-		if user.Role != "admin" {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized. You need to be an admin to access this function."})
-			c.Abort()
-			return
-		}
+	utils.Logger.Trace().Str("user role", userRole).Msg("User's role fetched from database")
 
-		utils.Logger.Trace().Msg(fmt.Sprintf("claim verified for %s of role %s", claim.Uid, user.Role))
-		c.Next()
+	role, err := services.Roles.Get(userRole)
+	if err != nil {
+		utils.Logger.Error().Err(err).Str("uid", claim.Uid).Msg("Error fetching permissions of user role")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		return nil
+	}
+
+	principal := &models.Principal{
+		Uid:         claim.Uid,
+		Role:        userRole,
+		Permissions: role.Permissions,
+		Method:      models.AuthJwt,
+		SessionID:   claim.Sid,
+	}
+
+	if claim.Act != nil {
+		principal.ImpersonatorUid = claim.Act.Sub
+		principal.ReadOnly = claim.ReadOnly
+		utils.Logger.Info().Str("uid", claim.Uid).Str("impersonator", claim.Act.Sub).Msg("Request made with impersonation token")
+	}
+
+	return principal
+}
+
+func isReadMethod(method string) bool {
+	return method == http.MethodGet || method == http.MethodHead || method == http.MethodOptions
+}
+
+// Verify api key and act as its owner. Only permissions held by both the owner's role and the key's max role are granted.
+// Writes the error response and returns nil if verification fails.
+func authenticateApiKey(c *gin.Context, apiKey string) *models.Principal {
+	key, err := services.ApiKeys.Authenticate(apiKey)
+	if err != nil {
+		// If this code is reached, there is an unauthorized attempt to access the backend as user requests use JWT and not API key
+		utils.Logger.Warn().Msg("Incorrect, expired or revoked API key.")
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid key"})
+		return nil
+	}
+
+	if !key.InScope(c.Request.URL.Path) {
+		utils.Logger.Warn().Str("key id", key.KeyID).Str("path", c.Request.URL.Path).Msg("API key used outside of its scopes")
+		c.JSON(http.StatusForbidden, gin.H{"error": "API key is not allowed to access this resource"})
+		return nil
+	}
+
+	ownerRole, err := services.Users.GetRole(key.OwnerUid)
+	if err != nil {
+		utils.Logger.Error().Err(err).Str("key id", key.KeyID).Msg("Error fetching role of API key owner")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		return nil
+	}
+
+	permissions, err := services.Roles.CommonPermissions(ownerRole, key.MaxRole)
+	if err != nil {
+		utils.Logger.Error().Err(err).Str("key id", key.KeyID).Msg("Error fetching permissions of API key")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		return nil
+	}
+
+	utils.Logger.Info().Str("key id", key.KeyID).Str("owner", key.OwnerUid).Msgf("Access via API key %s granted", key.Name)
+
+	return &models.Principal{
+		Uid:         key.OwnerUid,
+		Role:        ownerRole,
+		Permissions: permissions,
+		Method:      models.AuthApiKey,
+		ApiKeyID:    key.KeyID,
 	}
 }
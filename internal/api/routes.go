@@ -4,16 +4,21 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/ntu-onemdp/onemdp-backend/internal/api/middlewares"
 	"github.com/ntu-onemdp/onemdp-backend/internal/api/v1/admin"
+	"github.com/ntu-onemdp/onemdp-backend/internal/api/v1/admin/enrolment"
 	"github.com/ntu-onemdp/onemdp-backend/internal/api/v1/admin/karma"
 	"github.com/ntu-onemdp/onemdp-backend/internal/api/v1/articles"
 	"github.com/ntu-onemdp/onemdp-backend/internal/api/v1/auth"
 	"github.com/ntu-onemdp/onemdp-backend/internal/api/v1/comments"
+	"github.com/ntu-onemdp/onemdp-backend/internal/api/v1/drafts"
 	"github.com/ntu-onemdp/onemdp-backend/internal/api/v1/favorite"
 	"github.com/ntu-onemdp/onemdp-backend/internal/api/v1/files"
 	"github.com/ntu-onemdp/onemdp-backend/internal/api/v1/images"
 	"github.com/ntu-onemdp/onemdp-backend/internal/api/v1/like"
+	"github.com/ntu-onemdp/onemdp-backend/internal/api/v1/notifications"
 	"github.com/ntu-onemdp/onemdp-backend/internal/api/v1/posts"
+	"github.com/ntu-onemdp/onemdp-backend/internal/api/v1/tags"
 	"github.com/ntu-onemdp/onemdp-backend/internal/api/v1/threads"
+	"github.com/ntu-onemdp/onemdp-backend/internal/api/v1/trash"
 	"github.com/ntu-onemdp/onemdp-backend/internal/api/v1/users"
 	"github.com/ntu-onemdp/onemdp-backend/internal/models"
 )
@@ -25,24 +30,41 @@ import (
 ||                            ||
 ################################
 */
+func RegisterWellKnownRoutes(router *gin.RouterGroup) {
+	// GET /.well-known/jwks.json
+	router.GET("/jwks.json", func(c *gin.Context) {
+		auth.JwksHandler(c)
+	})
+}
+
 func RegisterAuthRoutes(router *gin.RouterGroup) {
 	// [AE-3] POST /api/v1/auth/login
 	router.POST("/login", func(c *gin.Context) {
 		auth.LoginHandler(c)
 	})
 
+	// POST /api/v1/auth/refresh
+	router.POST("/refresh", func(c *gin.Context) {
+		auth.RefreshTokenHandler(c)
+	})
+
+	// POST /api/v1/auth/logout
+	router.POST("/logout", middlewares.AuthGuard(), func(c *gin.Context) {
+		auth.LogoutHandler(c)
+	})
+
 	// [AE-104] POST /api/v1/auth/register
 	router.POST("/register", func(c *gin.Context) {
 		auth.RegisterUserHandler(c)
 	})
 
-	// [AE-105] GET /api/v1/auth/enrolment-code (Staff and above)
-	router.GET("/enrolment-code", middlewares.AuthGuard(models.Staff), func(c *gin.Context) {
+	// [AE-105] GET /api/v1/auth/enrolment-code (enrolment.view)
+	router.GET("/enrolment-code", middlewares.AuthGuard(models.PermEnrolmentView), func(c *gin.Context) {
 		auth.GetCodeHandler(c)
 	})
 
-	// [AE-106] POST /api/v1/auth/enrolment-code/refresh (Admin)
-	router.POST("/enrolment-code/refresh", middlewares.AuthGuard(models.Admin), func(c *gin.Context) {
+	// [AE-106] POST /api/v1/auth/enrolment-code/refresh (enrolment.manage)
+	router.POST("/enrolment-code/refresh", middlewares.AuthGuard(models.PermEnrolmentManage), func(c *gin.Context) {
 		auth.RefreshCodeHandler(c)
 	})
 }
@@ -127,6 +149,11 @@ func RegisterStudentUserRoutes(router *gin.RouterGroup) {
 	router.GET("/verify-admin", func(c *gin.Context) {
 		users.VerifyAdminHandler(c)
 	})
+
+	// GET /api/v1/users/search?q=
+	router.GET("/search", func(c *gin.Context) {
+		users.SearchUsersHandler(c)
+	})
 }
 
 // Routes starting with /threads
@@ -136,7 +163,7 @@ func RegisterThreadRoutes(router *gin.RouterGroup) {
 		threads.CreateThreadHandler(c)
 	})
 
-	// [AE-14] GET /api/v1/threads?size=25&sort=time_created&desc=true&timestamp=0
+	// [AE-14] GET /api/v1/threads?size=25&sort=time_created&desc=true&timestamp=0&tags=&match=any|all&status=resolved|unresolved&endorsed=true
 	router.GET("/", func(c *gin.Context) {
 		threads.GetAllThreadsHandler(c)
 	})
@@ -145,7 +172,7 @@ func RegisterThreadRoutes(router *gin.RouterGroup) {
 		threads.SearchThreadsHandler(c)
 	})
 
-	// [AE-20] GET /api/v1/threads/:thread_id
+	// [AE-20] GET /api/v1/threads/:thread_id?view=flat|tree
 	router.GET("/:thread_id", func(c *gin.Context) {
 		threads.GetOneThreadHandler(c)
 	})
@@ -154,6 +181,77 @@ func RegisterThreadRoutes(router *gin.RouterGroup) {
 	router.DELETE("/:thread_id", func(c *gin.Context) {
 		threads.DeleteThreadHandler(c)
 	})
+
+	// PUT /api/v1/threads/:thread_id/tags
+	router.PUT("/:thread_id/tags", func(c *gin.Context) {
+		threads.UpdateTagsHandler(c)
+	})
+
+	// POST /api/v1/threads/:thread_id/accept
+	router.POST("/:thread_id/accept", func(c *gin.Context) {
+		threads.AcceptAnswerHandler(c)
+	})
+
+	// DELETE /api/v1/threads/:thread_id/accept
+	router.DELETE("/:thread_id/accept", func(c *gin.Context) {
+		threads.UnacceptAnswerHandler(c)
+	})
+
+	// POST /api/v1/threads/:thread_id/poll
+	router.POST("/:thread_id/poll", func(c *gin.Context) {
+		threads.CreatePollHandler(c)
+	})
+
+	// GET /api/v1/threads/:thread_id/poll
+	router.GET("/:thread_id/poll", func(c *gin.Context) {
+		threads.GetPollHandler(c)
+	})
+
+	// POST /api/v1/threads/:thread_id/poll/vote
+	router.POST("/:thread_id/poll/vote", func(c *gin.Context) {
+		threads.VoteHandler(c)
+	})
+}
+
+// Routes starting with /tags
+func RegisterTagRoutes(router *gin.RouterGroup) {
+	// GET /api/v1/tags
+	router.GET("/", func(c *gin.Context) {
+		tags.GetTagsHandler(c)
+	})
+}
+
+// Routes starting with /notifications
+func RegisterNotificationRoutes(router *gin.RouterGroup) {
+	// GET /api/v1/notifications?page=&size=&unread=true
+	router.GET("/", func(c *gin.Context) {
+		notifications.GetNotificationsHandler(c)
+	})
+
+	// GET /api/v1/notifications/unread-count
+	router.GET("/unread-count", func(c *gin.Context) {
+		notifications.GetUnreadCountHandler(c)
+	})
+
+	// PUT /api/v1/notifications/read-all
+	router.PUT("/read-all", func(c *gin.Context) {
+		notifications.MarkAllReadHandler(c)
+	})
+
+	// PUT /api/v1/notifications/:notification_id/read
+	router.PUT("/:notification_id/read", func(c *gin.Context) {
+		notifications.MarkReadHandler(c)
+	})
+
+	// GET /api/v1/notifications/preferences
+	router.GET("/preferences", func(c *gin.Context) {
+		notifications.GetPreferencesHandler(c)
+	})
+
+	// PUT /api/v1/notifications/preferences
+	router.PUT("/preferences", func(c *gin.Context) {
+		notifications.UpdatePreferencesHandler(c)
+	})
 }
 
 // Routes starting with /posts
@@ -191,11 +289,31 @@ func RegisterArticleRoutes(router *gin.RouterGroup) {
 		articles.GetAllArticlesHandler(c)
 	})
 
-	// [AE-63] GET /api/v1/articles/:article_id
+	// [AE-63] GET /api/v1/articles/:article_id?depth=
 	router.GET("/:article_id", func(c *gin.Context) {
 		articles.GetOneArticleHandler(c)
 	})
 
+	// POST /api/v1/articles/:article_id/edit
+	router.POST("/:article_id/edit", func(c *gin.Context) {
+		articles.EditArticleHandler(c)
+	})
+
+	// GET /api/v1/articles/:article_id/revisions
+	router.GET("/:article_id/revisions", func(c *gin.Context) {
+		articles.GetRevisionsHandler(c)
+	})
+
+	// GET /api/v1/articles/:article_id/diff?from=&to=
+	router.GET("/:article_id/diff", func(c *gin.Context) {
+		articles.DiffRevisionsHandler(c)
+	})
+
+	// POST /api/v1/articles/:article_id/revisions/:revision/rollback
+	router.POST("/:article_id/revisions/:revision/rollback", func(c *gin.Context) {
+		articles.RollbackArticleHandler(c)
+	})
+
 	// [AE-58] DELETE /api/v1/articles/:article_id
 	router.DELETE("/:article_id", func(c *gin.Context) {
 		articles.DeleteArticleHandler(c)
@@ -209,12 +327,55 @@ func RegisterCommentRoutes(router *gin.RouterGroup) {
 		comments.CreateCommentHandler(c)
 	})
 
+	// GET /api/v1/comments/:comment_id/replies?depth=
+	router.GET("/:comment_id/replies", func(c *gin.Context) {
+		comments.GetRepliesHandler(c)
+	})
+
+	// POST /api/v1/comments/:comment_id/edit
+	router.POST("/:comment_id/edit", func(c *gin.Context) {
+		comments.EditCommentHandler(c)
+	})
+
 	// [AE-53] DELETE /api/v1/comments/:comment_id
 	router.DELETE("/:comment_id", func(c *gin.Context) {
 		comments.DeleteCommentHandler(c)
 	})
 }
 
+// Routes starting with /drafts
+func RegisterDraftRoutes(router *gin.RouterGroup) {
+	// POST /api/v1/drafts
+	router.POST("/", func(c *gin.Context) {
+		drafts.CreateDraftHandler(c)
+	})
+
+	// GET /api/v1/drafts?type=
+	router.GET("/", func(c *gin.Context) {
+		drafts.GetDraftsHandler(c)
+	})
+
+	// GET /api/v1/drafts/:draft_id
+	router.GET("/:draft_id", func(c *gin.Context) {
+		drafts.GetDraftHandler(c)
+	})
+
+	// PUT /api/v1/drafts/:draft_id
+	router.PUT("/:draft_id", func(c *gin.Context) {
+		drafts.SaveDraftHandler(c)
+	})
+
+	// DELETE /api/v1/drafts/:draft_id
+	router.DELETE("/:draft_id", func(c *gin.Context) {
+		drafts.DeleteDraftHandler(c)
+	})
+
+	// POST /api/v1/drafts/:draft_id/publish
+	router.POST("/:draft_id/publish", func(c *gin.Context) {
+		drafts.PublishDraftHandler(c)
+	})
+}
+
 // Routes starting with /files
 func RegisterFileRoutes(router *gin.RouterGroup) {
 	// [AE-100] GET /api/v1/files/:file_id
@@ -248,6 +409,81 @@ func RegisterFileMgmtRoutes(router *gin.RouterGroup) {
 	})
 }
 
+// Staff routes starting with /staff/posts
+func RegisterPostMgmtRoutes(router *gin.RouterGroup) {
+	// GET /api/v1/staff/posts/:post_id/revisions
+	router.GET("/:post_id/revisions", func(c *gin.Context) {
+		posts.GetPostRevisionsHandler(c)
+	})
+
+	// GET /api/v1/staff/posts/:post_id/diff?from=&to=
+	router.GET("/:post_id/diff", func(c *gin.Context) {
+		posts.DiffPostRevisionsHandler(c)
+	})
+}
+
+// Register staff routes for pinning, locking and announcing threads
+func RegisterThreadMgmtRoutes(router *gin.RouterGroup) {
+	// PUT /api/v1/staff/threads/:thread_id/state
+	router.PUT("/:thread_id/state", func(c *gin.Context) {
+		threads.UpdateStateHandler(c)
+	})
+}
+
+// Register staff routes for endorsing threads and posts
+func RegisterEndorsementRoutes(router *gin.RouterGroup) {
+	// POST /api/v1/staff/endorsements/threads/:thread_id
+	router.POST("/threads/:thread_id", func(c *gin.Context) {
+		threads.EndorseThreadHandler(c)
+	})
+
+	// DELETE /api/v1/staff/endorsements/threads/:thread_id
+	router.DELETE("/threads/:thread_id", func(c *gin.Context) {
+		threads.UnendorseThreadHandler(c)
+	})
+
+	// POST /api/v1/staff/endorsements/posts/:post_id
+	router.POST("/posts/:post_id", func(c *gin.Context) {
+		posts.EndorsePostHandler(c)
+	})
+
+	// DELETE /api/v1/staff/endorsements/posts/:post_id
+	router.DELETE("/posts/:post_id", func(c *gin.Context) {
+		posts.UnendorsePostHandler(c)
+	})
+}
+
+// Register staff routes for deleted content
+func RegisterTrashRoutes(router *gin.RouterGroup) {
+	// GET /api/v1/staff/trash?type=&page=&size=
+	router.GET("/", func(c *gin.Context) {
+		trash.GetTrashHandler(c)
+	})
+
+	// POST /api/v1/staff/trash/:content_id/restore
+	router.POST("/:content_id/restore", func(c *gin.Context) {
+		trash.RestoreHandler(c)
+	})
+}
+
+// Register staff routes for managing tags
+func RegisterTagMgmtRoutes(router *gin.RouterGroup) {
+	// POST /api/v1/staff/tags
+	router.POST("/", func(c *gin.Context) {
+		tags.CreateTagHandler(c)
+	})
+
+	// PUT /api/v1/staff/tags/:tag_id
+	router.PUT("/:tag_id", func(c *gin.Context) {
+		tags.UpdateTagHandler(c)
+	})
+
+	// DELETE /api/v1/staff/tags/:tag_id
+	router.DELETE("/:tag_id", func(c *gin.Context) {
+		tags.DeleteTagHandler(c)
+	})
+}
+
 /*
 ################################
 ||                            ||
@@ -262,6 +498,11 @@ func RegisterAdminUserRoutes(router *gin.RouterGroup) {
 		admin.CreateUsersHandler(c)
 	})
 
+	// POST /api/v1/admin/users/import
+	router.POST("/users/import", func(c *gin.Context) {
+		admin.ImportUsersHandler(c)
+	})
+
 	// [AE-9] GET /api/v1/admin/users
 	router.GET("/users", func(c *gin.Context) {
 		admin.GetAllUsersHandler(c)
@@ -276,6 +517,100 @@ func RegisterAdminUserRoutes(router *gin.RouterGroup) {
 	router.POST("/users/update-role", func(c *gin.Context) {
 		admin.UpdateRoleHandler(c)
 	})
+
+	// GET /api/v1/admin/users/:uid/sessions
+	router.GET("/users/:uid/sessions", func(c *gin.Context) {
+		admin.GetSessionsHandler(c)
+	})
+
+	// POST /api/v1/admin/users/:uid/revoke-sessions
+	router.POST("/users/:uid/revoke-sessions", func(c *gin.Context) {
+		admin.RevokeSessionsHandler(c)
+	})
+
+	// POST /api/v1/admin/users/:uid/suspend
+	router.POST("/users/:uid/suspend", func(c *gin.Context) {
+		admin.SuspendUserHandler(c)
+	})
+
+	// POST /api/v1/admin/users/:uid/ban
+	router.POST("/users/:uid/ban", func(c *gin.Context) {
+		admin.BanUserHandler(c)
+	})
+
+	// POST /api/v1/admin/users/:uid/deactivate
+	router.POST("/users/:uid/deactivate", func(c *gin.Context) {
+		admin.DeactivateUserHandler(c)
+	})
+
+	// POST /api/v1/admin/users/:uid/reinstate
+	router.POST("/users/:uid/reinstate", func(c *gin.Context) {
+		admin.ReinstateUserHandler(c)
+	})
+
+	// POST /api/v1/admin/users/:uid/remove
+	router.POST("/users/:uid/remove", func(c *gin.Context) {
+		admin.RemoveUserHandler(c)
+	})
+
+	// GET /api/v1/admin/users/:uid/export
+	router.GET("/users/:uid/export", func(c *gin.Context) {
+		admin.ExportUserHandler(c)
+	})
+
+	// POST /api/v1/admin/users/deactivate-semester
+	router.POST("/users/deactivate-semester", func(c *gin.Context) {
+		admin.DeactivateSemesterHandler(c)
+	})
+
+	// POST /api/v1/admin/users/:uid/impersonate
+	router.POST("/users/:uid/impersonate", func(c *gin.Context) {
+		admin.ImpersonateHandler(c)
+	})
+
+	// GET /api/v1/admin/auth-failures
+	router.GET("/auth-failures", func(c *gin.Context) {
+		admin.GetAuthFailuresHandler(c)
+	})
+
+	// GET /api/v1/admin/impersonation-audit
+	router.GET("/impersonation-audit", func(c *gin.Context) {
+		admin.GetImpersonationAuditHandler(c)
+	})
+}
+
+func RegisterAdminApiKeyRoutes(router *gin.RouterGroup) {
+	// POST /api/v1/admin/api-keys
+	router.POST("/", func(c *gin.Context) {
+		admin.CreateApiKeyHandler(c)
+	})
+
+	// GET /api/v1/admin/api-keys
+	router.GET("/", func(c *gin.Context) {
+		admin.GetApiKeysHandler(c)
+	})
+
+	// DELETE /api/v1/admin/api-keys/:key_id
+	router.DELETE("/:key_id", func(c *gin.Context) {
+		admin.RevokeApiKeyHandler(c)
+	})
+}
+
+func RegisterAdminRoleRoutes(router *gin.RouterGroup) {
+	// GET /api/v1/admin/roles
+	router.GET("/", func(c *gin.Context) {
+		admin.GetRolesHandler(c)
+	})
+
+	// PUT /api/v1/admin/roles/:role
+	router.PUT("/:role", func(c *gin.Context) {
+		admin.SaveRoleHandler(c)
+	})
+
+	// DELETE /api/v1/admin/roles/:role
+	router.DELETE("/:role", func(c *gin.Context) {
+		admin.DeleteRoleHandler(c)
+	})
 }
 
 func RegisterAdminKarmaRoutes(router *gin.RouterGroup) {
@@ -289,3 +624,30 @@ func RegisterAdminKarmaRoutes(router *gin.RouterGroup) {
 		karma.RetrieveKarmaHandler(c)
 	})
 }
+
+func RegisterAdminEnrolmentRoutes(router *gin.RouterGroup) {
+	// POST /api/v1/admin/enrolment-codes
+	router.POST("/", func(c *gin.Context) {
+		enrolment.CreateCodeHandler(c)
+	})
+
+	// GET /api/v1/admin/enrolment-codes?semester=
+	router.GET("/", func(c *gin.Context) {
+		enrolment.GetCodesHandler(c)
+	})
+
+	// POST /api/v1/admin/enrolment-codes/:code_id/disable
+	router.POST("/:code_id/disable", func(c *gin.Context) {
+		enrolment.DisableCodeHandler(c)
+	})
+
+	// POST /api/v1/admin/enrolment-codes/:code_id/enable
+	router.POST("/:code_id/enable", func(c *gin.Context) {
+		enrolment.EnableCodeHandler(c)
+	})
+
+	// GET /api/v1/admin/enrolment-codes/:code_id/redemptions
+	router.GET("/:code_id/redemptions", func(c *gin.Context) {
+		enrolment.GetRedemptionsHandler(c)
+	})
+}
@@ -0,0 +1,171 @@
+package admin
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/ntu-onemdp/onemdp-backend/internal/api/middlewares"
+	"github.com/ntu-onemdp/onemdp-backend/internal/models"
+	"github.com/ntu-onemdp/onemdp-backend/internal/services"
+	"github.com/ntu-onemdp/onemdp-backend/internal/utils"
+)
+
+type suspendRequest struct {
+	Reason string     `json:"reason" binding:"required"`
+	Until  *time.Time `json:"until"` // Suspension is indefinite if not provided
+}
+
+type statusReasonRequest struct {
+	Reason string `json:"reason" binding:"required"`
+}
+
+type deactivateSemesterRequest struct {
+	Semester string `json:"semester" binding:"required"`
+	Reason   string `json:"reason"`
+}
+
+// Suspend a user with a reason and optional expiry
+func SuspendUserHandler(c *gin.Context) {
+	uid := c.Param("uid")
+	utils.Logger.Info().Str("uid", uid).Msg("Suspend user request received")
+
+	var req suspendRequest
+	if !bindStatusRequest(c, &req) {
+		return
+	}
+
+	updateStatus(c, uid, "suspended", func(p *models.Principal) error {
+		return services.Users.Suspend(uid, req.Reason, req.Until, p)
+	})
+}
+
+// Ban a user permanently
+func BanUserHandler(c *gin.Context) {
+	uid := c.Param("uid")
+	utils.Logger.Info().Str("uid", uid).Msg("Ban user request received")
+
+	var req statusReasonRequest
+	if !bindStatusRequest(c, &req) {
+		return
+	}
+
+	updateStatus(c, uid, "banned", func(p *models.Principal) error {
+		return services.Users.Ban(uid, req.Reason, p)
+	})
+}
+
+// Deactivate a single user
+func DeactivateUserHandler(c *gin.Context) {
+	uid := c.Param("uid")
+	utils.Logger.Info().Str("uid", uid).Msg("Deactivate user request received")
+
+	var req statusReasonRequest
+	if !bindStatusRequest(c, &req) {
+		return
+	}
+
+	updateStatus(c, uid, "deactivated", func(p *models.Principal) error {
+		return services.Users.Deactivate(uid, req.Reason, p)
+	})
+}
+
+// Reinstate a suspended, banned or deactivated user
+func ReinstateUserHandler(c *gin.Context) {
+	uid := c.Param("uid")
+	utils.Logger.Info().Str("uid", uid).Msg("Reinstate user request received")
+
+	updateStatus(c, uid, "reinstated", func(p *models.Principal) error {
+		return services.Users.Reinstate(uid)
+	})
+}
+
+// Deactivate all students of a semester at the end of the semester
+func DeactivateSemesterHandler(c *gin.Context) {
+	utils.Logger.Info().Msg("Deactivate semester request received")
+
+	var req deactivateSemesterRequest
+	if !bindStatusRequest(c, &req) {
+		return
+	}
+
+	if req.Reason == "" {
+		req.Reason = "Semester " + req.Semester + " has ended"
+	}
+
+	numDeactivated, err := services.Users.DeactivateSemester(req.Semester, req.Reason)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "Error deactivating users",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":         true,
+		"message":         "Students of semester " + req.Semester + " deactivated",
+		"num_deactivated": numDeactivated,
+	})
+}
+
+func bindStatusRequest(c *gin.Context, req any) bool {
+	if err := c.ShouldBindJSON(req); err != nil {
+		utils.Logger.Error().Err(err).Msg("Error binding account status request")
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Malformed request",
+		})
+		return false
+	}
+
+	return true
+}
+
+// Run update as the principal of the request and write the response. Admins cannot change their own status.
+func updateStatus(c *gin.Context, uid string, action string, update func(p *models.Principal) error) {
+	principal := middlewares.GetPrincipal(c)
+	if principal == nil {
+		return
+	}
+
+	if principal.Uid == uid {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "You cannot change the status of your own account",
+		})
+		return
+	}
+
+	if err := update(principal); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			c.JSON(http.StatusNotFound, gin.H{
+				"success": false,
+				"error":   "User not found",
+			})
+			return
+		}
+		if errors.Is(err, services.ErrRoleNotHeld) {
+			c.JSON(http.StatusForbidden, gin.H{
+				"success": false,
+				"error":   "You cannot change the status of a user with permissions you do not have",
+			})
+			return
+		}
+
+		utils.Logger.Error().Err(err).Str("uid", uid).Msg("Error updating account status")
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	utils.Logger.Info().Str("uid", uid).Str("by", principal.Uid).Msgf("User %s %s", uid, action)
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "User " + action,
+	})
+}
@@ -0,0 +1,128 @@
+package admin
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/ntu-onemdp/onemdp-backend/internal/api/middlewares"
+	"github.com/ntu-onemdp/onemdp-backend/internal/models"
+	"github.com/ntu-onemdp/onemdp-backend/internal/services"
+	"github.com/ntu-onemdp/onemdp-backend/internal/utils"
+)
+
+type createApiKeyRequest struct {
+	Name      string     `json:"name" binding:"required"`
+	OwnerUid  string     `json:"owner_uid"` // Defaults to the admin creating the key
+	MaxRole   string     `json:"max_role"`  // Defaults to student
+	Scopes    []string   `json:"scopes"`    // Route prefixes, e.g. /api/v1/threads. Empty allows all routes.
+	ExpiresAt *time.Time `json:"expires_at"`
+}
+
+// Create a new api key. The plaintext key is only returned in this response.
+func CreateApiKeyHandler(c *gin.Context) {
+	utils.Logger.Info().Msg("Create api key request received")
+
+	var req createApiKeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.Logger.Error().Err(err).Msg("Error binding create api key request")
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Malformed request",
+		})
+		return
+	}
+
+	if req.OwnerUid == "" {
+		principal := middlewares.GetPrincipal(c)
+		if principal == nil {
+			return
+		}
+		req.OwnerUid = principal.Uid
+	}
+
+	if req.MaxRole == "" {
+		req.MaxRole = models.RoleStudent
+	}
+	if _, err := services.Roles.Get(req.MaxRole); err != nil {
+		utils.Logger.Warn().Err(err).Msg("Invalid role ceiling for api key")
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Invalid max_role",
+		})
+		return
+	}
+
+	if _, err := services.Users.GetProfile(req.OwnerUid); err != nil {
+		utils.Logger.Warn().Err(err).Str("owner", req.OwnerUid).Msg("Api key owner not found")
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Owner not found",
+		})
+		return
+	}
+
+	key, plaintext, err := services.ApiKeys.Create(req.Name, req.OwnerUid, req.MaxRole, req.Scopes, req.ExpiresAt)
+	if err != nil {
+		utils.Logger.Error().Err(err).Msg("Error creating api key")
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "Error creating api key",
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"api_key": key,
+		"key":     plaintext,
+	})
+}
+
+// List all api keys. Key values are never returned.
+func GetApiKeysHandler(c *gin.Context) {
+	utils.Logger.Info().Msg("Get api keys request received")
+
+	keys, err := services.ApiKeys.GetAll()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "Error retrieving api keys",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":  true,
+		"api_keys": keys,
+	})
+}
+
+// Revoke an api key. Takes effect on the next request made with the key.
+func RevokeApiKeyHandler(c *gin.Context) {
+	keyID := c.Param("key_id")
+	utils.Logger.Info().Str("key id", keyID).Msg("Revoke api key request received")
+
+	if err := services.ApiKeys.Revoke(keyID); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			c.JSON(http.StatusNotFound, gin.H{
+				"success": false,
+				"error":   "Api key not found or already revoked",
+			})
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "Error revoking api key",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Api key revoked",
+	})
+}
@@ -0,0 +1,47 @@
+package admin
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ntu-onemdp/onemdp-backend/internal/services"
+	"github.com/ntu-onemdp/onemdp-backend/internal/utils"
+)
+
+// Default and maximum number of failed attempts returned
+const (
+	defaultAuthFailuresLimit = 100
+	maxAuthFailuresLimit     = 1000
+)
+
+// List recent failed login and registration attempts. Filter with ?ip= and ?email=
+func GetAuthFailuresHandler(c *gin.Context) {
+	ip := c.DefaultQuery("ip", "")
+	email := c.DefaultQuery("email", "")
+	utils.Logger.Info().Str("ip", ip).Str("email", email).Msg("Get auth failures request received")
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(defaultAuthFailuresLimit)))
+	if err != nil || limit <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Invalid limit",
+		})
+		return
+	}
+	limit = min(limit, maxAuthFailuresLimit)
+
+	failures, err := services.AuthFailures.GetRecent(ip, email, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "Error retrieving failed attempts",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":  true,
+		"failures": failures,
+	})
+}
@@ -5,6 +5,7 @@ import (
 	"strings"
 
 	"github.com/gin-gonic/gin"
+	"github.com/ntu-onemdp/onemdp-backend/internal/api/middlewares"
 	"github.com/ntu-onemdp/onemdp-backend/internal/models"
 	"github.com/ntu-onemdp/onemdp-backend/internal/services"
 	"github.com/ntu-onemdp/onemdp-backend/internal/utils"
@@ -31,10 +32,18 @@ type CreateUserResponse struct {
 type SingleUserResponse struct {
 	Email  string `json:"email"`
 	Result string `json:"result"`
+	Reason string `json:"reason,omitempty"` // Why the user was not created
 }
 
+// Add pending students to the current semester
 func CreateUsersHandler(c *gin.Context) {
 	utils.Logger.Info().Msg("Create new user request received")
+
+	principal := middlewares.GetPrincipal(c)
+	if principal == nil {
+		return
+	}
+
 	var createNewUsersRequest CreateNewUsersRequest
 	createUserResponse := CreateUserResponse{
 		NumSuccess: 0,
@@ -48,28 +57,38 @@ func CreateUsersHandler(c *gin.Context) {
 		return
 	}
 
-	for _, newUser := range createNewUsersRequest.Users {
-		singleUserResult := SingleUserResponse{
-			Email:  newUser.Email,
-			Result: "not created", // Defaults to not created
+	rows := make([]models.RosterRow, len(createNewUsersRequest.Users))
+	for i, newUser := range createNewUsersRequest.Users {
+		rows[i] = models.RosterRow{
+			Row:   i + 1,
+			Email: strings.TrimSpace(newUser.Email),
+			Role:  models.RoleStudent,
 		}
+	}
 
-		// Call service to create new user
-		if err := services.Users.CreateNewUser(strings.ToUpper(newUser.Email), models.Student.String()); err != nil {
-			utils.Logger.Error().Err(err).Msg("Error encountered when inserting new user")
+	// Call service to create new users
+	result, err := services.Users.ImportRoster(rows, false, principal)
+	if err != nil {
+		utils.Logger.Error().Err(err).Msg("Error encountered when inserting new users")
+		c.JSON(http.StatusInternalServerError, nil)
+		return
+	}
+
+	for _, res := range result.Results {
+		singleUserResult := SingleUserResponse{
+			Email:  res.Email,
+			Result: "success",
+		}
+		if res.Result == models.RosterFailed {
 			singleUserResult.Result = "failed"
-		} else {
-			singleUserResult.Result = "success"
+			singleUserResult.Reason = strings.Join(res.Errors, ", ")
 		}
 
 		// Append the result to overall response
 		createUserResponse.Results = append(createUserResponse.Results, singleUserResult)
-		if singleUserResult.Result == "failed" {
-			createUserResponse.NumFailed++
-		} else {
-			createUserResponse.NumSuccess++
-		}
 	}
+	createUserResponse.NumSuccess = result.NumSuccess
+	createUserResponse.NumFailed = result.NumFailed
 
 	c.JSON(http.StatusCreated, createUserResponse)
 }
@@ -0,0 +1,91 @@
+package enrolment
+
+import (
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ntu-onemdp/onemdp-backend/internal/api/middlewares"
+	"github.com/ntu-onemdp/onemdp-backend/internal/models"
+	"github.com/ntu-onemdp/onemdp-backend/internal/semester"
+	"github.com/ntu-onemdp/onemdp-backend/internal/services"
+	"github.com/ntu-onemdp/onemdp-backend/internal/utils"
+)
+
+type createCodeRequest struct {
+	Role          string     `json:"role"` // Defaults to student
+	TutorialGroup *string    `json:"tutorial_group"`
+	MaxUses       *int       `json:"max_uses" binding:"omitempty,min=1"`
+	ExpiresAt     *time.Time `json:"expires_at"`
+}
+
+// Enrolment codes cannot grant roles with these permissions, as anyone holding the code could use them
+var restrictedPermissions = []models.Permission{models.PermUsersManage, models.PermRolesManage, models.PermApiKeysManage}
+
+// Create a new enrolment code for the current semester
+func CreateCodeHandler(c *gin.Context) {
+	utils.Logger.Info().Msg("Create enrolment code request received")
+
+	principal := middlewares.GetPrincipal(c)
+	if principal == nil {
+		return
+	}
+
+	var req createCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+			"message": "Error binding create enrolment code request",
+		})
+		return
+	}
+
+	if req.Role == "" {
+		req.Role = models.RoleStudent
+	}
+
+	if !isGrantable(principal, req.Role) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Invalid role",
+			"message": "Enrolment codes cannot grant administrative roles or roles with permissions you do not have",
+		})
+		return
+	}
+
+	if req.ExpiresAt != nil && req.ExpiresAt.Before(time.Now()) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Invalid expiry",
+			"message": "Expiry must be in the future",
+		})
+		return
+	}
+
+	code, err := semester.Service.CreateCode(req.Role, req.TutorialGroup, req.MaxUses, req.ExpiresAt, principal.Uid)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   err.Error(),
+			"message": "Error creating enrolment code",
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"message": "Enrolment code created successfully",
+		"code":    code,
+	})
+}
+
+func isGrantable(p *models.Principal, role string) bool {
+	r, err := services.Roles.CheckGrantable(p, role)
+	if err != nil {
+		return false
+	}
+
+	return !slices.ContainsFunc(restrictedPermissions, r.Can)
+}
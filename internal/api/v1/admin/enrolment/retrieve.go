@@ -0,0 +1,51 @@
+package enrolment
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ntu-onemdp/onemdp-backend/internal/semester"
+	"github.com/ntu-onemdp/onemdp-backend/internal/utils"
+)
+
+// List enrolment codes of a semester. Defaults to the current semester.
+func GetCodesHandler(c *gin.Context) {
+	sem := c.DefaultQuery("semester", "")
+	utils.Logger.Info().Str("semester", sem).Msg("Get enrolment codes request received")
+
+	codes, err := semester.Service.GetCodes(sem)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   err.Error(),
+			"message": "Error retrieving enrolment codes",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"codes":   codes,
+	})
+}
+
+// Audit the users who registered with a code
+func GetRedemptionsHandler(c *gin.Context) {
+	codeID := c.Param("code_id")
+	utils.Logger.Info().Str("code id", codeID).Msg("Get enrolment code redemptions request received")
+
+	redemptions, err := semester.Service.GetRedemptions(codeID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   err.Error(),
+			"message": "Error retrieving redemptions",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":     true,
+		"redemptions": redemptions,
+	})
+}
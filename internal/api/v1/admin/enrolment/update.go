@@ -0,0 +1,48 @@
+package enrolment
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/ntu-onemdp/onemdp-backend/internal/semester"
+	"github.com/ntu-onemdp/onemdp-backend/internal/utils"
+)
+
+// Disable an enrolment code. Users can no longer register with it.
+func DisableCodeHandler(c *gin.Context) {
+	setDisabled(c, true)
+}
+
+// Re-enable a disabled enrolment code
+func EnableCodeHandler(c *gin.Context) {
+	setDisabled(c, false)
+}
+
+func setDisabled(c *gin.Context, disabled bool) {
+	codeID := c.Param("code_id")
+	utils.Logger.Info().Str("code id", codeID).Bool("disabled", disabled).Msg("Update enrolment code request received")
+
+	if err := semester.Service.SetCodeDisabled(codeID, disabled); err != nil {
+		if err == pgx.ErrNoRows {
+			c.JSON(http.StatusNotFound, gin.H{
+				"success": false,
+				"error":   err.Error(),
+				"message": "Enrolment code not found",
+			})
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   err.Error(),
+			"message": "Error updating enrolment code",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Enrolment code updated successfully",
+	})
+}
@@ -0,0 +1,86 @@
+package admin
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ntu-onemdp/onemdp-backend/internal/api/middlewares"
+	"github.com/ntu-onemdp/onemdp-backend/internal/services"
+	"github.com/ntu-onemdp/onemdp-backend/internal/utils"
+)
+
+// Default and maximum number of audit entries returned
+const (
+	defaultImpersonationAuditLimit = 100
+	maxImpersonationAuditLimit     = 1000
+)
+
+type impersonateRequest struct {
+	Reason      string `json:"reason" binding:"required"`
+	AllowWrites bool   `json:"allow_writes"` // Token is read-only unless set
+}
+
+// Issue a short-lived token to view the app as another user. Every request made with it is audited.
+func ImpersonateHandler(c *gin.Context) {
+	uid := c.Param("uid")
+	utils.Logger.Info().Str("uid", uid).Msg("Impersonate user request received")
+
+	principal := middlewares.GetPrincipal(c)
+	if principal == nil {
+		return
+	}
+
+	var req impersonateRequest
+	if !bindStatusRequest(c, &req) {
+		return
+	}
+
+	token, expiresAt, err := services.Impersonation.Start(principal, uid, req.AllowWrites, req.Reason, c.ClientIP(), c.Request.URL.Path)
+	if err != nil {
+		utils.Logger.Warn().Err(err).Str("uid", uid).Str("by", principal.Uid).Msg("Impersonation refused")
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success":      true,
+		"access_token": token,
+		"expires_at":   expiresAt,
+		"read_only":    !req.AllowWrites,
+	})
+}
+
+// List recent impersonation activity. Filter with ?impersonator= and ?target=
+func GetImpersonationAuditHandler(c *gin.Context) {
+	impersonator := c.DefaultQuery("impersonator", "")
+	target := c.DefaultQuery("target", "")
+	utils.Logger.Info().Str("impersonator", impersonator).Str("target", target).Msg("Get impersonation audit request received")
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(defaultImpersonationAuditLimit)))
+	if err != nil || limit <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Invalid limit",
+		})
+		return
+	}
+	limit = min(limit, maxImpersonationAuditLimit)
+
+	entries, err := services.Impersonation.GetRecent(impersonator, target, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "Error retrieving impersonation audit",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"entries": entries,
+	})
+}
@@ -0,0 +1,60 @@
+package admin
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ntu-onemdp/onemdp-backend/internal/api/middlewares"
+	"github.com/ntu-onemdp/onemdp-backend/internal/services"
+	"github.com/ntu-onemdp/onemdp-backend/internal/utils"
+)
+
+// Add pending users from an uploaded CSV or XLSX roster.
+// Columns: email (required), name, role, semester, tutorial_group. Set dry_run=true to preview results without saving.
+func ImportUsersHandler(c *gin.Context) {
+	dryRun := c.Query("dry_run") == "true"
+	utils.Logger.Info().Bool("dry run", dryRun).Msg("Import users request received")
+
+	principal := middlewares.GetPrincipal(c)
+	if principal == nil {
+		return
+	}
+
+	file, err := c.FormFile("file")
+	if err != nil {
+		utils.Logger.Error().Err(err).Msg("Error retrieving file from request")
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "No file uploaded",
+		})
+		return
+	}
+
+	rows, err := services.Users.ParseRoster(file)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	result, err := services.Users.ImportRoster(rows, dryRun, principal)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "Error importing users",
+		})
+		return
+	}
+
+	status := http.StatusCreated
+	if dryRun {
+		status = http.StatusOK
+	}
+
+	c.JSON(status, gin.H{
+		"success": true,
+		"data":    result,
+	})
+}
@@ -0,0 +1,77 @@
+package admin
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/ntu-onemdp/onemdp-backend/internal/models"
+	"github.com/ntu-onemdp/onemdp-backend/internal/services"
+	"github.com/ntu-onemdp/onemdp-backend/internal/utils"
+)
+
+type removeUserRequest struct {
+	Policy     string `json:"policy" binding:"required,oneof=anonymise reassign"`
+	ReassignTo string `json:"reassign_to"` // Required if policy is reassign
+	Reason     string `json:"reason"`
+}
+
+// Remove a user and erase their personal data. Content is anonymised or reassigned according to the policy.
+// Export the user's data first if it is needed, as removal cannot be undone.
+func RemoveUserHandler(c *gin.Context) {
+	uid := c.Param("uid")
+	utils.Logger.Info().Str("uid", uid).Msg("Remove user request received")
+
+	var req removeUserRequest
+	if !bindStatusRequest(c, &req) {
+		return
+	}
+
+	if req.Reason == "" {
+		req.Reason = "Removed by admin"
+	}
+
+	updateStatus(c, uid, models.StatusRemoved, func(p *models.Principal) error {
+		return services.Users.Remove(uid, req.Policy, req.ReassignTo, req.Reason, p)
+	})
+}
+
+// Download everything stored about a user as a JSON file
+func ExportUserHandler(c *gin.Context) {
+	uid := c.Param("uid")
+	utils.Logger.Info().Str("uid", uid).Msg("Export user data request received")
+
+	export, err := services.Users.Export(uid)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			c.JSON(http.StatusNotFound, gin.H{
+				"success": false,
+				"error":   "User not found",
+			})
+			return
+		}
+
+		utils.Logger.Error().Err(err).Str("uid", uid).Msg("Error exporting user data")
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "Error exporting user data",
+		})
+		return
+	}
+
+	data, err := json.MarshalIndent(export, "", "  ")
+	if err != nil {
+		utils.Logger.Error().Err(err).Str("uid", uid).Msg("Error encoding user data")
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "Error exporting user data",
+		})
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="user-%s-%s.json"`, uid, export.ExportedAt.Format("20060102")))
+	c.Data(http.StatusOK, "application/json", data)
+}
@@ -0,0 +1,96 @@
+package admin
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/ntu-onemdp/onemdp-backend/internal/models"
+	"github.com/ntu-onemdp/onemdp-backend/internal/services"
+	"github.com/ntu-onemdp/onemdp-backend/internal/utils"
+)
+
+type saveRoleRequest struct {
+	Description string              `json:"description"`
+	Permissions []models.Permission `json:"permissions" binding:"required"`
+}
+
+// List all roles with their permissions, and all permissions that can be granted
+func GetRolesHandler(c *gin.Context) {
+	utils.Logger.Info().Msg("Get roles request received")
+
+	roles, err := services.Roles.GetAll()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "Error retrieving roles",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":     true,
+		"roles":       roles,
+		"permissions": models.AllPermissions,
+	})
+}
+
+// Create a role, or replace the description and permissions of an existing role
+func SaveRoleHandler(c *gin.Context) {
+	role := c.Param("role")
+	utils.Logger.Info().Str("role", role).Msg("Save role request received")
+
+	var req saveRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.Logger.Error().Err(err).Msg("Error binding save role request")
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Malformed request",
+		})
+		return
+	}
+
+	saved, err := services.Roles.Save(role, req.Description, req.Permissions)
+	if err != nil {
+		utils.Logger.Warn().Err(err).Str("role", role).Msg("Error saving role")
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Role saved",
+		"role":    saved,
+	})
+}
+
+// Delete a role. Built-in roles and roles still held by users cannot be deleted.
+func DeleteRoleHandler(c *gin.Context) {
+	role := c.Param("role")
+	utils.Logger.Info().Str("role", role).Msg("Delete role request received")
+
+	if err := services.Roles.Delete(role); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			c.JSON(http.StatusNotFound, gin.H{
+				"success": false,
+				"error":   "Role not found or is built in",
+			})
+			return
+		}
+
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Role deleted",
+	})
+}
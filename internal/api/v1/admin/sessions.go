@@ -0,0 +1,52 @@
+package admin
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ntu-onemdp/onemdp-backend/internal/services"
+	"github.com/ntu-onemdp/onemdp-backend/internal/utils"
+)
+
+// List all sessions of a user
+func GetSessionsHandler(c *gin.Context) {
+	uid := c.Param("uid")
+	utils.Logger.Info().Str("uid", uid).Msg("Get sessions request received")
+
+	sessions, err := services.Sessions.GetSessions(uid)
+	if err != nil {
+		utils.Logger.Error().Err(err).Msg("Error retrieving sessions")
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "Error retrieving sessions",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":  true,
+		"sessions": sessions,
+	})
+}
+
+// Revoke all sessions of a user. Takes effect on the user's next request.
+func RevokeSessionsHandler(c *gin.Context) {
+	uid := c.Param("uid")
+	utils.Logger.Info().Str("uid", uid).Msg("Revoke all sessions request received")
+
+	numRevoked, err := services.Sessions.RevokeAll(uid)
+	if err != nil {
+		utils.Logger.Error().Err(err).Msg("Error revoking sessions")
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "Error revoking sessions",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":     true,
+		"message":     "All sessions revoked",
+		"num_revoked": numRevoked,
+	})
+}
@@ -1,9 +1,11 @@
 package admin
 
 import (
+	"errors"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+	"github.com/ntu-onemdp/onemdp-backend/internal/api/middlewares"
 	"github.com/ntu-onemdp/onemdp-backend/internal/services"
 	"github.com/ntu-onemdp/onemdp-backend/internal/utils"
 )
@@ -17,6 +19,11 @@ type UpdateUserRoleRequest struct {
 func UpdateRoleHandler(c *gin.Context) {
 	utils.Logger.Info().Msg("Update user role request received")
 
+	principal := middlewares.GetPrincipal(c)
+	if principal == nil {
+		return
+	}
+
 	// Parse request
 	var updateUserRoleRequest UpdateUserRoleRequest
 	if err := c.BindJSON(&updateUserRoleRequest); err != nil {
@@ -27,10 +34,17 @@ func UpdateRoleHandler(c *gin.Context) {
 
 	utils.Logger.Trace().Interface("updateUserRoleRequest", updateUserRoleRequest).Msg("Parsed request")
 
-	err := services.Users.UpdateRole(updateUserRoleRequest.Uid, updateUserRoleRequest.Role)
+	err := services.Users.UpdateRole(updateUserRoleRequest.Uid, updateUserRoleRequest.Role, principal)
 	if err != nil {
 		utils.Logger.Error().Err(err).Msg("Error encountered when updating user role")
-		c.JSON(http.StatusInternalServerError, nil)
+		switch {
+		case errors.Is(err, services.ErrRoleNotHeld):
+			c.JSON(http.StatusForbidden, gin.H{"success": false, "error": err.Error()})
+		case errors.Is(err, services.ErrRoleNotAssignable):
+			c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, nil)
+		}
 		return
 	}
 
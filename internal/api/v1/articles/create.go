@@ -4,6 +4,7 @@ import (
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+	"github.com/ntu-onemdp/onemdp-backend/internal/api/middlewares"
 	"github.com/ntu-onemdp/onemdp-backend/internal/services"
 	"github.com/ntu-onemdp/onemdp-backend/internal/utils"
 )
@@ -26,8 +27,12 @@ func CreateArticleHandler(c *gin.Context) {
 		return
 	}
 
-	// Get author uid from JWT token
-	author := services.JwtHandler.GetUidFromJwt(c)
+	// Get authenticated user
+	principal := middlewares.GetPrincipal(c)
+	if principal == nil {
+		return
+	}
+	author := principal.Uid
 	utils.Logger.Info().Msg("New article request received from " + author)
 
 	id, err := services.Articles.CreateNewArticle(author, createArticleRequest.Title, createArticleRequest.Content)
@@ -4,6 +4,7 @@ import (
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+	"github.com/ntu-onemdp/onemdp-backend/internal/api/middlewares"
 	"github.com/ntu-onemdp/onemdp-backend/internal/services"
 	"github.com/ntu-onemdp/onemdp-backend/internal/utils"
 )
@@ -11,12 +12,16 @@ import (
 func DeleteArticleHandler(c *gin.Context) {
 	articleId := c.Param("article_id")
 
-	// Get author from JWT token
-	author := services.JwtHandler.GetUidFromJwt(c)
+	// Get authenticated user
+	principal := middlewares.GetPrincipal(c)
+	if principal == nil {
+		return
+	}
+	author := principal.Uid
 
 	utils.Logger.Info().Msg("Delete article request received from " + author)
 
-	err := services.Articles.DeleteArticle(articleId, author)
+	err := services.Articles.DeleteArticle(articleId, principal)
 	if err == utils.NewErrUnauthorized() {
 		utils.Logger.Error().Err(err).Msg("User is student and not author. Unauthorized to delete article")
 		c.JSON(http.StatusUnauthorized, gin.H{
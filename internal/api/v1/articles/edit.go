@@ -0,0 +1,66 @@
+package articles
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/ntu-onemdp/onemdp-backend/internal/api/middlewares"
+	"github.com/ntu-onemdp/onemdp-backend/internal/services"
+	"github.com/ntu-onemdp/onemdp-backend/internal/utils"
+)
+
+// Request to edit an article. The previous version is kept as a revision.
+type EditArticleRequest struct {
+	Title   string `json:"title" binding:"required"`
+	Content string `json:"content" binding:"required"`
+}
+
+func EditArticleHandler(c *gin.Context) {
+	articleID := c.Param("article_id")
+
+	var request EditArticleRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		utils.Logger.Error().Err(err).Msg("Error processing edit article request")
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Malformed request",
+		})
+		return
+	}
+
+	// Get authenticated user
+	principal := middlewares.GetPrincipal(c)
+	if principal == nil {
+		return
+	}
+
+	utils.Logger.Info().Str("article id", articleID).Msg("Edit article request received from " + principal.Uid)
+
+	err := services.Articles.UpdateArticle(articleID, request.Title, request.Content, principal)
+	if err == utils.NewErrUnauthorized() {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"success": false,
+			"error":   "Unauthorized to edit article. You need to be a staff/admin or the original author to edit the article",
+		})
+		return
+	} else if errors.Is(err, pgx.ErrNoRows) {
+		c.JSON(http.StatusNotFound, gin.H{
+			"success": false,
+			"error":   "Article not found",
+		})
+		return
+	} else if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "Error editing article",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Article updated successfully",
+	})
+}
@@ -7,14 +7,19 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/jackc/pgx/v5"
 	constants "github.com/ntu-onemdp/onemdp-backend/config"
+	"github.com/ntu-onemdp/onemdp-backend/internal/api/middlewares"
 	"github.com/ntu-onemdp/onemdp-backend/internal/services"
 	"github.com/ntu-onemdp/onemdp-backend/internal/utils"
 )
 
 // Retrieve all articles
 func GetAllArticlesHandler(c *gin.Context) {
-	// Get user uid from jwt
-	uid := services.JwtHandler.GetUidFromJwt(c)
+	// Get authenticated user
+	principal := middlewares.GetPrincipal(c)
+	if principal == nil {
+		return
+	}
+	uid := principal.Uid
 
 	// Retrieve query params
 	size, err := strconv.Atoi(c.DefaultQuery("size", constants.DEFAULT_PAGE_SIZE))
@@ -70,10 +75,23 @@ func GetAllArticlesHandler(c *gin.Context) {
 func GetOneArticleHandler(c *gin.Context) {
 	articleID := c.Param("article_id")
 
-	// Get uid from jwt
-	uid := services.JwtHandler.GetUidFromJwt(c)
+	// Get authenticated user
+	principal := middlewares.GetPrincipal(c)
+	if principal == nil {
+		return
+	}
+	uid := principal.Uid
+
+	depth, err := strconv.Atoi(c.DefaultQuery("depth", constants.DEFAULT_COMMENT_DEPTH))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
 
-	article, comments, err := services.Articles.GetArticle(articleID, uid)
+	article, comments, err := services.Articles.GetArticle(articleID, uid, depth)
 
 	if err != nil {
 		utils.Logger.Error().Err(err).Msg("Error getting article")
@@ -0,0 +1,128 @@
+package articles
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/ntu-onemdp/onemdp-backend/internal/api/middlewares"
+	"github.com/ntu-onemdp/onemdp-backend/internal/services"
+	"github.com/ntu-onemdp/onemdp-backend/internal/utils"
+)
+
+// GET /api/v1/articles/:article_id/revisions
+func GetRevisionsHandler(c *gin.Context) {
+	articleID := c.Param("article_id")
+
+	revisions, err := services.Articles.GetRevisions(articleID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		c.JSON(http.StatusNotFound, gin.H{
+			"success": false,
+			"error":   "Article not found",
+		})
+		return
+	} else if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "Error retrieving revisions",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    revisions,
+	})
+}
+
+// GET /api/v1/articles/:article_id/diff?from=1&to=0
+// Revision 0 is the current version, and is compared against if to is not given.
+func DiffRevisionsHandler(c *gin.Context) {
+	articleID := c.Param("article_id")
+
+	from, err := strconv.Atoi(c.Query("from"))
+	if err != nil || from < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Invalid from revision",
+		})
+		return
+	}
+	to, err := strconv.Atoi(c.DefaultQuery("to", "0"))
+	if err != nil || to < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Invalid to revision",
+		})
+		return
+	}
+
+	diff, err := services.Articles.DiffRevisions(articleID, from, to)
+	if errors.Is(err, pgx.ErrNoRows) {
+		c.JSON(http.StatusNotFound, gin.H{
+			"success": false,
+			"error":   "Article or revision not found",
+		})
+		return
+	} else if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "Error comparing revisions",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    diff,
+	})
+}
+
+// POST /api/v1/articles/:article_id/revisions/:revision/rollback
+func RollbackArticleHandler(c *gin.Context) {
+	articleID := c.Param("article_id")
+
+	revision, err := strconv.Atoi(c.Param("revision"))
+	if err != nil || revision < 1 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Invalid revision",
+		})
+		return
+	}
+
+	principal := middlewares.GetPrincipal(c)
+	if principal == nil {
+		return
+	}
+
+	utils.Logger.Info().Str("article id", articleID).Int("revision", revision).Msg("Rollback article request received from " + principal.Uid)
+
+	err = services.Articles.RollbackArticle(articleID, revision, principal)
+	if err == utils.NewErrUnauthorized() {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"success": false,
+			"error":   "Unauthorized to roll back article. You need to be a staff/admin to roll back articles",
+		})
+		return
+	} else if errors.Is(err, pgx.ErrNoRows) {
+		c.JSON(http.StatusNotFound, gin.H{
+			"success": false,
+			"error":   "Article or revision not found",
+		})
+		return
+	} else if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "Error rolling back article",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Article rolled back to revision " + strconv.Itoa(revision),
+	})
+}
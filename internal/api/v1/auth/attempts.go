@@ -0,0 +1,65 @@
+package auth
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ntu-onemdp/onemdp-backend/internal/limiter"
+	"github.com/ntu-onemdp/onemdp-backend/internal/services"
+	"github.com/ntu-onemdp/onemdp-backend/internal/utils"
+)
+
+// Endpoints tracked by the limiter
+const (
+	loginEndpoint    = "login"
+	registerEndpoint = "register"
+)
+
+// Limiter keys of the request. The email key is only included if email is known.
+func attemptKeys(c *gin.Context, email string) []string {
+	keys := []string{"ip:" + c.ClientIP()}
+	if email != "" {
+		keys = append(keys, "email:"+strings.ToLower(email))
+	}
+
+	return keys
+}
+
+// Returns true if the request is locked out, in which case a 429 Too Many Requests response has been written.
+func isLockedOut(c *gin.Context, email string) bool {
+	remaining := limiter.Auth.LockedFor(attemptKeys(c, email)...)
+	if remaining <= 0 {
+		return false
+	}
+
+	retryAfter := int(math.Ceil(remaining.Seconds()))
+	utils.Logger.Warn().Str("ip", c.ClientIP()).Int("retry after", retryAfter).Msg("Request rejected, too many failed attempts")
+
+	c.Header("Retry-After", strconv.Itoa(retryAfter))
+	c.JSON(http.StatusTooManyRequests, gin.H{
+		"success":     false,
+		"error":       "Too many failed attempts. Try again later.",
+		"code":        "TOO_MANY_ATTEMPTS",
+		"retry_after": retryAfter,
+	})
+	return true
+}
+
+// Count a failed attempt against the request's ip and email, and record it for admins to review.
+// Reason must never contain secrets such as tokens or codes.
+func recordFailedAttempt(c *gin.Context, endpoint string, email string, reason string) {
+	limiter.Auth.Fail(attemptKeys(c, email)...)
+
+	if err := services.AuthFailures.Record(endpoint, c.ClientIP(), email, reason, c.Request.UserAgent()); err != nil {
+		utils.Logger.Error().Err(err).Msg("Error recording failed attempt")
+	}
+}
+
+// Clear failures of the email after a successful attempt. Failures of the ip are kept,
+// so that a valid account cannot be used to reset the lockout of an ip.
+func recordSuccessfulAttempt(email string) {
+	limiter.Auth.Succeed("email:" + strings.ToLower(email))
+}
@@ -0,0 +1,15 @@
+package auth
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ntu-onemdp/onemdp-backend/internal/services"
+)
+
+// Serve the public keys used to verify jwts issued by this service.
+// Clients should refetch the key set when they encounter an unknown kid.
+func JwksHandler(c *gin.Context) {
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, services.JwtHandler.GetJwks())
+}
@@ -2,6 +2,7 @@ package auth
 
 import (
 	"net/http"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/ntu-onemdp/onemdp-backend/internal/models"
@@ -9,31 +10,50 @@ import (
 	"github.com/ntu-onemdp/onemdp-backend/internal/utils"
 )
 
-// uid + user object retrieved from supabase
+// Signed ID/access token issued by the identity provider (supabase). Identity of the user is taken from the verified token.
+type ssoToken struct {
+	IdToken string `json:"id_token" binding:"required"`
+}
+
+// Identity of a user verified by the identity provider
 type user struct {
-	Uid          string       `json:"uid" binding:"required"`
-	UserMetadata userMetadata `json:"user_metadata" binding:"required"`
+	Uid   string
+	Email string
+	Name  string
 }
 
-type userMetadata struct {
-	Email string `json:"email" binding:"required"`
-	Name  string `json:"full_name" binding:"required"`
+// Verify the SSO token and extract the user's identity from its claims.
+func verifySsoToken(idToken string) (*user, error) {
+	claim, err := services.Sso.Verify(idToken)
+	if err != nil {
+		return nil, err
+	}
+
+	return &user{
+		Uid:   claim.Subject,
+		Email: claim.Email,
+		Name:  claim.UserMetadata.DisplayName(),
+	}, nil
 }
 
 type LoginResponse struct {
-	Success bool                `json:"success"`
-	Error   string              `json:"error"`
-	Jwt     *string             `json:"jwt"`
-	User    *models.UserProfile `json:"user"`
+	Success      bool                  `json:"success"`
+	Error        string                `json:"error"`
+	Code         string                `json:"code,omitempty"` // Set when the account is not active, e.g. ACCOUNT_SUSPENDED
+	Status       *models.AccountStatus `json:"account_status,omitempty"`
+	Jwt          *string               `json:"jwt"`
+	ExpiresAt    *time.Time            `json:"expires_at"`    // Expiry of jwt
+	RefreshToken *string               `json:"refresh_token"` // Exchange at /auth/refresh for a new jwt
+	User         *models.UserProfile   `json:"user"`
 }
 
 // Implemented for SSO. After SSO login, the handler will return the JWT and user profile
 func LoginHandler(c *gin.Context) {
 	utils.Logger.Trace().Msg("Login request received")
-	var user user
+	var req ssoToken
 
 	// Bind with form
-	if err := c.ShouldBindJSON(&user); err != nil {
+	if err := c.ShouldBindJSON(&req); err != nil {
 		utils.Logger.Error().Err(err).Msg("Error processing login request")
 		response := LoginResponse{
 			Success: false,
@@ -44,10 +64,31 @@ func LoginHandler(c *gin.Context) {
 		return
 	}
 
-	utils.Logger.Debug().Str("uid", user.Uid).Interface("user metadata", user.UserMetadata).Msg("Login request binded")
+	if isLockedOut(c, "") {
+		return
+	}
+
+	// Verify identity with identity provider
+	user, err := verifySsoToken(req.IdToken)
+	if err != nil {
+		utils.Logger.Warn().Err(err).Msg("SSO token rejected")
+		recordFailedAttempt(c, loginEndpoint, "", "invalid SSO token")
+		response := LoginResponse{
+			Success: false,
+			Error:   "Invalid SSO token",
+		}
+		c.JSON(http.StatusUnauthorized, &response)
+		return
+	}
+
+	utils.Logger.Debug().Str("uid", user.Uid).Str("email", user.Email).Msg("Login request verified")
+
+	if isLockedOut(c, user.Email) {
+		return
+	}
 
 	// Check if user is pending registration
-	isPending, err := services.Users.IsUserPending(user.UserMetadata.Email)
+	isPending, err := services.Users.IsUserPending(user.Email)
 	if err != nil {
 		utils.Logger.Error().Err(err).Msg("Error checking if user is pending")
 		c.JSON(http.StatusInternalServerError, "Internal server error")
@@ -57,7 +98,7 @@ func LoginHandler(c *gin.Context) {
 	if isPending {
 		utils.Logger.Debug().Msg("User is pending registration")
 
-		if err := services.Users.RegisterUserFromPending(user.Uid, user.UserMetadata.Email, user.UserMetadata.Name); err != nil {
+		if err := services.Users.RegisterUserFromPending(user.Uid, user.Email, user.Name); err != nil {
 			utils.Logger.Error().Err(err).Msg("Error registering user")
 			response := LoginResponse{
 				Success: false,
@@ -68,6 +109,31 @@ func LoginHandler(c *gin.Context) {
 		}
 	}
 
+	// Check that account is active
+	status, err := services.Users.GetAccountStatus(user.Uid)
+	if err != nil {
+		utils.Logger.Debug().Msg("User not found")
+		recordFailedAttempt(c, loginEndpoint, user.Email, "user not registered")
+		response := LoginResponse{
+			Success: false,
+			Error:   "User not registered",
+		}
+		c.JSON(http.StatusNotFound, &response)
+		return
+	}
+
+	if status.Status != models.StatusActive {
+		utils.Logger.Warn().Str("uid", user.Uid).Str("status", status.Status).Msg("Login rejected, account is not active")
+		response := LoginResponse{
+			Success: false,
+			Error:   status.ErrorMessage(),
+			Code:    status.ErrorCode(),
+			Status:  status,
+		}
+		c.JSON(http.StatusForbidden, &response)
+		return
+	}
+
 	// Return user profile
 	profile, err := services.Users.GetProfile(user.Uid)
 	if err != nil {
@@ -80,18 +146,31 @@ func LoginHandler(c *gin.Context) {
 		return
 	}
 
+	// Start new session
+	session, refreshToken, err := services.Sessions.Create(user.Uid, c.Request.UserAgent())
+	if err != nil {
+		utils.Logger.Error().Err(err).Msg("Error creating session")
+		c.JSON(http.StatusInternalServerError, "Internal server error")
+		return
+	}
+
 	// Generate jwt
-	claim := models.NewClaim(user.Uid)
+	claim := models.NewClaim(user.Uid, session.SessionID)
 	jwt, err := services.JwtHandler.GenerateJwt(claim)
 	if err != nil {
-		utils.Logger.Error().Err(err)
+		utils.Logger.Error().Err(err).Msg("Error generating jwt")
 		c.JSON(http.StatusInternalServerError, "Internal server error")
+		return
 	}
 
+	recordSuccessfulAttempt(user.Email)
+
 	response := LoginResponse{
-		Success: true,
-		Jwt:     &jwt,
-		User:    profile,
+		Success:      true,
+		Jwt:          &jwt,
+		ExpiresAt:    &claim.ExpiresAt.Time,
+		RefreshToken: &refreshToken,
+		User:         profile,
 	}
 	c.JSON(http.StatusOK, &response)
 }
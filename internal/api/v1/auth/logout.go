@@ -0,0 +1,43 @@
+package auth
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ntu-onemdp/onemdp-backend/internal/api/middlewares"
+	"github.com/ntu-onemdp/onemdp-backend/internal/models"
+	"github.com/ntu-onemdp/onemdp-backend/internal/services"
+	"github.com/ntu-onemdp/onemdp-backend/internal/utils"
+)
+
+// Revoke the session of the jwt used in the request. Both the jwt and its refresh token stop working immediately.
+func LogoutHandler(c *gin.Context) {
+	principal := middlewares.GetPrincipal(c)
+	if principal == nil {
+		return
+	}
+
+	// Api keys have no session to end, and impersonation tokens share the admin's session
+	if principal.Method != models.AuthJwt || principal.IsImpersonated() {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Only jwt sessions can be logged out",
+		})
+		return
+	}
+
+	if err := services.Sessions.Revoke(principal.SessionID); err != nil {
+		utils.Logger.Error().Err(err).Str("uid", principal.Uid).Msg("Error revoking session")
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "Error logging out",
+		})
+		return
+	}
+
+	utils.Logger.Info().Str("uid", principal.Uid).Msgf("User %s logged out", principal.Uid)
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Logged out successfully",
+	})
+}
@@ -0,0 +1,77 @@
+package auth
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ntu-onemdp/onemdp-backend/internal/models"
+	"github.com/ntu-onemdp/onemdp-backend/internal/services"
+	"github.com/ntu-onemdp/onemdp-backend/internal/utils"
+)
+
+type refreshRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
+
+// Exchange a refresh token for a new jwt. The refresh token is rotated, so the response contains a new refresh token
+// which replaces the one in the request.
+func RefreshTokenHandler(c *gin.Context) {
+	utils.Logger.Trace().Msg("Refresh token request received")
+
+	var req refreshRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.Logger.Error().Err(err).Msg("Error binding refresh token request")
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Malformed request",
+		})
+		return
+	}
+
+	session, refreshToken, err := services.Sessions.Refresh(req.RefreshToken)
+	if err != nil {
+		utils.Logger.Warn().Err(err).Msg("Refresh token rejected")
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"success": false,
+			"error":   "Refresh token is invalid, expired or has been revoked",
+		})
+		return
+	}
+
+	// Account may have been deactivated without its sessions being revoked
+	status, err := services.Users.GetAccountStatus(session.Uid)
+	if err != nil || status.Status != models.StatusActive {
+		utils.Logger.Warn().Err(err).Str("uid", session.Uid).Msg("Refresh rejected, account is not active")
+		_ = services.Sessions.Revoke(session.SessionID)
+
+		response := gin.H{
+			"success": false,
+			"error":   "Your account is not active",
+		}
+		if status != nil {
+			response["error"] = status.ErrorMessage()
+			response["code"] = status.ErrorCode()
+		}
+		c.JSON(http.StatusForbidden, response)
+		return
+	}
+
+	claim := models.NewClaim(session.Uid, session.SessionID)
+	jwt, err := services.JwtHandler.GenerateJwt(claim)
+	if err != nil {
+		utils.Logger.Error().Err(err).Msg("Error generating jwt")
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "Internal server error",
+		})
+		return
+	}
+
+	utils.Logger.Debug().Str("uid", session.Uid).Msg("Access token refreshed")
+	c.JSON(http.StatusOK, gin.H{
+		"success":       true,
+		"jwt":           jwt,
+		"expires_at":    claim.ExpiresAt.Time,
+		"refresh_token": refreshToken,
+	})
+}
@@ -11,7 +11,7 @@ import (
 
 // Register user request from frontend
 type req struct {
-	user
+	ssoToken
 	Code string `json:"code" binding:"required"` // Enrolment code
 }
 
@@ -30,21 +30,47 @@ func RegisterUserHandler(c *gin.Context) {
 		return
 	}
 
-	// Check if code matches
-	if req.Code != semester.Service.GetCode() {
-		utils.Logger.Warn().Str("Provided code", req.Code).Str("Registration code", semester.Service.GetCode()).Msg("Registration code does not match.")
+	if isLockedOut(c, "") {
+		return
+	}
+
+	// Verify identity with identity provider
+	user, err := verifySsoToken(req.IdToken)
+	if err != nil {
+		utils.Logger.Warn().Err(err).Msg("SSO token rejected")
+		recordFailedAttempt(c, registerEndpoint, "", "invalid SSO token")
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"success": false,
+			"message": "Invalid SSO token",
+		})
+		return
+	}
+
+	if isLockedOut(c, user.Email) {
+		return
+	}
+
+	// Claim a use of the code. The use is given back if registration fails.
+	code, err := semester.Service.Claim(req.Code)
+	if err != nil {
+		utils.Logger.Warn().Msg("Enrolment code is invalid, disabled, expired or used up.")
+		recordFailedAttempt(c, registerEndpoint, user.Email, "invalid enrolment code")
 		c.JSON(http.StatusUnauthorized, gin.H{
 			"success": false,
-			"message": "Enrolment code provided is incorrect",
+			"message": "Enrolment code provided is incorrect or no longer valid",
 		})
 		return
 	}
 
-	utils.Logger.Trace().Msg("Code provided is correct. Registering new user.")
+	utils.Logger.Trace().Str("code id", code.CodeID).Msg("Code provided is valid. Registering new user.")
 
 	// Register user
-	if err := services.Users.RegisterUser(req.Uid, req.UserMetadata.Email, req.UserMetadata.Name); err != nil {
+	if err := services.Users.RegisterUserWithCode(user.Uid, user.Email, user.Name, code.Role, code.Semester, code.TutorialGroup); err != nil {
 		utils.Logger.Error().Err(err).Msg("Error encountered when registering user")
+		if err := semester.Service.Release(code); err != nil {
+			utils.Logger.Error().Err(err).Str("code id", code.CodeID).Msg("Error releasing enrolment code")
+		}
+
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"success": false,
 			"message": "Error encountered when registering user",
@@ -53,7 +79,14 @@ func RegisterUserHandler(c *gin.Context) {
 		return
 	}
 
-	utils.Logger.Info().Str("uid", req.Uid).Msgf("User %s successfully registered via enrolment code", req.UserMetadata.Name)
+	// User is registered at this point, so failing to record the redemption is only logged
+	if err := semester.Service.Redeem(code, user.Uid); err != nil {
+		utils.Logger.Error().Err(err).Str("code id", code.CodeID).Str("uid", user.Uid).Msg("Error recording redemption of enrolment code")
+	}
+
+	recordSuccessfulAttempt(user.Email)
+
+	utils.Logger.Info().Str("uid", user.Uid).Msgf("User %s successfully registered via enrolment code", user.Name)
 	c.JSON(http.StatusCreated, gin.H{
 		"success": true,
 		"message": "User successfully registered into the system.",
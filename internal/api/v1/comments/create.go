@@ -4,6 +4,7 @@ import (
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+	"github.com/ntu-onemdp/onemdp-backend/internal/api/middlewares"
 	"github.com/ntu-onemdp/onemdp-backend/internal/services"
 	"github.com/ntu-onemdp/onemdp-backend/internal/utils"
 )
@@ -11,6 +12,7 @@ import (
 // New comment request from the frontend
 type CreateCommentRequest struct {
 	ArticleID string `json:"article_id" binding:"required"`
+	ReplyTo   string `json:"reply_to"` // Parent comment. Blank for top level comments.
 	Content   string `json:"content" binding:"required"`
 }
 
@@ -27,11 +29,21 @@ func CreateCommentHandler(c *gin.Context) {
 		return
 	}
 
-	// Get author uid from jwt token
-	author := services.JwtHandler.GetUidFromJwt(c)
+	// Get authenticated user
+	principal := middlewares.GetPrincipal(c)
+	if principal == nil {
+		return
+	}
+	author := principal.Uid
 	utils.Logger.Info().Msg("New comment request received from " + author)
 
-	id, err := services.Comments.Create(author, request.ArticleID, request.Content)
+	// Check if reply to is blank
+	var replyTo *string
+	if request.ReplyTo != "" {
+		replyTo = &request.ReplyTo
+	}
+
+	id, err := services.Comments.Create(author, request.ArticleID, replyTo, request.Content)
 	if err != nil {
 		utils.Logger.Error().Err(err).Msg("Error creating new comment " + err.Error())
 		c.JSON(http.StatusInternalServerError, gin.H{
@@ -4,6 +4,7 @@ import (
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+	"github.com/ntu-onemdp/onemdp-backend/internal/api/middlewares"
 	"github.com/ntu-onemdp/onemdp-backend/internal/services"
 	"github.com/ntu-onemdp/onemdp-backend/internal/utils"
 )
@@ -13,12 +14,16 @@ func DeleteCommentHandler(c *gin.Context) {
 
 	utils.Logger.Trace().Str("commentID", commentID).Msgf("Delete comment request received for comment %s", commentID)
 
-	// Get author from JWT token
-	author := services.JwtHandler.GetUidFromJwt(c)
+	// Get authenticated user
+	principal := middlewares.GetPrincipal(c)
+	if principal == nil {
+		return
+	}
+	author := principal.Uid
 	utils.Logger.Info().Msgf("Delete commnent request received from %s for comment %s", author, commentID)
 
 	// Delete comment
-	if err := services.Comments.Delete(commentID, author); err != nil {
+	if err := services.Comments.Delete(commentID, principal); err != nil {
 		utils.Logger.Error().Err(err).Msg("Error deleting comment")
 
 		if (err == utils.ErrUnauthorized{}) {
@@ -0,0 +1,68 @@
+package comments
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/ntu-onemdp/onemdp-backend/internal/api/middlewares"
+	"github.com/ntu-onemdp/onemdp-backend/internal/services"
+	"github.com/ntu-onemdp/onemdp-backend/internal/utils"
+)
+
+// Edit comment request from the frontend
+type EditCommentRequest struct {
+	Content string `json:"content" binding:"required"`
+}
+
+func EditCommentHandler(c *gin.Context) {
+	commentID := c.Param("comment_id")
+
+	var request EditCommentRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		utils.Logger.Error().Err(err).Msg("Error processing edit comment request")
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Malformed request. " + err.Error(),
+		})
+		return
+	}
+
+	// Get authenticated user
+	principal := middlewares.GetPrincipal(c)
+	if principal == nil {
+		return
+	}
+	utils.Logger.Info().Msgf("Edit comment request received from %s for comment %s", principal.Uid, commentID)
+
+	if err := services.Comments.Update(commentID, request.Content, principal); err != nil {
+		utils.Logger.Error().Err(err).Msg("Error editing comment")
+
+		if (err == utils.ErrUnauthorized{}) {
+			c.JSON(http.StatusUnauthorized, gin.H{
+				"success": false,
+				"error":   "Unauthorized to edit comment. You need to be a staff/admin or the original author to edit the comment",
+			})
+			return
+		}
+		if errors.Is(err, pgx.ErrNoRows) {
+			c.JSON(http.StatusNotFound, gin.H{
+				"success": false,
+				"error":   "Comment not found",
+			})
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "Error editing comment",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Comment successfully updated",
+	})
+}
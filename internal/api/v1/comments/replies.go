@@ -0,0 +1,52 @@
+package comments
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	constants "github.com/ntu-onemdp/onemdp-backend/config"
+	"github.com/ntu-onemdp/onemdp-backend/internal/api/middlewares"
+	"github.com/ntu-onemdp/onemdp-backend/internal/services"
+)
+
+// Retrieve replies to a comment, e.g. those nested deeper than the depth returned with the article.
+func GetRepliesHandler(c *gin.Context) {
+	commentID := c.Param("comment_id")
+
+	principal := middlewares.GetPrincipal(c)
+	if principal == nil {
+		return
+	}
+
+	depth, err := strconv.Atoi(c.DefaultQuery("depth", constants.DEFAULT_COMMENT_DEPTH))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	replies, err := services.Comments.GetReplies(commentID, principal.Uid, depth)
+	if errors.Is(err, pgx.ErrNoRows) {
+		c.JSON(http.StatusNotFound, gin.H{
+			"success": false,
+			"error":   "Comment not found",
+		})
+		return
+	} else if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "Error retrieving replies",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"replies": replies,
+	})
+}
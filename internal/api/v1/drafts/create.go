@@ -0,0 +1,61 @@
+package drafts
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ntu-onemdp/onemdp-backend/internal/api/middlewares"
+	"github.com/ntu-onemdp/onemdp-backend/internal/services"
+	"github.com/ntu-onemdp/onemdp-backend/internal/utils"
+)
+
+// Request to create a new draft. TargetID is the thread of a post draft or the article of a comment draft.
+type CreateDraftRequest struct {
+	TargetType string `json:"target_type" binding:"required,oneof=thread post article comment"`
+	TargetID   string `json:"target_id"`
+	ReplyTo    string `json:"reply_to"`
+	Title      string `json:"title"`
+	Content    string `json:"content"`
+	IsAnon     bool   `json:"is_anon"`
+}
+
+func CreateDraftHandler(c *gin.Context) {
+	var request CreateDraftRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		utils.Logger.Error().Err(err).Msg("Error processing new draft request")
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Malformed request",
+		})
+		return
+	}
+
+	// Get authenticated user
+	principal := middlewares.GetPrincipal(c)
+	if principal == nil {
+		return
+	}
+
+	draft, err := services.Drafts.Create(principal.Uid, request.TargetType, optional(request.TargetID), optional(request.ReplyTo), request.Title, request.Content, request.IsAnon)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Error creating draft: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"message": "Draft created successfully",
+		"data":    draft,
+	})
+}
+
+// Returns nil for blank strings
+func optional(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
@@ -0,0 +1,40 @@
+package drafts
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/ntu-onemdp/onemdp-backend/internal/api/middlewares"
+	"github.com/ntu-onemdp/onemdp-backend/internal/services"
+)
+
+// DELETE /api/v1/drafts/:draft_id
+func DeleteDraftHandler(c *gin.Context) {
+	principal := middlewares.GetPrincipal(c)
+	if principal == nil {
+		return
+	}
+
+	if err := services.Drafts.Delete(c.Param("draft_id"), principal.Uid); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			c.JSON(http.StatusNotFound, gin.H{
+				"success": false,
+				"error":   "Draft not found",
+			})
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "Error deleting draft",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Draft deleted",
+	})
+}
@@ -0,0 +1,63 @@
+package drafts
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/ntu-onemdp/onemdp-backend/internal/api/middlewares"
+	"github.com/ntu-onemdp/onemdp-backend/internal/services"
+)
+
+// GET /api/v1/drafts?type=thread
+func GetDraftsHandler(c *gin.Context) {
+	principal := middlewares.GetPrincipal(c)
+	if principal == nil {
+		return
+	}
+
+	drafts, err := services.Drafts.GetAll(principal.Uid, c.Query("type"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Error retrieving drafts: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    drafts,
+	})
+}
+
+// GET /api/v1/drafts/:draft_id
+func GetDraftHandler(c *gin.Context) {
+	principal := middlewares.GetPrincipal(c)
+	if principal == nil {
+		return
+	}
+
+	draft, err := services.Drafts.Get(c.Param("draft_id"), principal.Uid)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			c.JSON(http.StatusNotFound, gin.H{
+				"success": false,
+				"error":   "Draft not found",
+			})
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "Error retrieving draft",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    draft,
+	})
+}
@@ -0,0 +1,47 @@
+package drafts
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/ntu-onemdp/onemdp-backend/internal/api/middlewares"
+	"github.com/ntu-onemdp/onemdp-backend/internal/services"
+	"github.com/ntu-onemdp/onemdp-backend/internal/utils"
+)
+
+// POST /api/v1/drafts/:draft_id/publish
+// Creates the thread, post, article or comment and deletes the draft.
+func PublishDraftHandler(c *gin.Context) {
+	principal := middlewares.GetPrincipal(c)
+	if principal == nil {
+		return
+	}
+
+	draftID := c.Param("draft_id")
+	utils.Logger.Info().Str("draft id", draftID).Msg("Publish draft request received from " + principal.Uid)
+
+	id, err := services.Drafts.Publish(draftID, principal.Uid)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			c.JSON(http.StatusNotFound, gin.H{
+				"success": false,
+				"error":   "Draft not found",
+			})
+			return
+		}
+
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Error publishing draft: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success":    true,
+		"message":    "Draft published successfully",
+		"content_id": id,
+	})
+}
@@ -0,0 +1,72 @@
+package drafts
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/ntu-onemdp/onemdp-backend/internal/api/middlewares"
+	"github.com/ntu-onemdp/onemdp-backend/internal/services"
+	"github.com/ntu-onemdp/onemdp-backend/internal/utils"
+)
+
+// Autosave request. LastEdited is when the edit was made on the client and defaults to now.
+type SaveDraftRequest struct {
+	Title      string     `json:"title"`
+	Content    string     `json:"content"`
+	IsAnon     bool       `json:"is_anon"`
+	LastEdited *time.Time `json:"last_edited"`
+}
+
+// PUT /api/v1/drafts/:draft_id
+// Responds with 409 and the stored draft if it has been saved with a later edit.
+func SaveDraftHandler(c *gin.Context) {
+	var request SaveDraftRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		utils.Logger.Error().Err(err).Msg("Error processing save draft request")
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Malformed request",
+		})
+		return
+	}
+
+	principal := middlewares.GetPrincipal(c)
+	if principal == nil {
+		return
+	}
+
+	draft, saved, err := services.Drafts.Save(c.Param("draft_id"), principal.Uid, request.Title, request.Content, request.IsAnon, request.LastEdited)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			c.JSON(http.StatusNotFound, gin.H{
+				"success": false,
+				"error":   "Draft not found",
+			})
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "Error saving draft",
+		})
+		return
+	}
+
+	if !saved {
+		c.JSON(http.StatusConflict, gin.H{
+			"success": false,
+			"error":   "Draft has been saved with a later edit",
+			"data":    draft,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Draft saved",
+		"data":    draft,
+	})
+}
@@ -4,14 +4,19 @@ import (
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+	"github.com/ntu-onemdp/onemdp-backend/internal/api/middlewares"
 	"github.com/ntu-onemdp/onemdp-backend/internal/services"
 	"github.com/ntu-onemdp/onemdp-backend/internal/utils"
 )
 
 // Retrieve list of saved threads
 func GetSavedHandler(c *gin.Context) {
-	// Get user uid from JWT token
-	uid := services.JwtHandler.GetUidFromJwt(c)
+	// Get authenticated user
+	principal := middlewares.GetPrincipal(c)
+	if principal == nil {
+		return
+	}
+	uid := principal.Uid
 
 	// Get content type
 	contentType := c.DefaultQuery("content-type", "threads")
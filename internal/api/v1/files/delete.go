@@ -6,13 +6,18 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/jackc/pgx/v5"
+	"github.com/ntu-onemdp/onemdp-backend/internal/api/middlewares"
 	"github.com/ntu-onemdp/onemdp-backend/internal/services"
 	"github.com/ntu-onemdp/onemdp-backend/internal/utils"
 )
 
 func DeleteFileHandler(c *gin.Context) {
 	// Retrieve params
-	uid := services.JwtHandler.GetUidFromJwt(c)
+	principal := middlewares.GetPrincipal(c)
+	if principal == nil {
+		return
+	}
+	uid := principal.Uid
 	fileID := c.Param("file_id")
 
 	utils.Logger.Info().Str("uid", uid).Str("file ID", fileID).Msgf("Delete file request received from %s for file %s", uid, fileID)
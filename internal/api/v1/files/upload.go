@@ -4,6 +4,7 @@ import (
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+	"github.com/ntu-onemdp/onemdp-backend/internal/api/middlewares"
 	"github.com/ntu-onemdp/onemdp-backend/internal/services"
 	"github.com/ntu-onemdp/onemdp-backend/internal/utils"
 )
@@ -11,7 +12,11 @@ import (
 func UploadFileHandler(c *gin.Context) {
 	utils.Logger.Debug().Msg("Received request to upload file")
 
-	author := services.JwtHandler.GetUidFromJwt(c)
+	principal := middlewares.GetPrincipal(c)
+	if principal == nil {
+		return
+	}
+	author := principal.Uid
 
 	form, err := c.MultipartForm()
 	if err != nil {
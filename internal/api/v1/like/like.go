@@ -4,14 +4,19 @@ import (
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+	"github.com/ntu-onemdp/onemdp-backend/internal/api/middlewares"
 	"github.com/ntu-onemdp/onemdp-backend/internal/services"
 	"github.com/ntu-onemdp/onemdp-backend/internal/utils"
 )
 
 // Handle like content request
 func LikeContentHandler(c *gin.Context) {
-	// Get user uid from JWT token
-	uid := services.JwtHandler.GetUidFromJwt(c)
+	// Get authenticated user
+	principal := middlewares.GetPrincipal(c)
+	if principal == nil {
+		return
+	}
+	uid := principal.Uid
 
 	// Check if content id is valid and get content ID.
 	contentID := services.GetContentID(c)
@@ -46,7 +51,11 @@ func LikeContentHandler(c *gin.Context) {
 
 // Handle unlike content request
 func UnlikeContentHandler(c *gin.Context) {
-	uid := services.JwtHandler.GetUidFromJwt(c)
+	principal := middlewares.GetPrincipal(c)
+	if principal == nil {
+		return
+	}
+	uid := principal.Uid
 
 	// Check content exists and get content ID
 	contentID := services.GetContentID(c)
@@ -0,0 +1,84 @@
+package notifications
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	constants "github.com/ntu-onemdp/onemdp-backend/config"
+	"github.com/ntu-onemdp/onemdp-backend/internal/api/middlewares"
+	"github.com/ntu-onemdp/onemdp-backend/internal/notification"
+	"github.com/ntu-onemdp/onemdp-backend/internal/utils"
+)
+
+// GET /api/v1/notifications?page=&size=&unread=true
+func GetNotificationsHandler(c *gin.Context) {
+	principal := middlewares.GetPrincipal(c)
+	if principal == nil {
+		return
+	}
+
+	size, err := strconv.Atoi(c.DefaultQuery("size", constants.DEFAULT_PAGE_SIZE))
+	if err != nil || size <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Invalid page size",
+		})
+		return
+	}
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if err != nil || page <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Invalid page",
+		})
+		return
+	}
+	unreadOnly, err := strconv.ParseBool(c.DefaultQuery("unread", "false"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Unread must be true or false",
+		})
+		return
+	}
+
+	utils.Logger.Debug().Int("page", page).Int("size", size).Bool("unread", unreadOnly).Msgf("Request received from %s to view notifications", principal.Uid)
+
+	notifications, metadata, err := notification.Service.GetAll(principal.Uid, unreadOnly, page, size)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "Error retrieving notifications",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":  true,
+		"data":     notifications,
+		"metadata": metadata,
+	})
+}
+
+// GET /api/v1/notifications/unread-count
+func GetUnreadCountHandler(c *gin.Context) {
+	principal := middlewares.GetPrincipal(c)
+	if principal == nil {
+		return
+	}
+
+	unread, err := notification.Service.CountUnread(principal.Uid)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "Error counting unread notifications",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    gin.H{"unread": unread},
+	})
+}
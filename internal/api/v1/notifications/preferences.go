@@ -0,0 +1,78 @@
+package notifications
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ntu-onemdp/onemdp-backend/internal/api/middlewares"
+	"github.com/ntu-onemdp/onemdp-backend/internal/notification"
+	"github.com/ntu-onemdp/onemdp-backend/internal/utils"
+)
+
+type updatePreferencesRequest struct {
+	Preferences []notification.Preference `json:"preferences" binding:"required"`
+}
+
+// GET /api/v1/notifications/preferences
+func GetPreferencesHandler(c *gin.Context) {
+	principal := middlewares.GetPrincipal(c)
+	if principal == nil {
+		return
+	}
+
+	preferences, err := notification.Service.GetPreferences(principal.Uid)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "Error retrieving notification preferences",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    preferences,
+	})
+}
+
+// PUT /api/v1/notifications/preferences
+func UpdatePreferencesHandler(c *gin.Context) {
+	principal := middlewares.GetPrincipal(c)
+	if principal == nil {
+		return
+	}
+
+	var req updatePreferencesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.Logger.Error().Err(err).Msg("Error binding notification preferences request")
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Preferences are required",
+		})
+		return
+	}
+
+	preferences, err := notification.Service.UpdatePreferences(principal.Uid, req.Preferences)
+	if err != nil {
+		if errors.Is(err, notification.ErrInvalidType) || errors.Is(err, notification.ErrNotMutable) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"success": false,
+				"error":   err.Error(),
+			})
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "Error updating notification preferences",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Notification preferences updated",
+		"data":    preferences,
+	})
+}
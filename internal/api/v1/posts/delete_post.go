@@ -4,6 +4,7 @@ import (
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+	"github.com/ntu-onemdp/onemdp-backend/internal/api/middlewares"
 	"github.com/ntu-onemdp/onemdp-backend/internal/services"
 	"github.com/ntu-onemdp/onemdp-backend/internal/utils"
 )
@@ -14,12 +15,16 @@ func DeletePostsHandler(c *gin.Context) {
 	// For debugging purposes
 	utils.Logger.Info().Str("postID", postID).Msg("Delete post request received")
 
-	// Get author from JWT token
-	author := services.JwtHandler.GetUidFromJwt(c)
+	// Get authenticated user
+	principal := middlewares.GetPrincipal(c)
+	if principal == nil {
+		return
+	}
+	author := principal.Uid
 	utils.Logger.Info().Msg("Delete post request received from " + author)
 
 	// Delete post
-	err := services.Posts.DeletePost(postID, author)
+	err := services.Posts.DeletePost(postID, principal)
 	if err != nil {
 		utils.Logger.Error().Err(err).Msg("Error deleting post")
 
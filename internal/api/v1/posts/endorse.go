@@ -0,0 +1,69 @@
+package posts
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/ntu-onemdp/onemdp-backend/internal/api/middlewares"
+	"github.com/ntu-onemdp/onemdp-backend/internal/services"
+	"github.com/ntu-onemdp/onemdp-backend/internal/utils"
+)
+
+// POST /api/v1/staff/endorsements/posts/:post_id
+func EndorsePostHandler(c *gin.Context) {
+	principal := middlewares.GetPrincipal(c)
+	if principal == nil {
+		return
+	}
+	postID := c.Param("post_id")
+
+	utils.Logger.Info().Str("post id", postID).Msgf("Endorse post request received from %s", principal.Uid)
+
+	if err := services.Posts.Endorse(postID, principal); err != nil {
+		handleEndorseError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Post endorsed",
+	})
+}
+
+// DELETE /api/v1/staff/endorsements/posts/:post_id
+func UnendorsePostHandler(c *gin.Context) {
+	principal := middlewares.GetPrincipal(c)
+	if principal == nil {
+		return
+	}
+	postID := c.Param("post_id")
+
+	utils.Logger.Info().Str("post id", postID).Msgf("Unendorse post request received from %s", principal.Uid)
+
+	if err := services.Posts.Unendorse(postID); err != nil {
+		handleEndorseError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Post endorsement removed",
+	})
+}
+
+func handleEndorseError(c *gin.Context, err error) {
+	if errors.Is(err, pgx.ErrNoRows) {
+		c.JSON(http.StatusNotFound, gin.H{
+			"success": false,
+			"error":   "Post not found",
+		})
+		return
+	}
+
+	c.JSON(http.StatusInternalServerError, gin.H{
+		"success": false,
+		"error":   "Error updating endorsement",
+	})
+}
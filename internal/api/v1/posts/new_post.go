@@ -1,9 +1,11 @@
 package posts
 
 import (
+	"errors"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+	"github.com/ntu-onemdp/onemdp-backend/internal/api/middlewares"
 	"github.com/ntu-onemdp/onemdp-backend/internal/services"
 	"github.com/ntu-onemdp/onemdp-backend/internal/utils"
 )
@@ -34,8 +36,12 @@ func NewPostHandler(c *gin.Context) {
 	// For debugging purposes
 	utils.Logger.Debug().Interface("newPostRequest", newPostRequest).Msg("New post request")
 
-	// Get author from JWT token
-	author := services.JwtHandler.GetUidFromJwt(c)
+	// Get authenticated user
+	principal := middlewares.GetPrincipal(c)
+	if principal == nil {
+		return
+	}
+	author := principal.Uid
 	utils.Logger.Info().Msg("New post request received from " + author)
 
 	// Check if reply to is blank
@@ -47,7 +53,14 @@ func NewPostHandler(c *gin.Context) {
 	}
 
 	// Create new post
-	err := services.Posts.CreateNewPost(author, replyTo, newPostRequest.ThreadId, newPostRequest.Title, newPostRequest.Content, *newPostRequest.IsAnon)
+	id, err := services.Posts.CreateNewPost(author, replyTo, newPostRequest.ThreadId, newPostRequest.Title, newPostRequest.Content, *newPostRequest.IsAnon)
+	if errors.Is(err, services.ErrThreadLocked) {
+		c.JSON(http.StatusForbidden, gin.H{
+			"success": false,
+			"error":   "Thread is locked and no longer accepts new posts",
+		})
+		return
+	}
 	if err != nil {
 		utils.Logger.Error().Err(err).Msg("Error creating new post")
 		c.JSON(http.StatusInternalServerError, gin.H{
@@ -60,5 +73,6 @@ func NewPostHandler(c *gin.Context) {
 	c.JSON(http.StatusCreated, gin.H{
 		"success": true,
 		"message": "Post created successfully",
+		"post_id": id,
 	})
 }
@@ -0,0 +1,73 @@
+package posts
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/ntu-onemdp/onemdp-backend/internal/services"
+)
+
+// GET /api/v1/staff/posts/:post_id/revisions
+func GetPostRevisionsHandler(c *gin.Context) {
+	postID := c.Param("post_id")
+
+	revisions, err := services.Posts.GetRevisions(postID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "Error retrieving revisions",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    revisions,
+	})
+}
+
+// GET /api/v1/staff/posts/:post_id/diff?from=1&to=0
+// Revision 0 is the current version, and is compared against if to is not given.
+func DiffPostRevisionsHandler(c *gin.Context) {
+	postID := c.Param("post_id")
+
+	from, err := strconv.Atoi(c.Query("from"))
+	if err != nil || from < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Invalid from revision",
+		})
+		return
+	}
+	to, err := strconv.Atoi(c.DefaultQuery("to", "0"))
+	if err != nil || to < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Invalid to revision",
+		})
+		return
+	}
+
+	diff, err := services.Posts.DiffRevisions(postID, from, to)
+	if errors.Is(err, pgx.ErrNoRows) {
+		c.JSON(http.StatusNotFound, gin.H{
+			"success": false,
+			"error":   "Post or revision not found",
+		})
+		return
+	} else if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "Error comparing revisions",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    diff,
+	})
+}
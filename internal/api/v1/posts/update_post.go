@@ -4,6 +4,7 @@ import (
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+	"github.com/ntu-onemdp/onemdp-backend/internal/api/middlewares"
 	"github.com/ntu-onemdp/onemdp-backend/internal/models"
 	"github.com/ntu-onemdp/onemdp-backend/internal/services"
 	"github.com/ntu-onemdp/onemdp-backend/internal/utils"
@@ -34,13 +35,17 @@ func UpdatePostHandler(c *gin.Context) {
 		return
 	}
 
-	// Get author from JWT token
-	author := services.JwtHandler.GetUidFromJwt(c)
+	// Get authenticated user
+	principal := middlewares.GetPrincipal(c)
+	if principal == nil {
+		return
+	}
+	author := principal.Uid
 
 	utils.Logger.Info().Msg("Update post request received from " + author)
 
 	// Update post
-	err := services.Posts.UpdatePost(updatedPost, author)
+	err := services.Posts.UpdatePost(updatedPost, principal)
 	if err != nil {
 		utils.Logger.Error().Err(err).Msg("Error updating post")
 		c.JSON(http.StatusInternalServerError, gin.H{
@@ -53,7 +58,7 @@ func UpdatePostHandler(c *gin.Context) {
 	// Update thread last activity
 	if updatedPost.IsHeader {
 		// Header post: update title, preview, and last activity
-		err = services.Threads.UpdateThread(updatedPost.ThreadId, updatedPost.Title, updatedPost.PostContent, author)
+		err = services.Threads.UpdateThread(updatedPost.ThreadId, updatedPost.Title, updatedPost.PostContent, principal)
 		if err != nil {
 			utils.Logger.Error().Err(err).Msg("Error updating thread")
 			c.JSON(http.StatusInternalServerError, gin.H{
@@ -0,0 +1,132 @@
+package tags
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/ntu-onemdp/onemdp-backend/internal/api/middlewares"
+	"github.com/ntu-onemdp/onemdp-backend/internal/services"
+	"github.com/ntu-onemdp/onemdp-backend/internal/utils"
+)
+
+type TagRequest struct {
+	Name        string `json:"name" binding:"required"`
+	Colour      string `json:"colour"` // Hex colour, e.g. #1e90ff. Grey if empty.
+	Description string `json:"description"`
+}
+
+// POST /api/v1/staff/tags
+func CreateTagHandler(c *gin.Context) {
+	principal := middlewares.GetPrincipal(c)
+	if principal == nil {
+		return
+	}
+
+	var request TagRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		utils.Logger.Error().Err(err).Msg("Error binding create tag request")
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Malformed request",
+		})
+		return
+	}
+
+	utils.Logger.Info().Str("name", request.Name).Msgf("Create tag request received from %s", principal.Uid)
+
+	tag, err := services.Tags.Create(request.Name, request.Colour, request.Description)
+	if err != nil {
+		handleTagError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"message": "Tag created",
+		"data":    tag,
+	})
+}
+
+// PUT /api/v1/staff/tags/:tag_id
+func UpdateTagHandler(c *gin.Context) {
+	principal := middlewares.GetPrincipal(c)
+	if principal == nil {
+		return
+	}
+
+	var request TagRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		utils.Logger.Error().Err(err).Msg("Error binding update tag request")
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Malformed request",
+		})
+		return
+	}
+
+	tagID := c.Param("tag_id")
+	utils.Logger.Info().Str("tag id", tagID).Msgf("Update tag request received from %s", principal.Uid)
+
+	tag, err := services.Tags.Update(tagID, request.Name, request.Colour, request.Description)
+	if err != nil {
+		handleTagError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Tag updated",
+		"data":    tag,
+	})
+}
+
+// DELETE /api/v1/staff/tags/:tag_id
+func DeleteTagHandler(c *gin.Context) {
+	principal := middlewares.GetPrincipal(c)
+	if principal == nil {
+		return
+	}
+
+	tagID := c.Param("tag_id")
+	utils.Logger.Info().Str("tag id", tagID).Msgf("Delete tag request received from %s", principal.Uid)
+
+	if err := services.Tags.Delete(tagID); err != nil {
+		handleTagError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Tag deleted",
+	})
+}
+
+func handleTagError(c *gin.Context, err error) {
+	var pgErr *pgconn.PgError
+
+	switch {
+	case errors.Is(err, pgx.ErrNoRows):
+		c.JSON(http.StatusNotFound, gin.H{
+			"success": false,
+			"error":   "Tag not found",
+		})
+	case errors.As(err, &pgErr) && pgErr.Code == "23505":
+		c.JSON(http.StatusConflict, gin.H{
+			"success": false,
+			"error":   "A tag with this name already exists",
+		})
+	case errors.As(err, &pgErr):
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "Error saving tag",
+		})
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+	}
+}
@@ -0,0 +1,93 @@
+package threads
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/ntu-onemdp/onemdp-backend/internal/api/middlewares"
+	"github.com/ntu-onemdp/onemdp-backend/internal/services"
+	"github.com/ntu-onemdp/onemdp-backend/internal/utils"
+)
+
+type AcceptAnswerRequest struct {
+	PostID string `json:"post_id" binding:"required"`
+}
+
+// POST /api/v1/threads/:thread_id/accept
+func AcceptAnswerHandler(c *gin.Context) {
+	threadID := c.Param("thread_id")
+
+	var request AcceptAnswerRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		utils.Logger.Error().Err(err).Msg("Error binding accept answer request")
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Malformed request",
+		})
+		return
+	}
+
+	// Get authenticated user
+	principal := middlewares.GetPrincipal(c)
+	if principal == nil {
+		return
+	}
+
+	utils.Logger.Info().Str("thread id", threadID).Str("post id", request.PostID).Msgf("Accept answer request received from %s", principal.Uid)
+
+	err := services.Threads.AcceptAnswer(threadID, request.PostID, principal)
+	if err != nil {
+		handleAcceptError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Answer accepted",
+	})
+}
+
+// DELETE /api/v1/threads/:thread_id/accept
+func UnacceptAnswerHandler(c *gin.Context) {
+	threadID := c.Param("thread_id")
+
+	// Get authenticated user
+	principal := middlewares.GetPrincipal(c)
+	if principal == nil {
+		return
+	}
+
+	utils.Logger.Info().Str("thread id", threadID).Msgf("Unaccept answer request received from %s", principal.Uid)
+
+	if err := services.Threads.UnacceptAnswer(threadID, principal); err != nil {
+		handleAcceptError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Accepted answer removed",
+	})
+}
+
+func handleAcceptError(c *gin.Context, err error) {
+	switch {
+	case err == utils.NewErrUnauthorized():
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"success": false,
+			"error":   "Only the author of the thread or a moderator can accept answers",
+		})
+	case errors.Is(err, pgx.ErrNoRows):
+		c.JSON(http.StatusNotFound, gin.H{
+			"success": false,
+			"error":   "Thread not found",
+		})
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Error updating accepted answer: " + err.Error(),
+		})
+	}
+}
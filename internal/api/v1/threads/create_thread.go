@@ -4,15 +4,17 @@ import (
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+	"github.com/ntu-onemdp/onemdp-backend/internal/api/middlewares"
 	"github.com/ntu-onemdp/onemdp-backend/internal/services"
 	"github.com/ntu-onemdp/onemdp-backend/internal/utils"
 )
 
 // Frontend request to create a new thread. Get author from JWT token
 type CreateThreadRequest struct {
-	Title   string `json:"title" binding:"required"`
-	Content string `json:"content" binding:"required"`
-	IsAnon  *bool  `json:"is_anon" binding:"required"` // https://github.com/gin-gonic/gin/issues/814
+	Title   string   `json:"title" binding:"required"`
+	Content string   `json:"content" binding:"required"`
+	IsAnon  *bool    `json:"is_anon" binding:"required"` // https://github.com/gin-gonic/gin/issues/814
+	Tags    []string `json:"tags"`                       // Tag ids, optional
 }
 
 func CreateThreadHandler(c *gin.Context) {
@@ -28,11 +30,23 @@ func CreateThreadHandler(c *gin.Context) {
 		return
 	}
 
-	// Get author uid from JWT token
-	author := services.JwtHandler.GetUidFromJwt(c)
+	// Get authenticated user
+	principal := middlewares.GetPrincipal(c)
+	if principal == nil {
+		return
+	}
+	author := principal.Uid
 	utils.Logger.Info().Msg("New thread request received from " + author)
 
-	id, err := services.Threads.CreateNewThread(author, createThreadRequest.Title, createThreadRequest.Content, *createThreadRequest.IsAnon)
+	if _, err := services.Tags.ValidateThreadTags(createThreadRequest.Tags); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	id, err := services.Threads.CreateNewThread(author, createThreadRequest.Title, createThreadRequest.Content, *createThreadRequest.IsAnon, createThreadRequest.Tags)
 	if err != nil {
 		utils.Logger.Error().Err(err).Msg("Error creating new thread " + err.Error())
 		c.JSON(http.StatusInternalServerError, nil)
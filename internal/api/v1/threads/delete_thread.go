@@ -4,6 +4,7 @@ import (
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+	"github.com/ntu-onemdp/onemdp-backend/internal/api/middlewares"
 	"github.com/ntu-onemdp/onemdp-backend/internal/services"
 	"github.com/ntu-onemdp/onemdp-backend/internal/utils"
 )
@@ -11,12 +12,16 @@ import (
 func DeleteThreadHandler(c *gin.Context) {
 	threadId := c.Param("thread_id")
 
-	// Get author from JWT token
-	author := services.JwtHandler.GetUidFromJwt(c)
+	// Get authenticated user
+	principal := middlewares.GetPrincipal(c)
+	if principal == nil {
+		return
+	}
+	author := principal.Uid
 
 	utils.Logger.Info().Msg("Delete thread request received from " + author)
 
-	err := services.Threads.DeleteThread(threadId, author)
+	err := services.Threads.DeleteThread(threadId, principal)
 	if err == utils.NewErrUnauthorized() {
 		utils.Logger.Error().Err(err).Msg("User is student and not author. Unauthorized to delete thread")
 		c.JSON(http.StatusUnauthorized, gin.H{
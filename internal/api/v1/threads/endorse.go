@@ -0,0 +1,69 @@
+package threads
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/ntu-onemdp/onemdp-backend/internal/api/middlewares"
+	"github.com/ntu-onemdp/onemdp-backend/internal/services"
+	"github.com/ntu-onemdp/onemdp-backend/internal/utils"
+)
+
+// POST /api/v1/staff/endorsements/threads/:thread_id
+func EndorseThreadHandler(c *gin.Context) {
+	principal := middlewares.GetPrincipal(c)
+	if principal == nil {
+		return
+	}
+	threadID := c.Param("thread_id")
+
+	utils.Logger.Info().Str("thread id", threadID).Msgf("Endorse thread request received from %s", principal.Uid)
+
+	if err := services.Threads.Endorse(threadID, principal); err != nil {
+		handleEndorseError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Thread endorsed",
+	})
+}
+
+// DELETE /api/v1/staff/endorsements/threads/:thread_id
+func UnendorseThreadHandler(c *gin.Context) {
+	principal := middlewares.GetPrincipal(c)
+	if principal == nil {
+		return
+	}
+	threadID := c.Param("thread_id")
+
+	utils.Logger.Info().Str("thread id", threadID).Msgf("Unendorse thread request received from %s", principal.Uid)
+
+	if err := services.Threads.Unendorse(threadID); err != nil {
+		handleEndorseError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Thread endorsement removed",
+	})
+}
+
+func handleEndorseError(c *gin.Context, err error) {
+	if errors.Is(err, pgx.ErrNoRows) {
+		c.JSON(http.StatusNotFound, gin.H{
+			"success": false,
+			"error":   "Thread not found",
+		})
+		return
+	}
+
+	c.JSON(http.StatusInternalServerError, gin.H{
+		"success": false,
+		"error":   "Error updating endorsement",
+	})
+}
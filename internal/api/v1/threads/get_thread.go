@@ -1,23 +1,53 @@
 package threads
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/jackc/pgx/v5"
 	constants "github.com/ntu-onemdp/onemdp-backend/config"
+	"github.com/ntu-onemdp/onemdp-backend/internal/api/middlewares"
+	"github.com/ntu-onemdp/onemdp-backend/internal/models"
 	"github.com/ntu-onemdp/onemdp-backend/internal/services"
 	"github.com/ntu-onemdp/onemdp-backend/internal/utils"
 )
 
 // Retrieve all threads in page
 func GetAllThreadsHandler(c *gin.Context) {
-	// Get uid from jwt
-	uid := services.JwtHandler.GetUidFromJwt(c)
-
-	// Retrieve keyword arguments (if any)
-	searchKeyword := c.DefaultQuery("search", "")
+	// Get authenticated user
+	principal := middlewares.GetPrincipal(c)
+	if principal == nil {
+		return
+	}
+	uid := principal.Uid
+
+	// Retrieve keyword arguments and tags (if any)
+	filter := models.ThreadFilter{
+		Search:   c.DefaultQuery("search", ""),
+		Match:    c.DefaultQuery("match", models.TagMatchAny),
+		Status:   c.Query("status"),
+		Endorsed: c.Query("endorsed") == "true",
+	}
+	if tags := c.Query("tags"); tags != "" {
+		filter.Tags = dedupe(strings.Split(tags, ","))
+	}
+	if filter.Match != models.TagMatchAny && filter.Match != models.TagMatchAll {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "match must be any or all",
+		})
+		return
+	}
+	if filter.Status != "" && filter.Status != models.ThreadResolved && filter.Status != models.ThreadUnresolved {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "status must be resolved or unresolved",
+		})
+		return
+	}
 
 	size, err := strconv.Atoi(c.DefaultQuery("size", constants.DEFAULT_PAGE_SIZE))
 	if err != nil {
@@ -43,7 +73,7 @@ func GetAllThreadsHandler(c *gin.Context) {
 
 	utils.Logger.Debug().Int("size", size).Bool("desc", desc).Str("sort", sort).Int("page", page).Msg("Get all threads request received.")
 
-	threads, err := services.Threads.GetThreads(sort, size, desc, page, uid, searchKeyword)
+	threads, err := services.Threads.GetThreads(sort, size, desc, page, uid, filter)
 	if err != nil {
 		utils.Logger.Error().Err(err).Msg("Error getting threads")
 		c.JSON(http.StatusInternalServerError, gin.H{
@@ -54,7 +84,7 @@ func GetAllThreadsHandler(c *gin.Context) {
 		return
 	}
 
-	metadata, err := services.Threads.GetMetadata(searchKeyword)
+	metadata, err := services.Threads.GetMetadata(filter)
 	if err != nil {
 		utils.Logger.Error().Err(err).Msg("Error getting threads metadata")
 		c.JSON(http.StatusInternalServerError, gin.H{
@@ -75,14 +105,28 @@ func GetAllThreadsHandler(c *gin.Context) {
 	})
 }
 
-// Retrieve individual thread
+// Retrieve individual thread. Posts are returned as a flat list in the order they were created by default, in reply order
+// with ?view=threaded, or as a tree with ?view=tree.
 func GetOneThreadHandler(c *gin.Context) {
 	threadId := c.Param("thread_id")
 
-	// Get uid from jwt
-	uid := services.JwtHandler.GetUidFromJwt(c)
+	// Get authenticated user
+	principal := middlewares.GetPrincipal(c)
+	if principal == nil {
+		return
+	}
+	uid := principal.Uid
 
-	thread, posts, err := services.Threads.GetThread(threadId, uid)
+	view := c.DefaultQuery("view", models.PostViewFlat)
+	if view != models.PostViewFlat && view != models.PostViewThreaded && view != models.PostViewTree {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "view must be flat, threaded or tree",
+		})
+		return
+	}
+
+	thread, posts, err := services.Threads.GetThread(threadId, uid, view)
 	if err != nil {
 		utils.Logger.Error().Err(err).Msg("Error getting thread")
 
@@ -101,10 +145,34 @@ func GetOneThreadHandler(c *gin.Context) {
 		return
 	}
 
+	// Poll is nil if the thread has none
+	poll, err := services.Polls.GetResults(threadId, principal)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": "error getting poll",
+		})
+		return
+	}
+
 	c.JSON(http.StatusOK, gin.H{
 		"success":     true,
 		"thread":      thread,
 		"posts":       posts,
-		"num_replies": len(posts) - 1,
+		"num_replies": thread.NumReplies,
+		"poll":        poll,
 	})
 }
+
+// Returns the non-empty values in order with duplicates removed
+func dedupe(values []string) []string {
+	unique := []string{}
+	seen := map[string]bool{}
+	for _, v := range values {
+		if v = strings.TrimSpace(v); v != "" && !seen[v] {
+			seen[v] = true
+			unique = append(unique, v)
+		}
+	}
+	return unique
+}
@@ -0,0 +1,164 @@
+package threads
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/ntu-onemdp/onemdp-backend/internal/api/middlewares"
+	"github.com/ntu-onemdp/onemdp-backend/internal/services"
+	"github.com/ntu-onemdp/onemdp-backend/internal/utils"
+)
+
+type CreatePollRequest struct {
+	Question       string     `json:"question" binding:"required"`
+	Options        []string   `json:"options" binding:"required"`
+	MultipleChoice bool       `json:"multiple_choice"`
+	IsAnon         *bool      `json:"is_anon" binding:"required"` // https://github.com/gin-gonic/gin/issues/814
+	ClosesAt       *time.Time `json:"closes_at"`                  // Never closes if nil
+}
+
+type VoteRequest struct {
+	Options []string `json:"options" binding:"required"` // Option ids
+}
+
+// POST /api/v1/threads/:thread_id/poll
+func CreatePollHandler(c *gin.Context) {
+	threadID := c.Param("thread_id")
+
+	var request CreatePollRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		utils.Logger.Error().Err(err).Msg("Error binding create poll request")
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Malformed request",
+		})
+		return
+	}
+
+	// Get authenticated user
+	principal := middlewares.GetPrincipal(c)
+	if principal == nil {
+		return
+	}
+
+	utils.Logger.Info().Str("thread id", threadID).Msgf("Create poll request received from %s", principal.Uid)
+
+	poll, err := services.Polls.Create(threadID, request.Question, request.Options, request.MultipleChoice, *request.IsAnon, request.ClosesAt, principal)
+	if err != nil {
+		if err == utils.NewErrUnauthorized() {
+			c.JSON(http.StatusUnauthorized, gin.H{
+				"success": false,
+				"error":   "Only the author of the thread or a moderator can add a poll",
+			})
+			return
+		}
+		if errors.Is(err, pgx.ErrNoRows) {
+			c.JSON(http.StatusNotFound, gin.H{
+				"success": false,
+				"error":   "Thread not found",
+			})
+			return
+		}
+
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Error creating poll: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"message": "Poll created",
+		"data":    poll,
+	})
+}
+
+// GET /api/v1/threads/:thread_id/poll
+func GetPollHandler(c *gin.Context) {
+	threadID := c.Param("thread_id")
+
+	// Get authenticated user
+	principal := middlewares.GetPrincipal(c)
+	if principal == nil {
+		return
+	}
+
+	results, err := services.Polls.GetResults(threadID, principal)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			c.JSON(http.StatusNotFound, gin.H{
+				"success": false,
+				"error":   "Thread has no poll",
+			})
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "Error retrieving poll",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    results,
+	})
+}
+
+// POST /api/v1/threads/:thread_id/poll/vote
+func VoteHandler(c *gin.Context) {
+	threadID := c.Param("thread_id")
+
+	var request VoteRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		utils.Logger.Error().Err(err).Msg("Error binding vote request")
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Malformed request",
+		})
+		return
+	}
+
+	// Get authenticated user
+	principal := middlewares.GetPrincipal(c)
+	if principal == nil {
+		return
+	}
+
+	if err := services.Polls.Vote(threadID, request.Options, principal); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			c.JSON(http.StatusNotFound, gin.H{
+				"success": false,
+				"error":   "Thread has no poll",
+			})
+			return
+		}
+
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Error voting: " + err.Error(),
+		})
+		return
+	}
+
+	// Return results, which are now visible to the user
+	results, err := services.Polls.GetResults(threadID, principal)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "Vote recorded but results could not be retrieved",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Vote recorded",
+		"data":    results,
+	})
+}
@@ -0,0 +1,55 @@
+package threads
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/ntu-onemdp/onemdp-backend/internal/api/middlewares"
+	"github.com/ntu-onemdp/onemdp-backend/internal/models"
+	"github.com/ntu-onemdp/onemdp-backend/internal/services"
+	"github.com/ntu-onemdp/onemdp-backend/internal/utils"
+)
+
+// PUT /api/v1/staff/threads/:thread_id/state
+func UpdateStateHandler(c *gin.Context) {
+	principal := middlewares.GetPrincipal(c)
+	if principal == nil {
+		return
+	}
+	threadID := c.Param("thread_id")
+
+	var state models.ThreadState
+	if err := c.ShouldBindJSON(&state); err != nil || (state.Pinned == nil && state.Locked == nil && state.Announcement == nil) {
+		utils.Logger.Error().Err(err).Msg("Error binding thread state request")
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "At least one of pinned, locked and announcement is required",
+		})
+		return
+	}
+
+	utils.Logger.Info().Str("thread id", threadID).Interface("state", state).Msgf("Update thread state request received from %s", principal.Uid)
+
+	if err := services.Threads.UpdateState(threadID, state, principal); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			c.JSON(http.StatusNotFound, gin.H{
+				"success": false,
+				"error":   "Thread not found",
+			})
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "Error updating thread state",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Thread state updated",
+	})
+}
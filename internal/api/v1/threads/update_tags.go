@@ -0,0 +1,67 @@
+package threads
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/ntu-onemdp/onemdp-backend/internal/api/middlewares"
+	"github.com/ntu-onemdp/onemdp-backend/internal/services"
+	"github.com/ntu-onemdp/onemdp-backend/internal/utils"
+)
+
+type UpdateTagsRequest struct {
+	Tags []string `json:"tags"` // Tag ids. Empty to remove all tags.
+}
+
+// PUT /api/v1/threads/:thread_id/tags
+func UpdateTagsHandler(c *gin.Context) {
+	threadID := c.Param("thread_id")
+
+	var request UpdateTagsRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		utils.Logger.Error().Err(err).Msg("Error binding update tags request")
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Malformed request",
+		})
+		return
+	}
+
+	// Get authenticated user
+	principal := middlewares.GetPrincipal(c)
+	if principal == nil {
+		return
+	}
+
+	utils.Logger.Info().Str("thread id", threadID).Strs("tags", request.Tags).Msgf("Update tags request received from %s", principal.Uid)
+
+	if err := services.Threads.UpdateTags(threadID, request.Tags, principal); err != nil {
+		if err == utils.NewErrUnauthorized() {
+			c.JSON(http.StatusUnauthorized, gin.H{
+				"success": false,
+				"error":   "Only the author or a moderator can change the tags of a thread",
+			})
+			return
+		}
+		if errors.Is(err, pgx.ErrNoRows) {
+			c.JSON(http.StatusNotFound, gin.H{
+				"success": false,
+				"error":   "Thread not found",
+			})
+			return
+		}
+
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Error updating tags: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Tags updated",
+	})
+}
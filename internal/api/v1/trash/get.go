@@ -0,0 +1,63 @@
+package trash
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	constants "github.com/ntu-onemdp/onemdp-backend/config"
+	"github.com/ntu-onemdp/onemdp-backend/internal/api/middlewares"
+	"github.com/ntu-onemdp/onemdp-backend/internal/services"
+	"github.com/ntu-onemdp/onemdp-backend/internal/utils"
+)
+
+// GET /api/v1/staff/trash?type=&page=&size=
+func GetTrashHandler(c *gin.Context) {
+	principal := middlewares.GetPrincipal(c)
+	if principal == nil {
+		return
+	}
+
+	size, err := strconv.Atoi(c.DefaultQuery("size", constants.DEFAULT_PAGE_SIZE))
+	if err != nil || size <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Invalid page size",
+		})
+		return
+	}
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if err != nil || page <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Invalid page",
+		})
+		return
+	}
+	contentType := c.Query("type")
+
+	utils.Logger.Debug().Str("type", contentType).Int("page", page).Int("size", size).Msgf("Request received from %s to view trash", principal.Uid)
+
+	items, metadata, err := services.Trash.GetAll(contentType, page, size)
+	if err != nil {
+		if err.Error() == "invalid content type" {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"success": false,
+				"error":   "Type must be one of thread, post, article or comment",
+			})
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "Error retrieving trash",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":  true,
+		"items":    items,
+		"metadata": metadata,
+	})
+}
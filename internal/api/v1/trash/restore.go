@@ -0,0 +1,52 @@
+package trash
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/ntu-onemdp/onemdp-backend/internal/api/middlewares"
+	"github.com/ntu-onemdp/onemdp-backend/internal/services"
+	"github.com/ntu-onemdp/onemdp-backend/internal/utils"
+)
+
+// POST /api/v1/staff/trash/:content_id/restore
+func RestoreHandler(c *gin.Context) {
+	principal := middlewares.GetPrincipal(c)
+	if principal == nil {
+		return
+	}
+	contentID := c.Param("content_id")
+
+	utils.Logger.Info().Str("content id", contentID).Msgf("Request received from %s to restore content", principal.Uid)
+
+	if err := services.Trash.Restore(contentID); err != nil {
+		// Content is not deleted, or its thread or article is still deleted
+		if errors.Is(err, pgx.ErrNoRows) {
+			c.JSON(http.StatusNotFound, gin.H{
+				"success": false,
+				"error":   "Deleted content not found. Restore its thread or article first if it is deleted.",
+			})
+			return
+		}
+		if err.Error() == "invalid content id" {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"success": false,
+				"error":   "Invalid content id",
+			})
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "Error restoring content",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Content restored",
+	})
+}
@@ -4,6 +4,8 @@ import (
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+	"github.com/ntu-onemdp/onemdp-backend/internal/api/middlewares"
+	"github.com/ntu-onemdp/onemdp-backend/internal/models"
 	"github.com/ntu-onemdp/onemdp-backend/internal/services"
 	"github.com/ntu-onemdp/onemdp-backend/internal/utils"
 )
@@ -26,6 +28,14 @@ func GetProfileHandler(c *gin.Context) {
 		return
 	}
 
+	// Let the frontend show a banner while an admin is viewing as this user
+	if principal := middlewares.GetPrincipal(c); principal != nil && principal.IsImpersonated() && principal.Uid == uid {
+		profile.Impersonation = &models.Impersonation{
+			ImpersonatorUid: principal.ImpersonatorUid,
+			ReadOnly:        principal.ReadOnly,
+		}
+	}
+
 	c.JSON(http.StatusOK, profile)
 }
 
@@ -64,7 +74,11 @@ func GetProfilePhotoHandler(c *gin.Context) {
 }
 
 func UpdateProfilePhotoHandler(c *gin.Context) {
-	uid := services.JwtHandler.GetUidFromJwt(c)
+	principal := middlewares.GetPrincipal(c)
+	if principal == nil {
+		return
+	}
+	uid := principal.Uid
 
 	if uid != c.Param("uid") {
 		utils.Logger.Warn().Msgf("UID in path param does not match JWT")
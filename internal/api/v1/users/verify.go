@@ -4,25 +4,25 @@ import (
 	"net/http"
 
 	"github.com/gin-gonic/gin"
-	"github.com/ntu-onemdp/onemdp-backend/internal/services"
+	"github.com/ntu-onemdp/onemdp-backend/internal/api/middlewares"
+	"github.com/ntu-onemdp/onemdp-backend/internal/models"
 	"github.com/ntu-onemdp/onemdp-backend/internal/utils"
 )
 
 func VerifyAdminHandler(c *gin.Context) {
-	// Get uid from jwt
-	uid := services.JwtHandler.GetUidFromJwt(c)
-
-	hasAdminPermission, err := services.Users.HasAdminPermission(uid)
-	if err != nil {
-		utils.Logger.Error().Err(err).Msgf("Error getting permission for user %s", uid)
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": err,
-		})
+	// Get authenticated user
+	principal := middlewares.GetPrincipal(c)
+	if principal == nil {
 		return
 	}
+	uid := principal.Uid
+
+	hasAdminPermission := principal.Can(models.PermUsersManage)
 
 	utils.Logger.Info().Bool("has admin permission", hasAdminPermission).Msgf("Request received from user %s to verify admin status.", uid)
 	c.JSON(http.StatusOK, gin.H{
 		"hasAdminPermission": hasAdminPermission,
+		"role":               principal.Role,
+		"permissions":        principal.Permissions,
 	})
 }
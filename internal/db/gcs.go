@@ -11,7 +11,8 @@ import (
 
 var Bucket *storage.BucketHandle
 
-func init() {
+// Connect to the GCS bucket. Called by Init, so that importing the package does not require GCS credentials.
+func initBucket() {
 	env := os.Getenv("ENV")
 	var path string
 	if env == "DEV" {
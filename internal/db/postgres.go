@@ -74,6 +74,8 @@ func Init() {
 	}
 
 	utils.Logger.Info().Msg("Postgres database fully initialized.")
+
+	initBucket()
 }
 
 func Close() {
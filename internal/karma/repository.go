@@ -46,9 +46,9 @@ func (r *karmaRepository) getSettings() (*Karma, error) {
 
 // Update karma settings for current semester
 func (r *karmaRepository) update(settings Karma) error {
-	query := fmt.Sprintf(`UPDATE %s SET CREATE_THREAD=$1, CREATE_ARTICLE=$2, CREATE_COMMENT=$3, CREATE_POST=$4, RECEIVE_LIKE=$5 WHERE SEMESTER = (SELECT SEMESTER FROM %s WHERE IS_CURRENT);`, KARMA_TABLE, semester.SEMESTER_TABLE)
+	query := fmt.Sprintf(`UPDATE %s SET CREATE_THREAD=$1, CREATE_ARTICLE=$2, CREATE_COMMENT=$3, CREATE_POST=$4, RECEIVE_LIKE=$5, ACCEPTED_ANSWER=$6 WHERE SEMESTER = (SELECT SEMESTER FROM %s WHERE IS_CURRENT);`, KARMA_TABLE, semester.SEMESTER_TABLE)
 
-	if _, err := r.db.Exec(context.Background(), query, settings.CreateThreadPts, settings.CreateArticlePts, settings.CreateCommentPts, settings.CreatePostPts, settings.LikePts); err != nil {
+	if _, err := r.db.Exec(context.Background(), query, settings.CreateThreadPts, settings.CreateArticlePts, settings.CreateCommentPts, settings.CreatePostPts, settings.LikePts, settings.AcceptedAnswerPts); err != nil {
 		utils.Logger.Error().Err(err).Msg("Error updating karma settings for current semester")
 		return err
 	}
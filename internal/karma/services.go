@@ -24,12 +24,13 @@ func NewKarmaService(repo *karmaRepository) *KarmaService {
 
 			// Default values for karma
 			settings = &Karma{
-				Semester:         *semester.Service.GetCurrentSem(),
-				CreateThreadPts:  10,
-				CreateArticlePts: 20,
-				CreateCommentPts: 2,
-				CreatePostPts:    5,
-				LikePts:          1,
+				Semester:          *semester.Service.GetCurrentSem(),
+				CreateThreadPts:   10,
+				CreateArticlePts:  20,
+				CreateCommentPts:  2,
+				CreatePostPts:     5,
+				LikePts:           1,
+				AcceptedAnswerPts: 15,
 			}
 
 			if err := repo.insert(settings.Semester); err != nil {
@@ -0,0 +1,16 @@
+package limiter
+
+import (
+	constants "github.com/ntu-onemdp/onemdp-backend/config"
+	"github.com/ntu-onemdp/onemdp-backend/internal/utils"
+)
+
+// Limiter for login and registration attempts
+var Auth *Limiter
+
+func Init() {
+	store := NewMemoryStore(constants.AUTH_ATTEMPT_WINDOW)
+	Auth = NewLimiter(store, constants.AUTH_MAX_ATTEMPTS, constants.AUTH_BASE_LOCKOUT, constants.AUTH_MAX_LOCKOUT, constants.AUTH_ATTEMPT_WINDOW)
+
+	utils.Logger.Info().Msg("Auth attempt limiter initialized")
+}
@@ -0,0 +1,100 @@
+package limiter
+
+import (
+	"time"
+)
+
+// Attempt state of a single key, e.g. an IP address or an email.
+type State struct {
+	Failures    int       // Consecutive failures within the window
+	LastFailure time.Time // Failures are forgotten once the window has passed since the last failure
+	LockedUntil time.Time // Zero if not locked
+}
+
+// Store holds attempt state. Implementations must be safe for concurrent use.
+// MemoryStore is used by default; a shared store (e.g. Redis) can be swapped in when running multiple instances.
+type Store interface {
+	Get(key string) (State, bool)
+	Update(key string, fn func(state State, ok bool) State) // Replace state of key with fn of its current state, atomically
+	Delete(key string)
+}
+
+// Limiter tracks failed attempts per key and locks keys out with exponential backoff.
+//
+// The first MaxAttempts failures are free. Every failure after that locks the key for BaseLockout, doubling with each
+// further failure up to MaxLockout.
+type Limiter struct {
+	store       Store
+	maxAttempts int
+	baseLockout time.Duration
+	maxLockout  time.Duration
+	window      time.Duration
+}
+
+func NewLimiter(store Store, maxAttempts int, baseLockout time.Duration, maxLockout time.Duration, window time.Duration) *Limiter {
+	return &Limiter{
+		store:       store,
+		maxAttempts: maxAttempts,
+		baseLockout: baseLockout,
+		maxLockout:  maxLockout,
+		window:      window,
+	}
+}
+
+// Returns the remaining lockout of the most locked key, or 0 if none of the keys are locked.
+func (l *Limiter) LockedFor(keys ...string) time.Duration {
+	now := time.Now()
+
+	var remaining time.Duration
+	for _, key := range keys {
+		state, ok := l.store.Get(key)
+		if !ok {
+			continue
+		}
+
+		if d := state.LockedUntil.Sub(now); d > remaining {
+			remaining = d
+		}
+	}
+
+	return remaining
+}
+
+// Record a failed attempt for each key, locking keys which have exceeded the number of free attempts.
+func (l *Limiter) Fail(keys ...string) {
+	now := time.Now()
+
+	for _, key := range keys {
+		l.store.Update(key, func(state State, ok bool) State {
+			if !ok || now.Sub(state.LastFailure) > l.window {
+				state = State{}
+			}
+
+			state.Failures++
+			state.LastFailure = now
+
+			if state.Failures > l.maxAttempts {
+				state.LockedUntil = now.Add(l.lockout(state.Failures - l.maxAttempts))
+			}
+
+			return state
+		})
+	}
+}
+
+// Clear the failures of each key after a successful attempt.
+func (l *Limiter) Succeed(keys ...string) {
+	for _, key := range keys {
+		l.store.Delete(key)
+	}
+}
+
+// Lockout duration after n failures beyond the free attempts
+func (l *Limiter) lockout(n int) time.Duration {
+	d := l.baseLockout
+	for i := 1; i < n && d < l.maxLockout; i++ {
+		d *= 2
+	}
+
+	return min(d, l.maxLockout)
+}
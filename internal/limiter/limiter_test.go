@@ -0,0 +1,100 @@
+package limiter
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func newTestLimiter() *Limiter {
+	return NewLimiter(NewMemoryStore(time.Hour), 3, time.Minute, 10*time.Minute, time.Hour)
+}
+
+func TestLockout(t *testing.T) {
+	l := newTestLimiter()
+
+	tests := []struct {
+		n    int
+		want time.Duration
+	}{
+		{1, time.Minute},
+		{2, 2 * time.Minute},
+		{3, 4 * time.Minute},
+		{4, 8 * time.Minute},
+		{5, 10 * time.Minute},
+		{100, 10 * time.Minute},
+	}
+
+	for _, tt := range tests {
+		if got := l.lockout(tt.n); got != tt.want {
+			t.Errorf("lockout(%d) = %v; want %v", tt.n, got, tt.want)
+		}
+	}
+}
+
+func TestFailLocksAfterFreeAttempts(t *testing.T) {
+	l := newTestLimiter()
+
+	for i := 0; i < 3; i++ {
+		l.Fail("ip:1.2.3.4")
+		if d := l.LockedFor("ip:1.2.3.4"); d != 0 {
+			t.Fatalf("locked for %v after %d failures; want unlocked", d, i+1)
+		}
+	}
+
+	l.Fail("ip:1.2.3.4")
+	if d := l.LockedFor("ip:1.2.3.4"); d <= 0 || d > time.Minute {
+		t.Errorf("locked for %v after 4 failures; want up to 1m", d)
+	}
+
+	// The most locked key wins
+	if d := l.LockedFor("email:a@b.c", "ip:1.2.3.4"); d <= 0 {
+		t.Errorf("locked for %v with a locked key; want locked", d)
+	}
+	if d := l.LockedFor("ip:5.6.7.8"); d != 0 {
+		t.Errorf("unrelated key locked for %v", d)
+	}
+}
+
+func TestFailForgetsFailuresOutsideWindow(t *testing.T) {
+	l := newTestLimiter()
+	l.store.Update("ip:1.2.3.4", func(State, bool) State {
+		return State{Failures: 10, LastFailure: time.Now().Add(-2 * time.Hour)}
+	})
+
+	l.Fail("ip:1.2.3.4")
+	state, _ := l.store.Get("ip:1.2.3.4")
+	if state.Failures != 1 || !state.LockedUntil.IsZero() {
+		t.Errorf("state = %+v; want 1 failure and unlocked", state)
+	}
+}
+
+func TestSucceedClearsKey(t *testing.T) {
+	l := newTestLimiter()
+	for i := 0; i < 5; i++ {
+		l.Fail("email:a@b.c")
+	}
+
+	l.Succeed("email:a@b.c")
+	if d := l.LockedFor("email:a@b.c"); d != 0 {
+		t.Errorf("locked for %v after success; want unlocked", d)
+	}
+}
+
+func TestFailConcurrent(t *testing.T) {
+	l := newTestLimiter()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			l.Fail("ip:1.2.3.4")
+		}()
+	}
+	wg.Wait()
+
+	if state, _ := l.store.Get("ip:1.2.3.4"); state.Failures != 100 {
+		t.Errorf("failures = %d; want 100", state.Failures)
+	}
+}
@@ -0,0 +1,61 @@
+package limiter
+
+import (
+	"sync"
+	"time"
+)
+
+// Number of keys after which stale entries are swept on write
+const sweepThreshold = 10000
+
+// In-memory Store. State is lost on restart and is not shared between instances.
+type MemoryStore struct {
+	mu      sync.Mutex
+	entries map[string]State
+	window  time.Duration // Entries are stale once unlocked and older than the window
+}
+
+func NewMemoryStore(window time.Duration) *MemoryStore {
+	return &MemoryStore{
+		entries: make(map[string]State),
+		window:  window,
+	}
+}
+
+func (s *MemoryStore) Get(key string) (State, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	state, ok := s.entries[key]
+	return state, ok
+}
+
+func (s *MemoryStore) Update(key string, fn func(state State, ok bool) State) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(s.entries) >= sweepThreshold {
+		s.sweep()
+	}
+
+	state, ok := s.entries[key]
+	s.entries[key] = fn(state, ok)
+}
+
+func (s *MemoryStore) Delete(key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.entries, key)
+}
+
+// Remove entries which are no longer locked and whose failures have been forgotten. Caller must hold the lock.
+func (s *MemoryStore) sweep() {
+	now := time.Now()
+
+	for key, state := range s.entries {
+		if now.After(state.LockedUntil) && now.Sub(state.LastFailure) > s.window {
+			delete(s.entries, key)
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package models
+
+import (
+	"slices"
+	"strings"
+	"time"
+
+	gonanoid "github.com/matoous/go-nanoid/v2"
+	constants "github.com/ntu-onemdp/onemdp-backend/config"
+	"github.com/ntu-onemdp/onemdp-backend/internal/utils"
+)
+
+// Prefix of every plaintext api key, to make leaked keys easy to recognise.
+const API_KEY_PREFIX = "omdp_"
+
+// ApiKey models a key used by services (e.g. the frontend server or Eduvisor) to call the backend without a jwt.
+// Requests made with the key act as the owner, but only with permissions that MaxRole also has.
+// Only the hash of the key is stored.
+type ApiKey struct {
+	KeyID       string     `json:"key_id" db:"key_id"`
+	KeyHash     string     `json:"-" db:"key_hash"`
+	Name        string     `json:"name" db:"name"`
+	OwnerUid    string     `json:"owner_uid" db:"owner_uid"`
+	MaxRole     string     `json:"max_role" db:"max_role"`
+	Scopes      []string   `json:"scopes" db:"scopes"` // Route prefixes the key may access, e.g. /api/v1/threads. Empty allows all routes.
+	TimeCreated time.Time  `json:"time_created" db:"time_created"`
+	ExpiresAt   *time.Time `json:"expires_at" db:"expires_at"` // Never expires if nil
+	LastUsed    *time.Time `json:"last_used" db:"last_used"`
+	RevokedAt   *time.Time `json:"revoked_at" db:"revoked_at"`
+}
+
+// Create a new api key. Returns the key and its plaintext value.
+// The plaintext value is not stored anywhere and must be returned to the admin creating it.
+func NewApiKey(name string, ownerUid string, maxRole string, scopes []string, expiresAt *time.Time) (*ApiKey, string) {
+	key := API_KEY_PREFIX + utils.GenerateToken()
+
+	if scopes == nil {
+		scopes = []string{}
+	}
+
+	return &ApiKey{
+		KeyID:       "k" + gonanoid.Must(constants.CONTENT_ID_LENGTH),
+		KeyHash:     utils.HashToken(key),
+		Name:        name,
+		OwnerUid:    ownerUid,
+		MaxRole:     maxRole,
+		Scopes:      scopes,
+		TimeCreated: time.Now(),
+		ExpiresAt:   expiresAt,
+		LastUsed:    nil,
+		RevokedAt:   nil,
+	}, key
+}
+
+// Returns true if the key may be used on path.
+func (k *ApiKey) InScope(path string) bool {
+	if len(k.Scopes) == 0 {
+		return true
+	}
+
+	return slices.ContainsFunc(k.Scopes, func(scope string) bool {
+		return path == scope || strings.HasPrefix(path, strings.TrimSuffix(scope, "/")+"/")
+	})
+}
@@ -0,0 +1,15 @@
+package models
+
+import "time"
+
+// Failed login or registration attempt, recorded for admins to review.
+// Secrets (tokens, enrolment codes) are never recorded.
+type AuthFailure struct {
+	FailureID   int64     `json:"failure_id" db:"failure_id"`
+	Endpoint    string    `json:"endpoint" db:"endpoint"` // login or register
+	Ip          string    `json:"ip" db:"ip"`
+	Email       *string   `json:"email" db:"email"` // Nil if the identity token could not be verified
+	Reason      string    `json:"reason" db:"reason"`
+	UserAgent   string    `json:"user_agent" db:"user_agent"`
+	TimeCreated time.Time `json:"time_created" db:"time_created"`
+}
@@ -8,14 +8,19 @@ import (
 	"github.com/ntu-onemdp/onemdp-backend/internal/utils"
 )
 
+// Shown in place of the author and content of a deleted comment which still has replies
+const DELETED_COMMENT = "[deleted]"
+
 // Comment models how a comment is represented on the API.
 type Comment struct {
 	DbComment
 
-	Author   string `json:"author" db:"author_name"` // Name of the author
-	NumLikes int    `json:"num_likes" db:"num_likes"`
-	IsLiked  bool   `json:"is_liked" db:"is_liked"`   // Whether the post has been liked by user
-	IsAuthor bool   `json:"is_author" db:"is_author"` // Whether user sending request is the author
+	Author     string    `json:"author" db:"author_name"` // Name of the author
+	NumLikes   int       `json:"num_likes" db:"num_likes"`
+	IsLiked    bool      `json:"is_liked" db:"is_liked"`   // Whether the post has been liked by user
+	IsAuthor   bool      `json:"is_author" db:"is_author"` // Whether user sending request is the author
+	NumReplies int       `json:"num_replies" db:"-"`       // Number of direct replies, including those beyond the depth returned
+	Replies    []Comment `json:"replies" db:"-"`
 }
 
 // DbComment models how a comment is stored in the database
@@ -23,6 +28,7 @@ type DbComment struct {
 	CommentID   string    `json:"comment_id" db:"comment_id"`
 	AuthorUID   string    `json:"author_uid" db:"author" binding:"required"`
 	ArticleID   string    `json:"article_id" db:"article_id" `
+	ReplyTo     *string   `json:"reply_to" db:"reply_to"` // Parent comment. Nil for top level comments.
 	Content     string    `json:"content" db:"content" binding:"required"`
 	TimeCreated time.Time `json:"time_created" db:"time_created"`
 	LastEdited  time.Time `json:"last_edited" db:"last_edited"`
@@ -38,11 +44,12 @@ func NewCommentFactory() *CommentFactory {
 	return &CommentFactory{}
 }
 
-func (f *CommentFactory) New(authorUID string, articleID string, content string) *DbComment {
+func (f *CommentFactory) New(authorUID string, articleID string, replyTo *string, content string) *DbComment {
 	return &DbComment{
 		CommentID:   "c" + gonanoid.Must(constants.CONTENT_ID_LENGTH),
 		AuthorUID:   authorUID,
 		ArticleID:   articleID,
+		ReplyTo:     replyTo,
 		Content:     utils.SanitizeContent(content),
 		TimeCreated: time.Now(),
 		LastEdited:  time.Now(),
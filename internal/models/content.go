@@ -22,6 +22,13 @@ type Content interface {
 	GetFlagged() bool
 }
 
+// Endorsement of a thread or post by an instructor. All fields are nil if the content is not endorsed.
+type Endorsement struct {
+	EndorsedBy   *string    `json:"endorsed_by" db:"endorsed_by"`     // Nil if the endorsing user has been removed
+	Endorser     *string    `json:"endorser" db:"endorser_name"`      // Name of the endorsing user
+	TimeEndorsed *time.Time `json:"time_endorsed" db:"time_endorsed"` // Set if the content is endorsed
+}
+
 type ContentMetadata struct {
 	Total    int `json:"total" db:"count"`
 	NumPages int `json:"num_pages" db:"-"`
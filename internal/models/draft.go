@@ -0,0 +1,56 @@
+package models
+
+import (
+	"time"
+
+	gonanoid "github.com/matoous/go-nanoid/v2"
+	constants "github.com/ntu-onemdp/onemdp-backend/config"
+	"github.com/ntu-onemdp/onemdp-backend/internal/utils"
+)
+
+// Type of content a draft will be published as
+const (
+	DraftThread  = "thread"
+	DraftPost    = "post"
+	DraftArticle = "article"
+	DraftComment = "comment"
+)
+
+// Unpublished content saved by its author.
+type Draft struct {
+	DraftID     string    `json:"draft_id" db:"draft_id"`
+	Author      string    `json:"author" db:"author"`
+	TargetType  string    `json:"target_type" db:"target_type"`
+	TargetID    *string   `json:"target_id" db:"target_id"` // Thread of post drafts and article of comment drafts. Nil for threads and articles.
+	ReplyTo     *string   `json:"reply_to" db:"reply_to"`   // Post or comment being replied to. Post and comment drafts only.
+	Title       string    `json:"title" db:"title"`
+	Content     string    `json:"content" db:"content"`
+	IsAnon      bool      `json:"is_anon" db:"is_anon"`
+	TimeCreated time.Time `json:"time_created" db:"time_created"`
+	LastEdited  time.Time `json:"last_edited" db:"last_edited"`
+}
+
+// Create a new draft. Content is sanitized here.
+func NewDraft(author string, targetType string, targetID *string, replyTo *string, title string, content string, isAnon bool) *Draft {
+	return &Draft{
+		DraftID:     "d" + gonanoid.Must(constants.CONTENT_ID_LENGTH),
+		Author:      author,
+		TargetType:  targetType,
+		TargetID:    targetID,
+		ReplyTo:     replyTo,
+		Title:       title,
+		Content:     utils.SanitizeContent(content),
+		IsAnon:      isAnon,
+		TimeCreated: time.Now(),
+		LastEdited:  time.Now(),
+	}
+}
+
+// Returns true if t is a type of content that can be drafted
+func IsValidDraftType(t string) bool {
+	switch t {
+	case DraftThread, DraftPost, DraftArticle, DraftComment:
+		return true
+	}
+	return false
+}
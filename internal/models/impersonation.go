@@ -0,0 +1,26 @@
+package models
+
+import "time"
+
+// Method recorded in the audit trail when an impersonation token is issued
+const IMPERSONATION_START = "START"
+
+// A request made with an impersonation token, or the issuing of one.
+type ImpersonationAudit struct {
+	AuditID         int64     `json:"audit_id" db:"audit_id"`
+	ImpersonatorUid string    `json:"impersonator_uid" db:"impersonator_uid"`
+	TargetUid       string    `json:"target_uid" db:"target_uid"`
+	SessionID       string    `json:"session_id" db:"session_id"`
+	Method          string    `json:"method" db:"method"`
+	Path            string    `json:"path" db:"path"`
+	Status          int       `json:"status" db:"status"`
+	Ip              string    `json:"ip" db:"ip"`
+	Reason          *string   `json:"reason" db:"reason"`
+	TimeCreated     time.Time `json:"time_created" db:"time_created"`
+}
+
+// Shown to the frontend so that it can display a banner while an admin is viewing as another user
+type Impersonation struct {
+	ImpersonatorUid string `json:"impersonator_uid"`
+	ReadOnly        bool   `json:"read_only"`
+}
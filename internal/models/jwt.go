@@ -1,18 +1,66 @@
 package models
 
 import (
+	"time"
+
 	"github.com/golang-jwt/jwt/v5"
 )
 
-// Jwt will store only Uid. Retrieve role from database instead of jwt.
+// Jwt will store only Uid and the session it was issued for. Retrieve role from database instead of jwt.
 type JwtClaim struct {
 	Uid string `json:"uid"`
+	Sid string `json:"sid"` // Session ID. Token is rejected once the session is revoked.
+
+	// Only set on impersonation tokens. Act is the admin acting as Uid, and Sid is the admin's session.
+	Act      *ActorClaim `json:"act,omitempty"`
+	ReadOnly bool        `json:"read_only,omitempty"`
 
 	jwt.RegisteredClaims
 }
 
-func NewClaim(uid string) *JwtClaim {
+// Actor claim (RFC 8693) identifying who is acting on behalf of the subject
+type ActorClaim struct {
+	Sub string `json:"sub"`
+}
+
+func NewClaim(uid string, sid string) *JwtClaim {
 	return &JwtClaim{
 		Uid: uid,
+		Sid: sid,
+	}
+}
+
+// Claim of a token letting admin act as uid. The token is tied to the admin's session and ends when it is revoked.
+func NewImpersonationClaim(uid string, admin string, adminSid string, readOnly bool, expiresAt time.Time) *JwtClaim {
+	return &JwtClaim{
+		Uid:      uid,
+		Sid:      adminSid,
+		Act:      &ActorClaim{Sub: admin},
+		ReadOnly: readOnly,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+		},
+	}
+}
+
+// Claims of the ID/access token issued by the SSO identity provider (Supabase).
+// Uid is taken from the subject claim.
+type SsoClaim struct {
+	Email        string          `json:"email"`
+	UserMetadata SsoUserMetadata `json:"user_metadata"`
+
+	jwt.RegisteredClaims
+}
+
+type SsoUserMetadata struct {
+	FullName string `json:"full_name"`
+	Name     string `json:"name"` // Set by some providers instead of full_name
+}
+
+// Display name of the user, preferring full_name.
+func (m SsoUserMetadata) DisplayName() string {
+	if m.FullName != "" {
+		return m.FullName
 	}
+	return m.Name
 }
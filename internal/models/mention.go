@@ -0,0 +1,41 @@
+package models
+
+import (
+	"html"
+	"regexp"
+)
+
+// Matches the id of the mentioned user in sanitized mention spans
+var mentionPattern = regexp.MustCompile(`<span[^>]*\sclass="mention"[^>]*\sdata-id="([^"]*)"`)
+
+// Content in which users are mentioned
+type MentionSource struct {
+	ContentID   string // Thread, post, article or comment containing the mentions
+	ContentType string // One of TrashThread, TrashPost, TrashArticle or TrashComment
+	Author      string
+	IsAnon      bool   // Author is hidden from mentioned users
+	LinkID      string // Thread or article mentioned users are sent to
+	Title       string // Title of the thread or article
+}
+
+// User that can be mentioned, as returned to the mention picker
+type MentionCandidate struct {
+	Uid  string `json:"uid" db:"uid"`
+	Name string `json:"name" db:"name"`
+	Role string `json:"role" db:"role"`
+}
+
+// Uids or names of the users mentioned in content, without duplicates
+func MentionedIDs(content string) []string {
+	ids := []string{}
+	seen := make(map[string]bool)
+	for _, match := range mentionPattern.FindAllStringSubmatch(content, -1) {
+		id := html.UnescapeString(match[1])
+		if id == "" || seen[id] {
+			continue
+		}
+		seen[id] = true
+		ids = append(ids, id)
+	}
+	return ids
+}
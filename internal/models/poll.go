@@ -0,0 +1,82 @@
+package models
+
+import (
+	"strings"
+	"time"
+
+	gonanoid "github.com/matoous/go-nanoid/v2"
+	constants "github.com/ntu-onemdp/onemdp-backend/config"
+)
+
+// Poll carried by a thread
+type Poll struct {
+	PollID         string     `json:"poll_id" db:"poll_id"`
+	ThreadID       string     `json:"thread_id" db:"thread_id"`
+	Question       string     `json:"question" db:"question"`
+	MultipleChoice bool       `json:"multiple_choice" db:"multiple_choice"`
+	IsAnon         bool       `json:"is_anon" db:"is_anon"`     // Voters are hidden from results
+	ClosesAt       *time.Time `json:"closes_at" db:"closes_at"` // Never closes if nil
+	CreatedBy      string     `json:"created_by" db:"created_by"`
+	TimeCreated    time.Time  `json:"time_created" db:"time_created"`
+}
+
+type PollOption struct {
+	OptionID string `json:"option_id" db:"option_id"`
+	PollID   string `json:"-" db:"poll_id"`
+	Position int    `json:"position" db:"position"`
+	Text     string `json:"text" db:"text"`
+}
+
+// Option of a poll together with its votes
+type PollOptionResult struct {
+	PollOption
+	NumVotes int         `json:"num_votes" db:"num_votes"`
+	Voters   []PollVoter `json:"voters,omitempty" db:"-"` // Only set for polls which are not anonymous
+}
+
+type PollVoter struct {
+	OptionID string `json:"-" db:"option_id"`
+	Uid      string `json:"uid" db:"uid"`
+	Name     string `json:"name" db:"name"`
+}
+
+// Poll as seen by a user. Votes are only included once the user has voted or the poll has closed.
+type PollResults struct {
+	Poll
+	IsClosed       bool               `json:"is_closed"`
+	ResultsVisible bool               `json:"results_visible"`
+	Votes          []string           `json:"votes"`      // Options voted for by the user
+	NumVoters      *int               `json:"num_voters"` // Nil if results are hidden
+	Options        []PollOptionResult `json:"options"`    // Votes of each option are zero and voters empty if results are hidden
+}
+
+// Create a new poll with the given options, in order
+func NewPoll(threadID string, question string, options []string, multipleChoice bool, isAnon bool, closesAt *time.Time, createdBy string) (*Poll, []PollOption) {
+	poll := &Poll{
+		PollID:         "v" + gonanoid.Must(constants.CONTENT_ID_LENGTH),
+		ThreadID:       threadID,
+		Question:       strings.TrimSpace(question),
+		MultipleChoice: multipleChoice,
+		IsAnon:         isAnon,
+		ClosesAt:       closesAt,
+		CreatedBy:      createdBy,
+		TimeCreated:    time.Now(),
+	}
+
+	pollOptions := make([]PollOption, len(options))
+	for i, text := range options {
+		pollOptions[i] = PollOption{
+			OptionID: "o" + gonanoid.Must(constants.CONTENT_ID_LENGTH),
+			PollID:   poll.PollID,
+			Position: i,
+			Text:     strings.TrimSpace(text),
+		}
+	}
+
+	return poll, pollOptions
+}
+
+// Returns true if the poll no longer accepts votes
+func (p *Poll) Closed() bool {
+	return p.ClosesAt != nil && !p.ClosesAt.After(time.Now())
+}
@@ -1,6 +1,7 @@
 package models
 
 import (
+	"regexp"
 	"time"
 
 	gonanoid "github.com/matoous/go-nanoid/v2"
@@ -8,6 +9,16 @@ import (
 	"github.com/ntu-onemdp/onemdp-backend/internal/utils"
 )
 
+// Matches the id of the quoted post in sanitized quote blocks
+var quotedPostPattern = regexp.MustCompile(`<blockquote[^>]*\sdata-post-id="([^"]*)"`)
+
+// Ways posts of a thread can be returned
+const (
+	PostViewFlat     = "flat"     // List in the order posts were created, with the parent id and depth of each post
+	PostViewThreaded = "threaded" // List with each post followed by its replies, with the parent id and depth of each post
+	PostViewTree     = "tree"     // Replies nested under the post they reply to
+)
+
 type PostFactory struct {
 	ContentFactory
 }
@@ -19,11 +30,14 @@ func NewPostFactory() *PostFactory {
 // Post models how a post is retrieved from the database.
 type Post struct {
 	DbPost
+	Endorsement
 
 	Author   string `json:"author" db:"author_name"` // Name of the author
 	NumLikes int    `json:"num_likes" db:"num_likes"`
 	IsLiked  bool   `json:"is_liked" db:"is_liked"`   // Whether the post is liked by the user
 	IsAuthor bool   `json:"is_author" db:"is_author"` // Whether user sending request is the author
+	Depth    int    `json:"depth" db:"-"`             // Number of posts above this post in the reply chain
+	Replies  []Post `json:"replies,omitempty" db:"-"` // Only set when posts are returned as a tree
 }
 
 // DbPost models how a post is stored in the database.
@@ -31,7 +45,7 @@ type DbPost struct {
 	PostID      string    `json:"post_id" db:"post_id"`
 	AuthorUid   string    `json:"author_uid" db:"author" binding:"required"`
 	ThreadId    string    `json:"thread_id" db:"thread_id" binding:"required"`
-	ReplyTo     *string   `json:"reply_to" db:"reply_to"`
+	ReplyTo     *string   `json:"reply_to" db:"reply_to"` // Post being replied to. Must be in the same thread.
 	Title       string    `json:"title" db:"title" binding:"required"`
 	PostContent string    `json:"content" db:"content" binding:"required"`
 	TimeCreated time.Time `json:"time_created" db:"time_created"`
@@ -40,6 +54,7 @@ type DbPost struct {
 	IsAvailable bool      `json:"is_available" db:"is_available"`
 	IsHeader    bool      `json:"is_header" db:"is_header"`
 	IsAnon      bool      `json:"is_anon" db:"is_anon"`
+	NumEdits    int       `json:"num_edits" db:"num_edits"` // Number of times the post has been edited
 }
 
 func (f *PostFactory) New(author string, threadId string, title string, content string, replyTo *string, isHeader bool, isAnon bool) *DbPost {
@@ -58,3 +73,12 @@ func (f *PostFactory) New(author string, threadId string, title string, content
 		IsAnon:      isAnon,
 	}
 }
+
+// Ids of the posts quoted in content
+func (p *DbPost) QuotedPostIDs() []string {
+	ids := []string{}
+	for _, match := range quotedPostPattern.FindAllStringSubmatch(p.PostContent, -1) {
+		ids = append(ids, match[1])
+	}
+	return ids
+}
@@ -0,0 +1,35 @@
+package models
+
+import "slices"
+
+// How a request was authenticated
+type AuthMethod string
+
+const (
+	AuthJwt    AuthMethod = "jwt"
+	AuthApiKey AuthMethod = "api_key"
+)
+
+// Principal is the authenticated caller of a request. It is set on the gin context by AuthGuard.
+type Principal struct {
+	Uid         string       `json:"uid"`
+	Role        string       `json:"role"`
+	Permissions []Permission `json:"permissions"` // For api keys, only permissions held by both the owner's role and the key's max role
+	Method      AuthMethod   `json:"method"`
+	SessionID   string       `json:"session_id,omitempty"` // Only set for jwt
+	ApiKeyID    string       `json:"api_key_id,omitempty"` // Only set for api keys
+
+	// Only set for impersonation tokens
+	ImpersonatorUid string `json:"impersonator_uid,omitempty"`
+	ReadOnly        bool   `json:"read_only,omitempty"`
+}
+
+// Returns true if an admin is acting as this user
+func (p *Principal) IsImpersonated() bool {
+	return p.ImpersonatorUid != ""
+}
+
+// Returns true if principal has perm
+func (p *Principal) Can(perm Permission) bool {
+	return slices.Contains(p.Permissions, perm)
+}
@@ -0,0 +1,36 @@
+package models
+
+import (
+	"time"
+
+	"github.com/ntu-onemdp/onemdp-backend/internal/utils"
+)
+
+// A previous version of an article
+type ArticleRevision struct {
+	ArticleID    string    `json:"article_id" db:"article_id"`
+	Revision     int       `json:"revision" db:"revision"`
+	Title        string    `json:"title" db:"title"`
+	Content      string    `json:"content" db:"content"`
+	ReplacedBy   string    `json:"replaced_by" db:"replaced_by"` // User whose edit or rollback replaced this version
+	TimeReplaced time.Time `json:"time_replaced" db:"time_replaced"`
+}
+
+// A previous version of a post
+type PostRevision struct {
+	PostID       string    `json:"post_id" db:"post_id"`
+	Revision     int       `json:"revision" db:"revision"`
+	Title        string    `json:"title" db:"title"`
+	Content      string    `json:"content" db:"content"`
+	ReplacedBy   string    `json:"replaced_by" db:"replaced_by"` // User whose edit replaced this version. May be staff rather than the author.
+	TimeReplaced time.Time `json:"time_replaced" db:"time_replaced"`
+}
+
+// Differences between two versions of a piece of content. Revision 0 is the current version.
+type RevisionDiff struct {
+	From      int              `json:"from"`
+	To        int              `json:"to"`
+	FromTitle string           `json:"from_title"`
+	ToTitle   string           `json:"to_title"`
+	Lines     []utils.DiffLine `json:"lines"`
+}
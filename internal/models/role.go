@@ -0,0 +1,72 @@
+package models
+
+import (
+	"slices"
+	"time"
+)
+
+// A named action that a role may be granted. Any authenticated user may read and post content; permissions cover
+// everything beyond that.
+type Permission string
+
+const (
+	PermContentHide     Permission = "content.hide"     // Hide (delete) threads, posts, articles and comments of other users
+	PermContentModerate Permission = "content.moderate" // Edit content of other users
+	PermContentEndorse  Permission = "content.endorse"  // Endorse threads and posts as an instructor
+	PermFilesManage     Permission = "files.manage"     // Upload, delete and restore course files
+	PermKarmaConfigure  Permission = "karma.configure"  // Change karma settings
+	PermUsersManage     Permission = "users.manage"     // Create, update, suspend and remove users
+	PermEnrolmentView   Permission = "enrolment.view"   // View the enrolment code of the current semester
+	PermEnrolmentManage Permission = "enrolment.manage" // Create, refresh and disable enrolment codes
+	PermApiKeysManage   Permission = "api_keys.manage"  // Create and revoke api keys
+	PermRolesManage     Permission = "roles.manage"     // Create, update and delete roles
+)
+
+// All permissions that can be granted to a role
+var AllPermissions = []Permission{
+	PermContentHide,
+	PermContentModerate,
+	PermContentEndorse,
+	PermFilesManage,
+	PermKarmaConfigure,
+	PermUsersManage,
+	PermEnrolmentView,
+	PermEnrolmentManage,
+	PermApiKeysManage,
+	PermRolesManage,
+}
+
+// Returns true if perm is a known permission
+func IsValidPermission(perm Permission) bool {
+	return slices.Contains(AllPermissions, perm)
+}
+
+// Built-in roles. Other roles can be created by admins.
+const (
+	RoleStudent   = "student"
+	RoleBot       = "bot"
+	RoleModerator = "moderator"
+	RoleTA        = "ta"
+	RoleStaff     = "staff"
+	RoleAdmin     = "admin"
+	RoleDeleted   = "deleted" // Role of the placeholder deleted user
+)
+
+// Role is a named set of permissions stored in the roles and role_permissions tables.
+type Role struct {
+	Role        string       `json:"role" db:"role"`
+	Description string       `json:"description" db:"description"`
+	IsSystem    bool         `json:"is_system" db:"is_system"` // Built-in roles cannot be deleted
+	TimeCreated time.Time    `json:"time_created" db:"time_created"`
+	Permissions []Permission `json:"permissions" db:"permissions"`
+}
+
+// Returns true if users may be given this role. Bot and deleted roles are reserved for special users.
+func (r *Role) IsAssignable() bool {
+	return r.Role != RoleBot && r.Role != RoleDeleted
+}
+
+// Returns true if role has perm
+func (r *Role) Can(perm Permission) bool {
+	return slices.Contains(r.Permissions, perm)
+}
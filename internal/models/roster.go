@@ -0,0 +1,35 @@
+package models
+
+// A row of a roster file uploaded by admin. Row is the line number in the file (header is row 1).
+type RosterRow struct {
+	Row           int     `json:"row"`
+	Email         string  `json:"email"`
+	Name          *string `json:"name"`
+	Role          string  `json:"role"`
+	Semester      string  `json:"semester"`
+	TutorialGroup *string `json:"tutorial_group"`
+}
+
+// Results of importing a single roster row
+const (
+	RosterCreated = "created" // New pending user added
+	RosterUpdated = "updated" // Existing pending user overwritten
+	RosterValid   = "valid"   // Row would be imported, returned on dry runs
+	RosterFailed  = "failed"
+)
+
+// Outcome of importing a single roster row. Errors is only set for failed rows.
+type RosterResult struct {
+	Row    int      `json:"row"`
+	Email  string   `json:"email"`
+	Result string   `json:"result"`
+	Errors []string `json:"errors,omitempty"`
+}
+
+// Outcome of importing a roster file
+type RosterImport struct {
+	DryRun     bool           `json:"dry_run"`
+	NumSuccess int            `json:"num_success"`
+	NumFailed  int            `json:"num_failed"`
+	Results    []RosterResult `json:"results"`
+}
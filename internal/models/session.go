@@ -0,0 +1,39 @@
+package models
+
+import (
+	"time"
+
+	gonanoid "github.com/matoous/go-nanoid/v2"
+	constants "github.com/ntu-onemdp/onemdp-backend/config"
+	"github.com/ntu-onemdp/onemdp-backend/internal/utils"
+)
+
+// Session models a login session. Each session holds one refresh token, which is rotated on every refresh.
+// Only the hash of the refresh token is stored.
+type Session struct {
+	SessionID   string     `json:"session_id" db:"session_id"`
+	Uid         string     `json:"uid" db:"uid"`
+	TokenHash   string     `json:"-" db:"token_hash"`
+	UserAgent   string     `json:"user_agent" db:"user_agent"`
+	TimeCreated time.Time  `json:"time_created" db:"time_created"`
+	LastUsed    time.Time  `json:"last_used" db:"last_used"`
+	ExpiresAt   time.Time  `json:"expires_at" db:"expires_at"`
+	RevokedAt   *time.Time `json:"revoked_at" db:"revoked_at"`
+}
+
+// Create a new session for uid. Returns the session and the plaintext refresh token.
+// The plaintext refresh token is not stored anywhere and must be returned to the client.
+func NewSession(uid string, userAgent string) (*Session, string) {
+	token := utils.GenerateToken()
+
+	return &Session{
+		SessionID:   "s" + gonanoid.Must(constants.CONTENT_ID_LENGTH),
+		Uid:         uid,
+		TokenHash:   utils.HashToken(token),
+		UserAgent:   userAgent,
+		TimeCreated: time.Now(),
+		LastUsed:    time.Now(),
+		ExpiresAt:   time.Now().Add(constants.REFRESH_TOKEN_TTL),
+		RevokedAt:   nil,
+	}, token
+}
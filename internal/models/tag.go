@@ -0,0 +1,55 @@
+package models
+
+import (
+	"regexp"
+	"strings"
+	"time"
+
+	gonanoid "github.com/matoous/go-nanoid/v2"
+	constants "github.com/ntu-onemdp/onemdp-backend/config"
+)
+
+// Tag matching modes when filtering threads by more than one tag
+const (
+	TagMatchAny = "any" // Threads with at least one of the tags
+	TagMatchAll = "all" // Threads with all of the tags
+)
+
+const DEFAULT_TAG_COLOUR = "#808080"
+
+var tagColourPattern = regexp.MustCompile(`^#[0-9a-fA-F]{6}$`)
+
+// Staff-managed label for threads, e.g. a weekly topic
+type Tag struct {
+	TagID       string    `json:"tag_id" db:"tag_id"`
+	Name        string    `json:"name" db:"name"`
+	Colour      string    `json:"colour" db:"colour"`
+	Description string    `json:"description" db:"description"`
+	TimeCreated time.Time `json:"time_created" db:"time_created"`
+}
+
+// Tag with the number of available threads labelled with it
+type TagCount struct {
+	Tag
+	NumThreads int `json:"num_threads" db:"num_threads"`
+}
+
+// Create a new tag. Colour defaults to DEFAULT_TAG_COLOUR if empty.
+func NewTag(name string, colour string, description string) *Tag {
+	if colour == "" {
+		colour = DEFAULT_TAG_COLOUR
+	}
+
+	return &Tag{
+		TagID:       "g" + gonanoid.Must(constants.CONTENT_ID_LENGTH),
+		Name:        strings.TrimSpace(name),
+		Colour:      colour,
+		Description: strings.TrimSpace(description),
+		TimeCreated: time.Now(),
+	}
+}
+
+// Returns true if colour is a hex colour of the form #rrggbb
+func IsValidTagColour(colour string) bool {
+	return tagColourPattern.MatchString(colour)
+}
@@ -18,6 +18,7 @@ func NewThreadFactory() *ThreadFactory {
 // Thread models how a thread is retrieved from the database.
 type Thread struct {
 	DbThread
+	Endorsement
 
 	Author      string `json:"author" db:"author_name"` // Name of the author
 	NumViews    int    `json:"views" db:"views"`
@@ -26,6 +27,8 @@ type Thread struct {
 	IsLiked     bool   `json:"is_liked" db:"is_liked"`         // Whether the thread is liked by the user
 	IsAuthor    bool   `json:"is_author" db:"is_author"`       // Whether user sending request is the author
 	IsFavorited bool   `json:"is_favorited" db:"is_favorited"` // Whether user sending request has added threads to favorites
+	Resolved    bool   `json:"resolved" db:"resolved"`         // Whether a post has been accepted as the answer
+	Tags        []Tag  `json:"tags" db:"tags"`
 }
 
 // DbThread models how a thread is stored in the database.
@@ -39,6 +42,43 @@ type DbThread struct {
 	IsAvailable  bool      `json:"is_available" db:"is_available"`
 	Preview      string    `json:"preview" db:"preview"`
 	IsAnon       bool      `json:"is_anon" db:"is_anon"`
+	Pinned       bool      `json:"pinned" db:"pinned"`               // Listed before all other threads
+	Locked       bool      `json:"locked" db:"locked"`               // No new posts allowed
+	Announcement bool      `json:"announcement" db:"announcement"`   // Pushed to the notification feed of all users
+	AcceptedPost *string   `json:"accepted_post" db:"accepted_post"` // Post accepted as the answer, nil if unresolved
+}
+
+// Values of ThreadFilter.Status
+const (
+	ThreadResolved   = "resolved"   // Threads with an accepted answer
+	ThreadUnresolved = "unresolved" // Threads without an accepted answer
+)
+
+// Filters applied when listing threads. Empty fields do not filter.
+type ThreadFilter struct {
+	Search   string   // Free-text search on title, content and author
+	Tags     []string // Tag ids
+	Match    string   // TagMatchAny or TagMatchAll
+	Status   string   // ThreadResolved or ThreadUnresolved
+	Endorsed bool     // Only endorsed threads if true
+}
+
+// Threads can also be sorted by time endorsed, with threads which are not endorsed last
+const TIME_ENDORSED_COL SortColumn = "time_endorsed"
+
+// Convert string to SortColumn of threads
+func StrToThreadSortColumn(s string) SortColumn {
+	if s == string(TIME_ENDORSED_COL) {
+		return TIME_ENDORSED_COL
+	}
+	return StrToSortColumn(s)
+}
+
+// Staff-controlled states of a thread. Nil fields are left unchanged.
+type ThreadState struct {
+	Pinned       *bool `json:"pinned"`
+	Locked       *bool `json:"locked"`
+	Announcement *bool `json:"announcement"`
 }
 
 // Create a new thread with a unique thread ID
@@ -0,0 +1,25 @@
+package models
+
+import "time"
+
+// Types of content in the trash
+const (
+	TrashThread  = "thread"
+	TrashPost    = "post"
+	TrashArticle = "article"
+	TrashComment = "comment"
+)
+
+// Soft deleted content, as listed to staff. Posts deleted together with their thread are not listed separately, as
+// they are restored with the thread.
+type TrashItem struct {
+	ContentType string     `json:"content_type" db:"content_type"`
+	ContentID   string     `json:"content_id" db:"content_id"`
+	ParentID    *string    `json:"parent_id" db:"parent_id"` // Thread of a post or article of a comment
+	Title       string     `json:"title" db:"title"`         // Empty for comments
+	Preview     string     `json:"preview" db:"preview"`
+	AuthorUid   string     `json:"author_uid" db:"author"`
+	Author      string     `json:"author" db:"author_name"`
+	DeletedBy   *string    `json:"deleted_by" db:"deleted_by"`     // Nil for content deleted before deletions were recorded
+	TimeDeleted *time.Time `json:"time_deleted" db:"time_deleted"` // Nil for content deleted before deletions were recorded
+}
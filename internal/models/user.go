@@ -1,7 +1,6 @@
 package models
 
 import (
-	"fmt"
 	"time"
 
 	"github.com/ntu-onemdp/onemdp-backend/internal/semester"
@@ -18,14 +17,80 @@ type User struct {
 	ProfilePhoto *[]byte    `json:"-" db:"profile_photo"`
 	Status       string     `json:"status" db:"status"`
 	Karma        int        `json:"karma" db:"karma"`
+
+	TutorialGroup *string `json:"tutorial_group" db:"tutorial_group"`
+
+	StatusReason   *string    `json:"status_reason" db:"status_reason"`     // Reason given by admin for suspension, ban or deactivation
+	SuspendedUntil *time.Time `json:"suspended_until" db:"suspended_until"` // Suspension is lifted after this time. Nil for indefinite suspensions.
+}
+
+// Account statuses. Only active users can log in or use the api.
+const (
+	StatusActive      = "active"
+	StatusSuspended   = "suspended"   // Temporarily blocked, content stays visible
+	StatusBanned      = "banned"      // Permanently blocked
+	StatusDeactivated = "deactivated" // Semester has ended
+	StatusRemoved     = "removed"     // Removed by admin and personal data erased. Cannot be reinstated.
+)
+
+// What to do with the content of a removed user
+const (
+	RemovalAnonymise = "anonymise" // Content is kept under the placeholder deleted user
+	RemovalReassign  = "reassign"  // Content is transferred to another user
+)
+
+// Account status of a user
+type AccountStatus struct {
+	Status         string     `json:"status" db:"status"`
+	StatusReason   *string    `json:"status_reason" db:"status_reason"`
+	SuspendedUntil *time.Time `json:"suspended_until" db:"suspended_until"`
+}
+
+// Returns true if suspension has an expiry which has passed
+func (s *AccountStatus) SuspensionExpired() bool {
+	return s.Status == StatusSuspended && s.SuspendedUntil != nil && s.SuspendedUntil.Before(time.Now())
+}
+
+// Error code returned to clients when the account is not active
+func (s *AccountStatus) ErrorCode() string {
+	switch s.Status {
+	case StatusSuspended:
+		return "ACCOUNT_SUSPENDED"
+	case StatusBanned:
+		return "ACCOUNT_BANNED"
+	case StatusDeactivated:
+		return "ACCOUNT_DEACTIVATED"
+	case StatusRemoved:
+		return "ACCOUNT_REMOVED"
+	default:
+		return "ACCOUNT_INACTIVE"
+	}
+}
+
+// Error message returned to clients when the account is not active
+func (s *AccountStatus) ErrorMessage() string {
+	switch s.Status {
+	case StatusSuspended:
+		return "Your account has been suspended"
+	case StatusBanned:
+		return "Your account has been banned"
+	case StatusDeactivated:
+		return "Your account has been deactivated"
+	case StatusRemoved:
+		return "Your account has been removed"
+	default:
+		return "Your account is not active"
+	}
 }
 
 // User pending registration
 type PendingUser struct {
-	Email       string    `json:"email" db:"email"`
-	Role        string    `json:"role" db:"role"`
-	Semester    *string   `json:"semester" db:"semester"`
-	TimeCreated time.Time `json:"time_created" db:"time_created"`
+	Email         string    `json:"email" db:"email"`
+	Role          string    `json:"role" db:"role"`
+	Semester      *string   `json:"semester" db:"semester"`
+	TimeCreated   time.Time `json:"time_created" db:"time_created"`
+	Name          *string   `json:"name" db:"name"` // Used if the user has no name set in SSO
+	TutorialGroup *string   `json:"tutorial_group" db:"tutorial_group"`
 }
 
 // Initialize a new user for insertion into user table after registration
@@ -38,23 +103,28 @@ func CreateUser(uid string, name string, email string, role string) *User {
 		DateCreated:  time.Now(),
 		Semester:     semester.Service.GetCurrentSem(),
 		ProfilePhoto: nil,
-		Status:       "active",
+		Status:       StatusActive,
 		Karma:        0,
 	}
 }
 
-// Initialize a new user from pending users
+// Initialize a new user from pending users. The name from the roster is used if name is empty.
 func CreateUserFromPending(user *PendingUser, uid string, name string) *User {
+	if name == "" && user.Name != nil {
+		name = *user.Name
+	}
+
 	return &User{
-		Uid:          uid,
-		Name:         name,
-		Email:        user.Email,
-		Role:         user.Role,
-		DateCreated:  time.Now(),
-		Semester:     user.Semester,
-		ProfilePhoto: nil,
-		Status:       "active",
-		Karma:        0,
+		Uid:           uid,
+		Name:          name,
+		Email:         user.Email,
+		Role:          user.Role,
+		DateCreated:   time.Now(),
+		Semester:      user.Semester,
+		ProfilePhoto:  nil,
+		Status:        StatusActive,
+		Karma:         0,
+		TutorialGroup: user.TutorialGroup,
 	}
 }
 
@@ -70,7 +140,7 @@ func CreateSpecialUser(uid string, name string, email string, role string) *User
 		DateCreated:  time.Now(),
 		Semester:     &semester,
 		ProfilePhoto: nil,
-		Status:       "active",
+		Status:       StatusActive,
 		Karma:        0,
 	}
 }
@@ -79,7 +149,7 @@ func CreateSpecialUser(uid string, name string, email string, role string) *User
 func CreatePendingUser(email string, role string) *PendingUser {
 	// Defaults to student if not provided
 	if role == "" {
-		role = "student"
+		role = RoleStudent
 	}
 
 	return &PendingUser{
@@ -99,47 +169,6 @@ type UserProfile struct {
 	Semester     string  `json:"semester"`
 	Karma        int     `json:"karma"`
 	Role         string  `json:"role"`
-}
-
-// Role levels
-type UserRole int
 
-const (
-	Unknown          = 0
-	Student UserRole = iota
-	Bot
-	Staff
-	Admin
-)
-
-func ParseRole(role string) (UserRole, error) {
-	switch role {
-	case "unknown", "deleted":
-		return Unknown, nil
-	case "student":
-		return Student, nil
-	case "staff":
-		return Staff, nil
-	case "admin":
-		return Admin, nil
-	case "bot":
-		return Bot, nil
-	default:
-		return Student, fmt.Errorf("unknown role: %s", role)
-	}
-}
-
-func (r UserRole) String() string {
-	switch r {
-	case Student:
-		return "student"
-	case Bot:
-		return "bot"
-	case Staff:
-		return "Staff"
-	case Admin:
-		return "admin"
-	default:
-		return "unknown"
-	}
+	Impersonation *Impersonation `json:"impersonation,omitempty" db:"-"` // Set when an admin is viewing as this user
 }
@@ -0,0 +1,42 @@
+package models
+
+import "time"
+
+// A row of any table, keyed by column name
+type Record map[string]any
+
+// Everything stored about a user, returned for data protection requests.
+// Secrets such as session token hashes and api key hashes are left out.
+type UserExport struct {
+	Uid        string    `json:"uid"`
+	ExportedAt time.Time `json:"exported_at"`
+
+	Account      Record   `json:"account"` // Users table row, including profile photo as base64
+	PendingUsers []Record `json:"pending_users"`
+
+	Threads  []Record `json:"threads"`
+	Posts    []Record `json:"posts"`
+	Articles []Record `json:"articles"`
+	Comments []Record `json:"comments"`
+	Drafts   []Record `json:"drafts"`
+	Files    []Record `json:"files"`
+
+	PostRevisions    []Record `json:"post_revisions"`    // Previous versions of their posts, and versions they replaced
+	ArticleRevisions []Record `json:"article_revisions"` // Previous versions of their articles, and versions they replaced
+
+	Mentions  []Record `json:"mentions"` // Mentions of the user, and mentions in their content
+	Polls     []Record `json:"polls"`
+	PollVotes []Record `json:"poll_votes"`
+
+	Likes     []Record `json:"likes"`
+	Favorites []Record `json:"favorites"`
+	Views     []Record `json:"views"`
+
+	Notifications     []Record `json:"notifications"` // Notifications received by the user, and notifications they triggered
+	NotificationMutes []Record `json:"notification_mutes"`
+
+	Sessions     []Record `json:"sessions"`
+	ApiKeys      []Record `json:"api_keys"`
+	Redemptions  []Record `json:"enrolment_code_redemptions"`
+	AuthFailures []Record `json:"auth_failures"`
+}
@@ -0,0 +1,13 @@
+package notification
+
+import (
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/ntu-onemdp/onemdp-backend/internal/utils"
+)
+
+func Init(db *pgxpool.Pool) {
+	repo = &NotificationRepository{db: db}
+	Service = NewNotificationService(repo)
+
+	utils.Logger.Info().Msg("Notification service initialized")
+}
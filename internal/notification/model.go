@@ -0,0 +1,87 @@
+package notification
+
+import (
+	"time"
+
+	gonanoid "github.com/matoous/go-nanoid/v2"
+	constants "github.com/ntu-onemdp/onemdp-backend/config"
+)
+
+// Types of notification
+const (
+	TypeReply        = "reply"        // Post in a thread of the user, or reply to a post or comment of the user
+	TypeLike         = "like"         // Like on content of the user
+	TypeMention      = "mention"      // User mentioned in content
+	TypeComment      = "comment"      // Comment on an article of the user
+	TypeEndorsement  = "endorsement"  // Content of the user endorsed by an instructor
+	TypeAnnouncement = "announcement" // Thread announced by staff
+	TypeEduvisor     = "eduvisor"     // Eduvisor answered a thread of the user
+)
+
+// All types of notification
+var Types = []string{TypeReply, TypeLike, TypeMention, TypeComment, TypeEndorsement, TypeAnnouncement, TypeEduvisor}
+
+// Types of notification users can mute, in the order preferences are listed. Announcements always reach every user.
+var MutableTypes = []string{TypeReply, TypeLike, TypeMention, TypeComment, TypeEndorsement, TypeEduvisor}
+
+// Returns true if t is a type of notification
+func IsValidType(t string) bool {
+	for _, notificationType := range Types {
+		if t == notificationType {
+			return true
+		}
+	}
+	return false
+}
+
+// Returns true if users can mute notifications of type t
+func IsMutableType(t string) bool {
+	for _, notificationType := range MutableTypes {
+		if t == notificationType {
+			return true
+		}
+	}
+	return false
+}
+
+type Notification struct {
+	NotificationID string    `json:"notification_id" db:"notification_id"`
+	Uid            string    `json:"uid" db:"uid"` // Recipient
+	Type           string    `json:"type" db:"type"`
+	Actor          *string   `json:"actor" db:"actor"`           // User who triggered the notification, nil for system notifications
+	ActorName      *string   `json:"actor_name" db:"actor_name"` // Name of actor. Only set when listing notifications.
+	ContentID      *string   `json:"content_id" db:"content_id"` // Content the notification is about
+	Message        string    `json:"message" db:"message"`
+	IsRead         bool      `json:"is_read" db:"is_read"`
+	TimeCreated    time.Time `json:"time_created" db:"time_created"`
+}
+
+// Whether a user receives a type of notification
+type Preference struct {
+	Type  string `json:"type" db:"type"`
+	Muted bool   `json:"muted" db:"muted"`
+}
+
+// Page of notifications of a user
+type Metadata struct {
+	Total    int `json:"total"`
+	NumPages int `json:"num_pages"`
+	Unread   int `json:"unread"`
+}
+
+func NewNotification(uid string, notificationType string, actor *string, contentID *string, message string) *Notification {
+	return &Notification{
+		NotificationID: newNotificationID(),
+		Uid:            uid,
+		Type:           notificationType,
+		Actor:          actor,
+		ContentID:      contentID,
+		Message:        message,
+		IsRead:         false,
+		TimeCreated:    time.Now(),
+	}
+}
+
+func newNotificationID() string {
+	return "n" + gonanoid.Must(constants.CONTENT_ID_LENGTH)
+}
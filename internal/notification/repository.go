@@ -0,0 +1,192 @@
+package notification
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	constants "github.com/ntu-onemdp/onemdp-backend/config"
+	"github.com/ntu-onemdp/onemdp-backend/internal/utils"
+)
+
+const NOTIFICATIONS_TABLE = "notifications"
+const NOTIFICATION_MUTES_TABLE = "notification_mutes"
+
+type NotificationRepository struct {
+	db *pgxpool.Pool
+}
+
+var repo *NotificationRepository
+
+var notificationColumns = []string{"notification_id", "uid", "type", "actor", "content_id", "message", "is_read", "time_created"}
+
+// Insert notification unless the recipient has muted its type. Returns true if the notification was inserted.
+func (r *NotificationRepository) insert(n *Notification) (bool, error) {
+	query := fmt.Sprintf(`
+	INSERT INTO %s (%s)
+	SELECT $1, $2, $3, $4, $5, $6, $7, $8
+	WHERE NOT EXISTS (SELECT 1 FROM %s M WHERE M.uid = $2 AND M.type = $3);`, NOTIFICATIONS_TABLE, strings.Join(notificationColumns, ", "), NOTIFICATION_MUTES_TABLE)
+
+	tag, err := r.db.Exec(context.Background(), query, n.NotificationID, n.Uid, n.Type, n.Actor, n.ContentID, n.Message, n.IsRead, n.TimeCreated)
+	if err != nil {
+		utils.Logger.Error().Err(err).Str("uid", n.Uid).Str("type", n.Type).Msg("Error inserting notification")
+		return false, err
+	}
+
+	return tag.RowsAffected() > 0, nil
+}
+
+// Insert a copy of the notification for every active user other than the actor who has not muted its type.
+// Uid of n is ignored. Returns number of notifications inserted.
+func (r *NotificationRepository) insertForAll(n *Notification) (int64, error) {
+	ctx := context.Background()
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		utils.Logger.Error().Err(err).Msg("Error starting transaction")
+		return 0, err
+	}
+	defer tx.Rollback(ctx)
+
+	actor := ""
+	if n.Actor != nil {
+		actor = *n.Actor
+	}
+
+	query := fmt.Sprintf(`
+	SELECT uid FROM users U
+	WHERE status = 'active' AND uid <> $1 AND uid <> $2
+		AND NOT EXISTS (SELECT 1 FROM %s M WHERE M.uid = U.uid AND M.type = $3);`, NOTIFICATION_MUTES_TABLE)
+	rows, _ := tx.Query(ctx, query, actor, constants.DELETED_USER_UID, n.Type)
+	uids, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		utils.Logger.Error().Err(err).Msg("Error retrieving recipients of notification")
+		return 0, err
+	}
+
+	count, err := tx.CopyFrom(ctx, pgx.Identifier{NOTIFICATIONS_TABLE}, notificationColumns, pgx.CopyFromSlice(len(uids), func(i int) ([]any, error) {
+		return []any{newNotificationID(), uids[i], n.Type, n.Actor, n.ContentID, n.Message, false, n.TimeCreated}, nil
+	}))
+	if err != nil {
+		utils.Logger.Error().Err(err).Str("type", n.Type).Msg("Error inserting notifications")
+		return 0, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		utils.Logger.Error().Err(err).Msg("Error committing transaction")
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// Retrieve notifications of user in given page, newest first. Only unread notifications are returned if unreadOnly.
+func (r *NotificationRepository) getAll(uid string, unreadOnly bool, page int, size int) ([]Notification, error) {
+	query := fmt.Sprintf(`
+	SELECT N.notification_id, N.uid, N.type, N.actor, U.name AS actor_name, N.content_id, N.message, N.is_read, N.time_created
+	FROM %s N
+	LEFT JOIN users U ON U.uid = N.actor
+	WHERE N.uid = $1 AND (NOT $2 OR NOT N.is_read)
+	ORDER BY N.time_created DESC
+	LIMIT $3 OFFSET $4;`, NOTIFICATIONS_TABLE)
+
+	rows, _ := r.db.Query(context.Background(), query, uid, unreadOnly, size, (page-1)*size)
+	notifications, err := pgx.CollectRows(rows, pgx.RowToStructByName[Notification])
+	if err != nil {
+		utils.Logger.Error().Err(err).Str("uid", uid).Msg("Error retrieving notifications")
+		return nil, err
+	}
+
+	return notifications, nil
+}
+
+// Count notifications of user, and how many of them are unread
+func (r *NotificationRepository) count(uid string) (int, int, error) {
+	query := fmt.Sprintf(`SELECT COUNT(*), COUNT(*) FILTER (WHERE NOT is_read) FROM %s WHERE uid = $1;`, NOTIFICATIONS_TABLE)
+
+	var total, unread int
+	if err := r.db.QueryRow(context.Background(), query, uid).Scan(&total, &unread); err != nil {
+		utils.Logger.Error().Err(err).Str("uid", uid).Msg("Error counting notifications")
+		return 0, 0, err
+	}
+
+	return total, unread, nil
+}
+
+// Mark notification of user as read. Returns pgx.ErrNoRows if the user has no such notification.
+func (r *NotificationRepository) markRead(uid string, notificationID string) error {
+	query := fmt.Sprintf(`UPDATE %s SET is_read = true WHERE notification_id = $1 AND uid = $2;`, NOTIFICATIONS_TABLE)
+
+	tag, err := r.db.Exec(context.Background(), query, notificationID, uid)
+	if err != nil {
+		utils.Logger.Error().Err(err).Str("notification id", notificationID).Msg("Error marking notification as read")
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+
+	return nil
+}
+
+// Mark all notifications of user as read. Returns number of notifications marked.
+func (r *NotificationRepository) markAllRead(uid string) (int64, error) {
+	query := fmt.Sprintf(`UPDATE %s SET is_read = true WHERE uid = $1 AND NOT is_read;`, NOTIFICATIONS_TABLE)
+
+	tag, err := r.db.Exec(context.Background(), query, uid)
+	if err != nil {
+		utils.Logger.Error().Err(err).Str("uid", uid).Msg("Error marking notifications as read")
+		return 0, err
+	}
+
+	return tag.RowsAffected(), nil
+}
+
+// Retrieve types of notification muted by user
+func (r *NotificationRepository) getMuted(uid string) ([]string, error) {
+	query := fmt.Sprintf(`SELECT type FROM %s WHERE uid = $1;`, NOTIFICATION_MUTES_TABLE)
+
+	rows, _ := r.db.Query(context.Background(), query, uid)
+	muted, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		utils.Logger.Error().Err(err).Str("uid", uid).Msg("Error retrieving muted notification types")
+		return nil, err
+	}
+
+	return muted, nil
+}
+
+// Replace types of notification muted by user
+func (r *NotificationRepository) setMuted(uid string, muted []string) error {
+	ctx := context.Background()
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		utils.Logger.Error().Err(err).Msg("Error starting transaction")
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	query := fmt.Sprintf(`DELETE FROM %s WHERE uid = $1;`, NOTIFICATION_MUTES_TABLE)
+	if _, err := tx.Exec(ctx, query, uid); err != nil {
+		utils.Logger.Error().Err(err).Str("uid", uid).Msg("Error removing muted notification types")
+		return err
+	}
+
+	query = fmt.Sprintf(`INSERT INTO %s (uid, type) VALUES ($1, $2);`, NOTIFICATION_MUTES_TABLE)
+	for _, t := range muted {
+		if _, err := tx.Exec(ctx, query, uid, t); err != nil {
+			utils.Logger.Error().Err(err).Str("uid", uid).Str("type", t).Msg("Error muting notification type")
+			return err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		utils.Logger.Error().Err(err).Msg("Error committing transaction")
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,152 @@
+package notification
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/ntu-onemdp/onemdp-backend/internal/utils"
+)
+
+type NotificationService struct {
+	repo *NotificationRepository
+}
+
+var Service *NotificationService
+
+// Returned when updating preferences of a type of notification that does not exist
+var ErrInvalidType = errors.New("invalid notification type")
+var ErrNotMutable = errors.New("notification type cannot be muted")
+
+func NewNotificationService(repo *NotificationRepository) *NotificationService {
+	return &NotificationService{repo: repo}
+}
+
+// Push an announcement of a thread to every active user other than its author
+func (s *NotificationService) Announce(threadID string, title string, actor string) error {
+	n := NewNotification("", TypeAnnouncement, &actor, &threadID, title)
+
+	count, err := s.repo.insertForAll(n)
+	if err != nil {
+		return err
+	}
+
+	utils.Logger.Info().Str("thread id", threadID).Int64("recipients", count).Msg("Announcement pushed to users")
+	return nil
+}
+
+// Notify a user, unless they have muted notificationType. actor is nil if the user who triggered the notification is
+// hidden.
+func (s *NotificationService) Notify(uid string, notificationType string, actor *string, contentID string, message string) error {
+	n := NewNotification(uid, notificationType, actor, &contentID, message)
+
+	inserted, err := s.repo.insert(n)
+	if err != nil {
+		return err
+	}
+
+	if !inserted {
+		utils.Logger.Trace().Str("uid", uid).Str("type", notificationType).Msg("Notification type muted by user")
+		return nil
+	}
+
+	utils.Logger.Debug().Str("uid", uid).Str("type", notificationType).Msg("Notification sent")
+	return nil
+}
+
+// Retrieve notifications of user in given page, newest first, together with the number of notifications and how many
+// of them are unread. Only unread notifications are returned if unreadOnly.
+func (s *NotificationService) GetAll(uid string, unreadOnly bool, page int, size int) ([]Notification, *Metadata, error) {
+	notifications, err := s.repo.getAll(uid, unreadOnly, page, size)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	total, unread, err := s.repo.count(uid)
+	if err != nil {
+		return nil, nil, err
+	}
+	if unreadOnly {
+		total = unread
+	}
+
+	metadata := &Metadata{
+		Total:    total,
+		NumPages: int(math.Ceil(float64(total) / float64(size))),
+		Unread:   unread,
+	}
+
+	return notifications, metadata, nil
+}
+
+// Number of unread notifications of user
+func (s *NotificationService) CountUnread(uid string) (int, error) {
+	_, unread, err := s.repo.count(uid)
+	return unread, err
+}
+
+// Mark notification of user as read
+func (s *NotificationService) MarkRead(uid string, notificationID string) error {
+	return s.repo.markRead(uid, notificationID)
+}
+
+// Mark all notifications of user as read. Returns number of notifications marked.
+func (s *NotificationService) MarkAllRead(uid string) (int64, error) {
+	return s.repo.markAllRead(uid)
+}
+
+// Retrieve whether user receives each type of notification they can mute
+func (s *NotificationService) GetPreferences(uid string) ([]Preference, error) {
+	muted, err := s.repo.getMuted(uid)
+	if err != nil {
+		return nil, err
+	}
+
+	isMuted := make(map[string]bool, len(muted))
+	for _, t := range muted {
+		isMuted[t] = true
+	}
+
+	preferences := make([]Preference, 0, len(MutableTypes))
+	for _, t := range MutableTypes {
+		preferences = append(preferences, Preference{Type: t, Muted: isMuted[t]})
+	}
+
+	return preferences, nil
+}
+
+// Update whether user receives each type of notification. Types not listed in preferences are left unchanged.
+func (s *NotificationService) UpdatePreferences(uid string, preferences []Preference) ([]Preference, error) {
+	current, err := s.GetPreferences(uid)
+	if err != nil {
+		return nil, err
+	}
+
+	isMuted := make(map[string]bool, len(current))
+	for _, preference := range current {
+		isMuted[preference.Type] = preference.Muted
+	}
+	for _, preference := range preferences {
+		if !IsValidType(preference.Type) {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidType, preference.Type)
+		}
+		if !IsMutableType(preference.Type) {
+			return nil, fmt.Errorf("%w: %s", ErrNotMutable, preference.Type)
+		}
+		isMuted[preference.Type] = preference.Muted
+	}
+
+	muted := []string{}
+	for _, t := range MutableTypes {
+		if isMuted[t] {
+			muted = append(muted, t)
+		}
+	}
+
+	if err := s.repo.setMuted(uid, muted); err != nil {
+		return nil, err
+	}
+
+	utils.Logger.Info().Str("uid", uid).Strs("muted", muted).Msg("Notification preferences updated")
+	return s.GetPreferences(uid)
+}
@@ -0,0 +1,103 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/ntu-onemdp/onemdp-backend/internal/utils"
+)
+
+// Accept post as the answer of thread and give its author pts karma. A previously accepted answer is replaced and the
+// karma given for it is taken back. Returns pgx.ErrNoRows if the thread does not exist.
+func (r *ThreadsRepository) AcceptAnswer(threadID string, postID string, postAuthor string, acceptedBy string, pts int) error {
+	ctx := context.Background()
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		utils.Logger.Error().Err(err).Msg("Error starting transaction")
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if err := revokeAcceptedAnswer(ctx, tx, threadID, nil); err != nil {
+		return err
+	}
+
+	query := fmt.Sprintf(`
+	UPDATE %s
+	SET accepted_post = $2, accepted_by = $3, time_accepted = NOW(), accepted_karma = $4
+	WHERE thread_id = $1 AND is_available = true;`, THREADS_TABLE)
+
+	result, err := tx.Exec(ctx, query, threadID, postID, acceptedBy, pts)
+	if err != nil {
+		utils.Logger.Error().Err(err).Str("thread id", threadID).Msg("Error accepting answer")
+		return err
+	}
+	if result.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+
+	query = fmt.Sprintf(`UPDATE %s SET karma = karma + $2 WHERE uid = $1;`, USERS_TABLE)
+	if _, err := tx.Exec(ctx, query, postAuthor, pts); err != nil {
+		utils.Logger.Error().Err(err).Msg("Error updating karma of accepted answer's author")
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		utils.Logger.Error().Err(err).Msg("Error committing transaction")
+		return err
+	}
+
+	utils.Logger.Info().Str("thread id", threadID).Str("post id", postID).Str("accepted by", acceptedBy).Msg("Answer accepted")
+	return nil
+}
+
+// Remove the accepted answer of thread and take back the karma given for it
+func (r *ThreadsRepository) UnacceptAnswer(threadID string) error {
+	ctx := context.Background()
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		utils.Logger.Error().Err(err).Msg("Error starting transaction")
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if err := revokeAcceptedAnswer(ctx, tx, threadID, nil); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		utils.Logger.Error().Err(err).Msg("Error committing transaction")
+		return err
+	}
+
+	utils.Logger.Info().Str("thread id", threadID).Msg("Accepted answer removed")
+	return nil
+}
+
+// Clear the accepted answer of thread within a transaction and take back the karma given for it. If postID is not nil,
+// the answer is only cleared if it is that post.
+func revokeAcceptedAnswer(ctx context.Context, tx pgx.Tx, threadID string, postID *string) error {
+	query := fmt.Sprintf(`
+	WITH old AS (
+		SELECT T.thread_id, T.accepted_karma, P.author
+		FROM %[1]s T INNER JOIN %[2]s P ON P.post_id = T.accepted_post
+		WHERE T.thread_id = $1 AND ($2::text IS NULL OR T.accepted_post = $2)
+		FOR UPDATE OF T
+	), cleared AS (
+		UPDATE %[1]s T
+		SET accepted_post = NULL, accepted_by = NULL, time_accepted = NULL, accepted_karma = 0
+		FROM old WHERE T.thread_id = old.thread_id
+	)
+	UPDATE %[3]s U SET karma = GREATEST(U.karma - old.accepted_karma, 0)
+	FROM old WHERE U.uid = old.author;`, THREADS_TABLE, POSTS_TABLE, USERS_TABLE)
+
+	if _, err := tx.Exec(ctx, query, threadID, postID); err != nil {
+		utils.Logger.Error().Err(err).Str("thread id", threadID).Msg("Error removing accepted answer")
+		return err
+	}
+
+	return nil
+}
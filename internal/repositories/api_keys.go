@@ -0,0 +1,95 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/ntu-onemdp/onemdp-backend/internal/models"
+	"github.com/ntu-onemdp/onemdp-backend/internal/utils"
+)
+
+// Api keys table name in db
+const API_KEYS_TABLE = "api_keys"
+
+type ApiKeysRepository struct {
+	db *pgxpool.Pool
+}
+
+var ApiKeys *ApiKeysRepository
+
+// Insert new api key into the database. Returns nil on success.
+func (r *ApiKeysRepository) Insert(key *models.ApiKey) error {
+	query := fmt.Sprintf(`
+	INSERT INTO %s (key_id, key_hash, name, owner_uid, max_role, scopes, time_created, expires_at)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8);`, API_KEYS_TABLE)
+
+	if _, err := r.db.Exec(context.Background(), query, key.KeyID, key.KeyHash, key.Name, key.OwnerUid, key.MaxRole, key.Scopes, key.TimeCreated, key.ExpiresAt); err != nil {
+		utils.Logger.Error().Err(err).Str("name", key.Name).Msg("Error inserting api key into database")
+		return err
+	}
+
+	utils.Logger.Info().Str("key id", key.KeyID).Str("owner", key.OwnerUid).Msgf("Api key %s created", key.Name)
+	return nil
+}
+
+// Retrieve an active (not revoked and not expired) api key by its hash.
+// Returns pgx.ErrNoRows if no active key matches.
+func (r *ApiKeysRepository) GetActiveByHash(keyHash string) (*models.ApiKey, error) {
+	query := fmt.Sprintf(`SELECT * FROM %s WHERE key_hash=$1 AND revoked_at IS NULL AND (expires_at IS NULL OR expires_at > NOW());`, API_KEYS_TABLE)
+
+	row, _ := r.db.Query(context.Background(), query, keyHash)
+	key, err := pgx.CollectOneRow(row, pgx.RowToAddrOfStructByName[models.ApiKey])
+	if err != nil {
+		utils.Logger.Warn().Err(err).Msg("No active api key found")
+		return nil, err
+	}
+
+	return key, nil
+}
+
+// Retrieve all api keys, newest first.
+func (r *ApiKeysRepository) GetAll() ([]models.ApiKey, error) {
+	query := fmt.Sprintf(`SELECT * FROM %s ORDER BY time_created DESC;`, API_KEYS_TABLE)
+
+	rows, _ := r.db.Query(context.Background(), query)
+	keys, err := pgx.CollectRows(rows, pgx.RowToStructByName[models.ApiKey])
+	if err != nil {
+		utils.Logger.Error().Err(err).Msg("Error retrieving api keys")
+		return nil, err
+	}
+
+	return keys, nil
+}
+
+// Record that the key has been used.
+func (r *ApiKeysRepository) UpdateLastUsed(keyID string) error {
+	query := fmt.Sprintf(`UPDATE %s SET last_used=NOW() WHERE key_id=$1;`, API_KEYS_TABLE)
+
+	if _, err := r.db.Exec(context.Background(), query, keyID); err != nil {
+		utils.Logger.Error().Err(err).Str("key id", keyID).Msg("Error updating last used time of api key")
+		return err
+	}
+
+	return nil
+}
+
+// Revoke an api key. Returns pgx.ErrNoRows if the key does not exist or was already revoked.
+func (r *ApiKeysRepository) Revoke(keyID string) error {
+	query := fmt.Sprintf(`UPDATE %s SET revoked_at=NOW() WHERE key_id=$1 AND revoked_at IS NULL;`, API_KEYS_TABLE)
+
+	res, err := r.db.Exec(context.Background(), query, keyID)
+	if err != nil {
+		utils.Logger.Error().Err(err).Str("key id", keyID).Msg("Error revoking api key")
+		return err
+	}
+
+	if res.RowsAffected() == 0 {
+		utils.Logger.Warn().Str("key id", keyID).Msg("Api key not found or already revoked")
+		return pgx.ErrNoRows
+	}
+
+	utils.Logger.Info().Str("key id", keyID).Msg("Api key revoked")
+	return nil
+}
@@ -0,0 +1,106 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/ntu-onemdp/onemdp-backend/internal/models"
+	"github.com/ntu-onemdp/onemdp-backend/internal/utils"
+)
+
+// Article revisions table name in db
+const ARTICLE_REVISIONS_TABLE = "article_revisions"
+
+// Update title, content and preview of an available article. The version being replaced is saved as a new revision in
+// the same transaction. Nothing is saved if neither the title nor the content has changed.
+// Returns pgx.ErrNoRows if the article does not exist or has been deleted.
+func (r *ArticleRepository) Update(articleID string, title string, content string, preview string, editor string) error {
+	ctx := context.Background()
+
+	tx, err := r.Db.Begin(ctx)
+	if err != nil {
+		utils.Logger.Error().Err(err).Msg("Error starting transaction for updating article")
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	// Lock the article so that concurrent edits get consecutive revision numbers
+	query := fmt.Sprintf(`SELECT title, content FROM %s WHERE article_id=$1 AND is_available=TRUE FOR UPDATE;`, ARTICLES_TABLE)
+
+	var oldTitle, oldContent string
+	if err := tx.QueryRow(ctx, query, articleID).Scan(&oldTitle, &oldContent); err != nil {
+		utils.Logger.Warn().Err(err).Str("article id", articleID).Msg("Article to update not found")
+		return err
+	}
+
+	if oldTitle == title && oldContent == content {
+		utils.Logger.Debug().Str("article id", articleID).Msg("Article unchanged, no revision saved")
+		return nil
+	}
+
+	query = fmt.Sprintf(`
+	INSERT INTO %s (article_id, revision, title, content, replaced_by)
+	SELECT $1, COALESCE(MAX(revision), 0) + 1, $2, $3, $4 FROM %s WHERE article_id=$1;`, ARTICLE_REVISIONS_TABLE, ARTICLE_REVISIONS_TABLE)
+
+	if _, err := tx.Exec(ctx, query, articleID, oldTitle, oldContent, editor); err != nil {
+		utils.Logger.Error().Err(err).Str("article id", articleID).Msg("Error saving article revision")
+		return err
+	}
+
+	query = fmt.Sprintf(`UPDATE %s SET title=$2, content=$3, preview=$4, last_activity=NOW() WHERE article_id=$1;`, ARTICLES_TABLE)
+
+	if _, err := tx.Exec(ctx, query, articleID, title, content, preview); err != nil {
+		utils.Logger.Error().Err(err).Str("article id", articleID).Msg("Error updating article")
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		utils.Logger.Error().Err(err).Msg("Error committing transaction after updating article")
+		return err
+	}
+
+	utils.Logger.Info().Str("article id", articleID).Str("editor", editor).Msg("Article updated")
+	return nil
+}
+
+// Retrieve all previous versions of an article, newest first.
+func (r *ArticleRepository) GetRevisions(articleID string) ([]models.ArticleRevision, error) {
+	query := fmt.Sprintf(`SELECT * FROM %s WHERE article_id=$1 ORDER BY revision DESC;`, ARTICLE_REVISIONS_TABLE)
+
+	rows, _ := r.Db.Query(context.Background(), query, articleID)
+	revisions, err := pgx.CollectRows(rows, pgx.RowToStructByName[models.ArticleRevision])
+	if err != nil {
+		utils.Logger.Error().Err(err).Str("article id", articleID).Msg("Error retrieving article revisions")
+		return nil, err
+	}
+
+	return revisions, nil
+}
+
+// Retrieve the current version of an available article as revision 0. Returns pgx.ErrNoRows if it does not exist.
+func (r *ArticleRepository) GetCurrentVersion(articleID string) (*models.ArticleRevision, error) {
+	query := fmt.Sprintf(`SELECT title, content FROM %s WHERE article_id=$1 AND is_available=TRUE;`, ARTICLES_TABLE)
+
+	rev := &models.ArticleRevision{ArticleID: articleID}
+	if err := r.Db.QueryRow(context.Background(), query, articleID).Scan(&rev.Title, &rev.Content); err != nil {
+		utils.Logger.Warn().Err(err).Str("article id", articleID).Msg("Article not found")
+		return nil, err
+	}
+
+	return rev, nil
+}
+
+// Retrieve one previous version of an article. Returns pgx.ErrNoRows if it does not exist.
+func (r *ArticleRepository) GetRevision(articleID string, revision int) (*models.ArticleRevision, error) {
+	query := fmt.Sprintf(`SELECT * FROM %s WHERE article_id=$1 AND revision=$2;`, ARTICLE_REVISIONS_TABLE)
+
+	row, _ := r.Db.Query(context.Background(), query, articleID, revision)
+	rev, err := pgx.CollectOneRow(row, pgx.RowToAddrOfStructByName[models.ArticleRevision])
+	if err != nil {
+		utils.Logger.Warn().Err(err).Str("article id", articleID).Int("revision", revision).Msg("Article revision not found")
+		return nil, err
+	}
+
+	return rev, nil
+}
@@ -316,6 +316,19 @@ func (r *ArticleRepository) GetAuthor(articleID string) (string, error) {
 	return author, nil
 }
 
+// Get title of article by article ID
+func (r *ArticleRepository) GetTitle(articleID string) (string, error) {
+	query := fmt.Sprintf(`SELECT TITLE FROM %s WHERE ARTICLE_ID = $1;`, ARTICLES_TABLE)
+
+	var title string
+	if err := r.Db.QueryRow(context.Background(), query, articleID).Scan(&title); err != nil {
+		utils.Logger.Error().Err(err).Msgf("Error fetching title of article with ID %s", articleID)
+		return "", err
+	}
+
+	return title, nil
+}
+
 // Returns true if article exists in database
 func (r *ArticleRepository) IsAvailable(articleID string) bool {
 	query := fmt.Sprintf(`SELECT IS_AVAILABLE FROM %s WHERE ARTICLE_ID=$1;`, ARTICLES_TABLE)
@@ -331,7 +344,7 @@ func (r *ArticleRepository) IsAvailable(articleID string) bool {
 
 // Perform soft delete of an article by its ID.
 // It also updates the author's karma by subtracting the points for article creation.
-func (r *ArticleRepository) Delete(articleID string) error {
+func (r *ArticleRepository) Delete(articleID string, deletedBy string) error {
 	ctx := context.Background()
 
 	utils.Logger.Trace().Msgf("Deleting article with ID %s", articleID)
@@ -349,13 +362,13 @@ func (r *ArticleRepository) Delete(articleID string) error {
 	// Soft delete the article
 	query := fmt.Sprintf(`
 		UPDATE %s
-		SET IS_AVAILABLE = FALSE, last_activity = NOW()
+		SET IS_AVAILABLE = FALSE, last_activity = NOW(), DELETED_BY = $2, TIME_DELETED = NOW()
 		WHERE ARTICLE_ID = $1
 		AND IS_AVAILABLE = TRUE
 		RETURNING AUTHOR;
 	`, ARTICLES_TABLE)
 
-	if err := tx.QueryRow(ctx, query, articleID).Scan(&author); err != nil {
+	if err := tx.QueryRow(ctx, query, articleID, deletedBy).Scan(&author); err != nil {
 		utils.Logger.Error().Err(err).Msg("Error soft deleting article")
 		return err
 	}
@@ -363,12 +376,9 @@ func (r *ArticleRepository) Delete(articleID string) error {
 	utils.Logger.Trace().Msgf("Soft deleted article with ID %s", articleID)
 
 	// Update author's karma
-	query = fmt.Sprintf(`
-	UPDATE %s
-	SET karma = GREATEST(karma - %d, 0)
-	WHERE uid = $1`, USERS_TABLE, models.CREATE_ARTICLE_PTS)
+	query = takeKarmaQuery(models.CREATE_ARTICLE_PTS, ARTICLES_TABLE, "article_id")
 
-	if _, err := tx.Exec(ctx, query, author); err != nil {
+	if _, err := tx.Exec(ctx, query, author, articleID); err != nil {
 		utils.Logger.Error().Err(err).Msg("Error updating author's karma after article deletion")
 		return err
 	}
@@ -394,3 +404,46 @@ func (r *ArticleRepository) Delete(articleID string) error {
 	utils.Logger.Debug().Msgf("Successfully deleted article with ID %s and updated author's karma", articleID)
 	return nil
 }
+
+// Restore a deleted article and give back the karma that Delete took from the author. Comments are not deleted
+// with the article, so they need not be restored. Returns pgx.ErrNoRows if the article is not deleted.
+func (r *ArticleRepository) Restore(articleID string) error {
+	ctx := context.Background()
+
+	tx, err := r.Db.Begin(ctx)
+	if err != nil {
+		utils.Logger.Error().Err(err).Msg("Error starting transaction for restoring article")
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	var author string
+	query := fmt.Sprintf(`
+		UPDATE %s
+		SET IS_AVAILABLE = TRUE, DELETED_BY = NULL, TIME_DELETED = NULL
+		WHERE ARTICLE_ID = $1
+		AND IS_AVAILABLE = FALSE
+		RETURNING AUTHOR, KARMA_TAKEN;
+	`, ARTICLES_TABLE)
+
+	var karmaTaken int
+	if err := tx.QueryRow(ctx, query, articleID).Scan(&author, &karmaTaken); err != nil {
+		utils.Logger.Warn().Err(err).Msg("Error restoring article")
+		return err
+	}
+
+	query = fmt.Sprintf(`UPDATE %s SET karma = karma + $2 WHERE uid = $1`, USERS_TABLE)
+
+	if _, err := tx.Exec(ctx, query, author, karmaTaken); err != nil {
+		utils.Logger.Error().Err(err).Msg("Error restoring author's karma after article restore")
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		utils.Logger.Error().Err(err).Msg("Error committing transaction after restoring article")
+		return err
+	}
+
+	utils.Logger.Info().Msgf("Successfully restored article with ID %s", articleID)
+	return nil
+}
@@ -0,0 +1,50 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/ntu-onemdp/onemdp-backend/internal/models"
+	"github.com/ntu-onemdp/onemdp-backend/internal/utils"
+)
+
+// Auth failures table name in db
+const AUTH_FAILURES_TABLE = "auth_failures"
+
+type AuthFailuresRepository struct {
+	db *pgxpool.Pool
+}
+
+var AuthFailures *AuthFailuresRepository
+
+// Insert a failed attempt. Returns nil on success.
+func (r *AuthFailuresRepository) Insert(failure *models.AuthFailure) error {
+	query := fmt.Sprintf(`INSERT INTO %s (endpoint, ip, email, reason, user_agent) VALUES ($1, $2, $3, $4, $5);`, AUTH_FAILURES_TABLE)
+
+	if _, err := r.db.Exec(context.Background(), query, failure.Endpoint, failure.Ip, failure.Email, failure.Reason, failure.UserAgent); err != nil {
+		utils.Logger.Error().Err(err).Msg("Error inserting auth failure into database")
+		return err
+	}
+
+	return nil
+}
+
+// Retrieve most recent failed attempts, optionally filtered by ip and email.
+func (r *AuthFailuresRepository) GetRecent(ip string, email string, limit int) ([]models.AuthFailure, error) {
+	query := fmt.Sprintf(`
+	SELECT * FROM %s
+	WHERE ($1 = '' OR ip = $1) AND ($2 = '' OR email ILIKE $2)
+	ORDER BY time_created DESC
+	LIMIT $3;`, AUTH_FAILURES_TABLE)
+
+	rows, _ := r.db.Query(context.Background(), query, ip, email, limit)
+	failures, err := pgx.CollectRows(rows, pgx.RowToStructByName[models.AuthFailure])
+	if err != nil {
+		utils.Logger.Error().Err(err).Msg("Error retrieving auth failures")
+		return nil, err
+	}
+
+	return failures, nil
+}
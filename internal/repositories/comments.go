@@ -35,10 +35,10 @@ func (r *CommentsRepository) Create(comment *models.DbComment) error {
 
 	// Insert comment into comments table
 	query := fmt.Sprintf(`
-	INSERT INTO %s (comment_id, author, article_id, content)
-	VALUES ($1, $2, $3, $4);`, COMMENTS_TABLE)
+	INSERT INTO %s (comment_id, author, article_id, reply_to, content)
+	VALUES ($1, $2, $3, $4, $5);`, COMMENTS_TABLE)
 
-	if _, err = tx.Exec(ctx, query, comment.CommentID, comment.AuthorUID, comment.ArticleID, comment.Content); err != nil {
+	if _, err = tx.Exec(ctx, query, comment.CommentID, comment.AuthorUID, comment.ArticleID, comment.ReplyTo, comment.Content); err != nil {
 		utils.Logger.Error().Err(err).Msg("Error inserting comment into database")
 		return err
 	}
@@ -65,19 +65,21 @@ func (r *CommentsRepository) Create(comment *models.DbComment) error {
 }
 
 // Get comments by articleID. Returns slice of comment objects if found, nil otherwise.
+// Deleted comments are included with their author and content hidden, so that replies to them can still be shown.
 func (r *CommentsRepository) GetCommentsByArticleID(articleID string, uid string) ([]models.Comment, error) {
 	query := fmt.Sprintf(`
 	SELECT
 		C.COMMENT_ID,
-		C.AUTHOR,
+		CASE WHEN C.IS_AVAILABLE THEN C.AUTHOR ELSE $3 END AS AUTHOR,
 		C.ARTICLE_ID,
-		C.CONTENT,
+		C.REPLY_TO,
+		CASE WHEN C.IS_AVAILABLE THEN C.CONTENT ELSE $3 END AS CONTENT,
 		C.TIME_CREATED,
 		C.LAST_EDITED,
 		C.FLAGGED,
 		C.IS_AVAILABLE,
-		C.AUTHOR=$1 AS IS_AUTHOR,
-		U.NAME AS AUTHOR_NAME,
+		C.IS_AVAILABLE AND C.AUTHOR=$1 AS IS_AUTHOR,
+		CASE WHEN C.IS_AVAILABLE THEN U.NAME ELSE $3 END AS AUTHOR_NAME,
 		COALESCE(L.LIKE_COUNT, 0) AS NUM_LIKES,
 		COALESCE(UL.USER_LIKED, FALSE) AS IS_LIKED
 	FROM
@@ -103,13 +105,12 @@ func (r *CommentsRepository) GetCommentsByArticleID(articleID string, uid string
 		) UL ON UL.CONTENT_ID = C.COMMENT_ID
 	WHERE
 		C.ARTICLE_ID = $2 -- Article ID parameter
-		AND C.IS_AVAILABLE = TRUE
 	ORDER BY
 		C.TIME_CREATED ASC`, COMMENTS_TABLE, USERS_TABLE)
 
 	utils.Logger.Trace().Msgf("Retrieving comments with article_id: %s", articleID)
 
-	rows, _ := r.Db.Query(context.Background(), query, uid, articleID)
+	rows, _ := r.Db.Query(context.Background(), query, uid, articleID, models.DELETED_COMMENT)
 	comments, err := pgx.CollectRows(rows, pgx.RowToStructByName[models.Comment])
 	if err != nil {
 		utils.Logger.Error().Err(err).Msg("Error serializing rows to comment structs")
@@ -121,6 +122,39 @@ func (r *CommentsRepository) GetCommentsByArticleID(articleID string, uid string
 	return comments, nil
 }
 
+// Get comment by comment_id, including deleted comments. Returns pgx.ErrNoRows if not found.
+func (r *CommentsRepository) Get(commentID string) (*models.DbComment, error) {
+	query := fmt.Sprintf(`
+	SELECT COMMENT_ID, AUTHOR, ARTICLE_ID, REPLY_TO, CONTENT, TIME_CREATED, LAST_EDITED, FLAGGED, IS_AVAILABLE
+	FROM %s WHERE COMMENT_ID=$1;`, COMMENTS_TABLE)
+
+	row, _ := r.Db.Query(context.Background(), query, commentID)
+	comment, err := pgx.CollectOneRow(row, pgx.RowToAddrOfStructByName[models.DbComment])
+	if err != nil {
+		utils.Logger.Warn().Err(err).Msgf("Comment of ID %s not found", commentID)
+		return nil, err
+	}
+
+	return comment, nil
+}
+
+// Update content of an available comment. Returns pgx.ErrNoRows if not found.
+func (r *CommentsRepository) Update(commentID string, content string) error {
+	query := fmt.Sprintf(`UPDATE %s SET CONTENT=$2, LAST_EDITED=NOW() WHERE COMMENT_ID=$1 AND IS_AVAILABLE=TRUE;`, COMMENTS_TABLE)
+
+	tag, err := r.Db.Exec(context.Background(), query, commentID, content)
+	if err != nil {
+		utils.Logger.Error().Err(err).Msgf("Error updating comment with id %s", commentID)
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+
+	utils.Logger.Debug().Msgf("Comment with id %s successfully updated", commentID)
+	return nil
+}
+
 // Get comment's author
 func (r *CommentsRepository) GetAuthor(commentID string) (string, error) {
 	query := fmt.Sprintf(`SELECT AUTHOR FROM %s WHERE COMMENT_ID=$1;`, COMMENTS_TABLE)
@@ -151,7 +185,7 @@ func (r *CommentsRepository) IsAvailable(commentID string) bool {
 }
 
 // Delete comment from database
-func (r *CommentsRepository) Delete(commentID string) error {
+func (r *CommentsRepository) Delete(commentID string, deletedBy string) error {
 	ctx := context.Background()
 
 	// Begin transaction
@@ -165,11 +199,11 @@ func (r *CommentsRepository) Delete(commentID string) error {
 
 	// Remove comment from comments table and retrieve author uid
 	query := fmt.Sprintf(`
-	UPDATE %s SET IS_AVAILABLE=false WHERE comment_id=$1 AND IS_AVAILABLE=true RETURNING AUTHOR;
+	UPDATE %s SET IS_AVAILABLE=false, DELETED_BY=$2, TIME_DELETED=NOW() WHERE comment_id=$1 AND IS_AVAILABLE=true RETURNING AUTHOR;
 	`, COMMENTS_TABLE)
 
 	var author string // UID of author
-	if err = tx.QueryRow(ctx, query, commentID).Scan(&author); err != nil {
+	if err = tx.QueryRow(ctx, query, commentID, deletedBy).Scan(&author); err != nil {
 		utils.Logger.Error().Err(err).Msg("Error deleting comment from database")
 		return err
 	}
@@ -208,3 +242,43 @@ func (r *CommentsRepository) Delete(commentID string) error {
 	utils.Logger.Info().Msgf("Comment with id %s successfully deleted from database", commentID)
 	return nil
 }
+
+// Restore a deleted comment and give back the karma that Delete subtracted from the author. The article of the comment
+// must not be deleted. Returns pgx.ErrNoRows if there is no such deleted comment on an available article.
+func (r *CommentsRepository) Restore(commentID string) error {
+	ctx := context.Background()
+
+	tx, err := r.Db.Begin(ctx)
+	if err != nil {
+		utils.Logger.Error().Err(err).Msg("Error starting transaction")
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	query := fmt.Sprintf(`
+	UPDATE %s C SET IS_AVAILABLE=true, DELETED_BY=NULL, TIME_DELETED=NULL
+	FROM %s A
+	WHERE C.COMMENT_ID=$1 AND C.IS_AVAILABLE=false AND A.ARTICLE_ID=C.ARTICLE_ID AND A.IS_AVAILABLE=true
+	RETURNING C.AUTHOR;`, COMMENTS_TABLE, ARTICLES_TABLE)
+
+	var author string // UID of author
+	if err = tx.QueryRow(ctx, query, commentID).Scan(&author); err != nil {
+		utils.Logger.Warn().Err(err).Msg("Error restoring comment")
+		return err
+	}
+
+	query = fmt.Sprintf(`UPDATE %s SET KARMA = KARMA + %d WHERE UID=$1`, USERS_TABLE, models.COMMENT_ARTICLE_PTS)
+
+	if _, err = tx.Exec(ctx, query, author); err != nil {
+		utils.Logger.Error().Err(err).Msg("Error restoring user karma")
+		return err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		utils.Logger.Error().Err(err).Msg("Error committing transaction")
+		return err
+	}
+
+	utils.Logger.Info().Msgf("Comment with id %s successfully restored", commentID)
+	return nil
+}
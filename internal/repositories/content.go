@@ -1,6 +1,10 @@
 package repositories
 
-import "github.com/ntu-onemdp/onemdp-backend/internal/models"
+import (
+	"fmt"
+
+	"github.com/ntu-onemdp/onemdp-backend/internal/models"
+)
 
 type ContentRepository interface {
 	// Insert new content into the database
@@ -15,8 +19,22 @@ type ContentRepository interface {
 	Update(contentID string, content *models.Content) error
 	// Update content last updated in the database. Relevant for threads and articles
 	UpdateActivity(contentID string) error
-	// Soft delete content in the database
-	Delete(contentID string) error
+	// Soft delete content in the database, recording who deleted it
+	Delete(contentID string, deletedBy string) error
 	// Restore content in the database
 	Restore(contentID string) error
 }
+
+// Query taking up to points karma from the author ($1) of deleted content ($2) without going below 0. The amount taken is
+// recorded in karma_taken of the content, so that restoring it gives back exactly that amount.
+func takeKarmaQuery(points int, table string, idColumn string) string {
+	return fmt.Sprintf(`
+	WITH old AS (SELECT karma FROM %s WHERE uid = $1 FOR UPDATE),
+	taken AS (
+		UPDATE %s u SET karma = GREATEST(u.karma - %d, 0)
+		FROM old
+		WHERE u.uid = $1
+		RETURNING old.karma - u.karma AS amount
+	)
+	UPDATE %s SET karma_taken = COALESCE((SELECT amount FROM taken), 0) WHERE %s = $2;`, USERS_TABLE, USERS_TABLE, points, table, idColumn)
+}
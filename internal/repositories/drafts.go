@@ -0,0 +1,128 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/ntu-onemdp/onemdp-backend/internal/models"
+	"github.com/ntu-onemdp/onemdp-backend/internal/utils"
+)
+
+// Drafts table name in db
+const DRAFTS_TABLE = "drafts"
+
+type DraftsRepository struct {
+	db *pgxpool.Pool
+}
+
+var Drafts *DraftsRepository
+
+// Insert new draft into the database. Returns nil on success.
+func (r *DraftsRepository) Insert(draft *models.Draft) error {
+	query := fmt.Sprintf(`
+	INSERT INTO %s (draft_id, author, target_type, target_id, reply_to, title, content, is_anon, time_created, last_edited)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10);`, DRAFTS_TABLE)
+
+	if _, err := r.db.Exec(context.Background(), query, draft.DraftID, draft.Author, draft.TargetType, draft.TargetID, draft.ReplyTo, draft.Title, draft.Content, draft.IsAnon, draft.TimeCreated, draft.LastEdited); err != nil {
+		utils.Logger.Error().Err(err).Str("author", draft.Author).Msg("Error inserting draft into database")
+		return err
+	}
+
+	return nil
+}
+
+// Retrieve a draft of author. Returns pgx.ErrNoRows if author has no such draft.
+func (r *DraftsRepository) Get(draftID string, author string) (*models.Draft, error) {
+	query := fmt.Sprintf(`SELECT * FROM %s WHERE draft_id=$1 AND author=$2;`, DRAFTS_TABLE)
+
+	row, _ := r.db.Query(context.Background(), query, draftID, author)
+	draft, err := pgx.CollectOneRow(row, pgx.RowToAddrOfStructByName[models.Draft])
+	if err != nil {
+		utils.Logger.Warn().Err(err).Str("draft id", draftID).Msg("Draft not found")
+		return nil, err
+	}
+
+	return draft, nil
+}
+
+// Retrieve all drafts of author, most recently edited first. Drafts are filtered by target type if it is not empty.
+func (r *DraftsRepository) GetByAuthor(author string, targetType string) ([]models.Draft, error) {
+	query := fmt.Sprintf(`
+	SELECT * FROM %s
+	WHERE author=$1 AND ($2 = '' OR target_type=$2)
+	ORDER BY last_edited DESC;`, DRAFTS_TABLE)
+
+	rows, _ := r.db.Query(context.Background(), query, author, targetType)
+	drafts, err := pgx.CollectRows(rows, pgx.RowToStructByName[models.Draft])
+	if err != nil {
+		utils.Logger.Error().Err(err).Str("author", author).Msg("Error retrieving drafts")
+		return nil, err
+	}
+
+	return drafts, nil
+}
+
+// Update title, content and anonymity of a draft, unless it has been saved with a later last_edited (last writer wins).
+// Returns the updated draft, or pgx.ErrNoRows if the draft does not exist or the update is stale.
+func (r *DraftsRepository) Update(draftID string, author string, title string, content string, isAnon bool, lastEdited time.Time) (*models.Draft, error) {
+	query := fmt.Sprintf(`
+	UPDATE %s SET title=$3, content=$4, is_anon=$5, last_edited=$6
+	WHERE draft_id=$1 AND author=$2 AND last_edited <= $6
+	RETURNING *;`, DRAFTS_TABLE)
+
+	row, _ := r.db.Query(context.Background(), query, draftID, author, title, content, isAnon, lastEdited)
+	draft, err := pgx.CollectOneRow(row, pgx.RowToAddrOfStructByName[models.Draft])
+	if err != nil {
+		utils.Logger.Warn().Err(err).Str("draft id", draftID).Msg("Draft not updated")
+		return nil, err
+	}
+
+	return draft, nil
+}
+
+// Delete a draft of author. Returns pgx.ErrNoRows if author has no such draft.
+func (r *DraftsRepository) Delete(draftID string, author string) error {
+	query := fmt.Sprintf(`DELETE FROM %s WHERE draft_id=$1 AND author=$2;`, DRAFTS_TABLE)
+
+	tag, err := r.db.Exec(context.Background(), query, draftID, author)
+	if err != nil {
+		utils.Logger.Error().Err(err).Str("draft id", draftID).Msg("Error deleting draft")
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+
+	return nil
+}
+
+// Delete a draft of author and return it, so that only one of several concurrent callers gets the draft.
+// Returns pgx.ErrNoRows if author has no such draft.
+func (r *DraftsRepository) Take(draftID string, author string) (*models.Draft, error) {
+	query := fmt.Sprintf(`DELETE FROM %s WHERE draft_id=$1 AND author=$2 RETURNING *;`, DRAFTS_TABLE)
+
+	row, _ := r.db.Query(context.Background(), query, draftID, author)
+	draft, err := pgx.CollectOneRow(row, pgx.RowToAddrOfStructByName[models.Draft])
+	if err != nil {
+		utils.Logger.Warn().Err(err).Str("draft id", draftID).Msg("Draft not taken")
+		return nil, err
+	}
+
+	return draft, nil
+}
+
+// Delete all drafts last edited before cutoff. Returns number of drafts deleted.
+func (r *DraftsRepository) DeleteOlderThan(cutoff time.Time) (int64, error) {
+	query := fmt.Sprintf(`DELETE FROM %s WHERE last_edited < $1;`, DRAFTS_TABLE)
+
+	tag, err := r.db.Exec(context.Background(), query, cutoff)
+	if err != nil {
+		utils.Logger.Error().Err(err).Msg("Error deleting old drafts")
+		return 0, err
+	}
+
+	return tag.RowsAffected(), nil
+}
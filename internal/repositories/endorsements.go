@@ -0,0 +1,50 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/ntu-onemdp/onemdp-backend/internal/utils"
+)
+
+// Endorse thread as endorsedBy. Endorsing again updates the endorser and time. Returns pgx.ErrNoRows if the thread does not exist.
+func (r *ThreadsRepository) Endorse(threadID string, endorsedBy string) error {
+	return setEndorsement(r.db, THREADS_TABLE, "thread_id", threadID, &endorsedBy)
+}
+
+// Remove endorsement of thread. Returns pgx.ErrNoRows if the thread does not exist.
+func (r *ThreadsRepository) Unendorse(threadID string) error {
+	return setEndorsement(r.db, THREADS_TABLE, "thread_id", threadID, nil)
+}
+
+// Endorse post as endorsedBy. Endorsing again updates the endorser and time. Returns pgx.ErrNoRows if the post does not exist.
+func (r *PostsRepository) Endorse(postID string, endorsedBy string) error {
+	return setEndorsement(r.Db, POSTS_TABLE, "post_id", postID, &endorsedBy)
+}
+
+// Remove endorsement of post. Returns pgx.ErrNoRows if the post does not exist.
+func (r *PostsRepository) Unendorse(postID string) error {
+	return setEndorsement(r.Db, POSTS_TABLE, "post_id", postID, nil)
+}
+
+// Set or clear (if endorsedBy is nil) the endorsement of an available thread or post
+func setEndorsement(db *pgxpool.Pool, table string, idColumn string, id string, endorsedBy *string) error {
+	query := fmt.Sprintf(`
+	UPDATE %[1]s
+	SET endorsed_by = $2, time_endorsed = CASE WHEN $2::text IS NULL THEN NULL ELSE NOW() END
+	WHERE %[2]s = $1 AND is_available = true;`, table, idColumn)
+
+	result, err := db.Exec(context.Background(), query, id, endorsedBy)
+	if err != nil {
+		utils.Logger.Error().Err(err).Str(idColumn, id).Msg("Error updating endorsement")
+		return err
+	}
+	if result.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+
+	utils.Logger.Info().Str(idColumn, id).Bool("endorsed", endorsedBy != nil).Msg("Endorsement updated")
+	return nil
+}
@@ -79,6 +79,14 @@ func (r *FavoritesRepository) GetThreads(uid string) ([]models.Thread, error) {
 			ELSE U.NAME
 		END AS AUTHOR_NAME,
 		T.IS_ANON,
+		T.PINNED,
+		T.LOCKED,
+		T.ANNOUNCEMENT,
+		T.ACCEPTED_POST,
+		T.ACCEPTED_POST IS NOT NULL AS RESOLVED,
+		T.ENDORSED_BY,
+		(SELECT NAME FROM USERS WHERE UID = T.ENDORSED_BY) AS ENDORSER_NAME,
+		T.TIME_ENDORSED,
 		T.AUTHOR=$1 AS IS_AUTHOR, -- uid parameter
 		(
 			SELECT
@@ -101,7 +109,7 @@ func (r *FavoritesRepository) GetThreads(uid string) ([]models.Thread, error) {
 				WHEN F.UID = $1 THEN 1
 				ELSE 0
 			END
-		)::BOOLEAN AS IS_FAVORITED 
+		)::BOOLEAN AS IS_FAVORITED,` + threadTagsColumn + `
 	FROM 
 		THREADS T
 		INNER JOIN USERS U ON T.AUTHOR = U.UID
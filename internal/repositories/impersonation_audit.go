@@ -0,0 +1,52 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/ntu-onemdp/onemdp-backend/internal/models"
+	"github.com/ntu-onemdp/onemdp-backend/internal/utils"
+)
+
+// Impersonation audit table name in db
+const IMPERSONATION_AUDIT_TABLE = "impersonation_audit"
+
+type ImpersonationAuditRepository struct {
+	db *pgxpool.Pool
+}
+
+var ImpersonationAudit *ImpersonationAuditRepository
+
+// Insert an audit entry. Returns nil on success.
+func (r *ImpersonationAuditRepository) Insert(entry *models.ImpersonationAudit) error {
+	query := fmt.Sprintf(`
+	INSERT INTO %s (impersonator_uid, target_uid, session_id, method, path, status, ip, reason)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8);`, IMPERSONATION_AUDIT_TABLE)
+
+	if _, err := r.db.Exec(context.Background(), query, entry.ImpersonatorUid, entry.TargetUid, entry.SessionID, entry.Method, entry.Path, entry.Status, entry.Ip, entry.Reason); err != nil {
+		utils.Logger.Error().Err(err).Str("impersonator", entry.ImpersonatorUid).Str("target", entry.TargetUid).Msg("Error inserting impersonation audit entry")
+		return err
+	}
+
+	return nil
+}
+
+// Retrieve most recent audit entries, optionally filtered by impersonator and target uid.
+func (r *ImpersonationAuditRepository) GetRecent(impersonator string, target string, limit int) ([]models.ImpersonationAudit, error) {
+	query := fmt.Sprintf(`
+	SELECT * FROM %s
+	WHERE ($1 = '' OR impersonator_uid = $1) AND ($2 = '' OR target_uid = $2)
+	ORDER BY time_created DESC
+	LIMIT $3;`, IMPERSONATION_AUDIT_TABLE)
+
+	rows, _ := r.db.Query(context.Background(), query, impersonator, target, limit)
+	entries, err := pgx.CollectRows(rows, pgx.RowToStructByName[models.ImpersonationAudit])
+	if err != nil {
+		utils.Logger.Error().Err(err).Msg("Error retrieving impersonation audit entries")
+		return nil, err
+	}
+
+	return entries, nil
+}
@@ -13,4 +13,14 @@ func Init(db *pgxpool.Pool) {
 	Articles = &ArticleRepository{Db: db}
 	Comments = &CommentsRepository{Db: db}
 	Files = &FilesRepository{db: db}
+	Sessions = &SessionsRepository{db: db}
+	ApiKeys = &ApiKeysRepository{db: db}
+	AuthFailures = &AuthFailuresRepository{db: db}
+	Roles = &RolesRepository{db: db}
+	ImpersonationAudit = &ImpersonationAuditRepository{db: db}
+	Drafts = &DraftsRepository{db: db}
+	Trash = &TrashRepository{db: db}
+	Tags = &TagsRepository{db: db}
+	Polls = &PollsRepository{db: db}
+	Mentions = &MentionsRepository{db: db}
 }
@@ -0,0 +1,87 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/ntu-onemdp/onemdp-backend/internal/models"
+	"github.com/ntu-onemdp/onemdp-backend/internal/utils"
+)
+
+// Mentions table name in db
+const MENTIONS_TABLE = "mentions"
+
+type MentionsRepository struct {
+	db *pgxpool.Pool
+}
+
+var Mentions *MentionsRepository
+
+// Resolve ids taken from mention spans to the uids of active users. An id matches a user with that uid, or the only
+// active user with that name, ignoring case. Ids which do not match any user are dropped.
+func (r *MentionsRepository) Resolve(ids []string) ([]string, error) {
+	query := fmt.Sprintf(`
+	SELECT DISTINCT U.UID FROM %s U
+	WHERE U.STATUS = 'active'
+		AND (
+			U.UID = ANY($1)
+			OR (
+				LOWER(U.NAME) IN (SELECT LOWER(ID) FROM UNNEST($1::TEXT[]) AS ID)
+				AND (SELECT COUNT(*) FROM %s N WHERE N.STATUS = 'active' AND LOWER(N.NAME) = LOWER(U.NAME)) = 1
+			)
+		);`, USERS_TABLE, USERS_TABLE)
+
+	rows, _ := r.db.Query(context.Background(), query, ids)
+	uids, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		utils.Logger.Error().Err(err).Msg("Error resolving mentioned users")
+		return nil, err
+	}
+
+	return uids, nil
+}
+
+// Replace the users mentioned in content with uids. Returns the uids which were not mentioned in it before.
+func (r *MentionsRepository) Set(source models.MentionSource, uids []string) ([]string, error) {
+	if uids == nil {
+		uids = []string{}
+	}
+
+	ctx := context.Background()
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		utils.Logger.Error().Err(err).Msg("Error starting transaction")
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	query := fmt.Sprintf(`DELETE FROM %s WHERE CONTENT_ID = $1 AND UID <> ALL($2);`, MENTIONS_TABLE)
+	if _, err := tx.Exec(ctx, query, source.ContentID, uids); err != nil {
+		utils.Logger.Error().Err(err).Str("content id", source.ContentID).Msg("Error removing mentions")
+		return nil, err
+	}
+
+	query = fmt.Sprintf(`
+	INSERT INTO %s (CONTENT_ID, CONTENT_TYPE, UID, AUTHOR, TIME_CREATED)
+	SELECT $1, $2, UID, $3, NOW() FROM UNNEST($4::TEXT[]) AS UID
+	ON CONFLICT DO NOTHING
+	RETURNING UID;`, MENTIONS_TABLE)
+
+	rows, _ := tx.Query(ctx, query, source.ContentID, source.ContentType, source.Author, uids)
+	added, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		utils.Logger.Error().Err(err).Str("content id", source.ContentID).Msg("Error inserting mentions")
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		utils.Logger.Error().Err(err).Msg("Error committing transaction")
+		return nil, err
+	}
+
+	utils.Logger.Debug().Str("content id", source.ContentID).Int("mentions", len(uids)).Int("added", len(added)).Msg("Mentions updated")
+	return added, nil
+}
@@ -0,0 +1,179 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/ntu-onemdp/onemdp-backend/internal/models"
+	"github.com/ntu-onemdp/onemdp-backend/internal/utils"
+)
+
+// Polls table names in db
+const POLLS_TABLE = "polls"
+const POLL_OPTIONS_TABLE = "poll_options"
+const POLL_VOTES_TABLE = "poll_votes"
+
+type PollsRepository struct {
+	db *pgxpool.Pool
+}
+
+var Polls *PollsRepository
+
+// Insert poll together with its options
+func (r *PollsRepository) Insert(poll *models.Poll, options []models.PollOption) error {
+	ctx := context.Background()
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		utils.Logger.Error().Err(err).Msg("Error starting transaction")
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	query := fmt.Sprintf(`
+	INSERT INTO %s (poll_id, thread_id, question, multiple_choice, is_anon, closes_at, created_by, time_created)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8);`, POLLS_TABLE)
+	if _, err := tx.Exec(ctx, query, poll.PollID, poll.ThreadID, poll.Question, poll.MultipleChoice, poll.IsAnon, poll.ClosesAt, poll.CreatedBy, poll.TimeCreated); err != nil {
+		utils.Logger.Error().Err(err).Str("thread id", poll.ThreadID).Msg("Error inserting poll")
+		return err
+	}
+
+	query = fmt.Sprintf(`INSERT INTO %s (option_id, poll_id, position, text) VALUES ($1, $2, $3, $4);`, POLL_OPTIONS_TABLE)
+	for _, option := range options {
+		if _, err := tx.Exec(ctx, query, option.OptionID, option.PollID, option.Position, option.Text); err != nil {
+			utils.Logger.Error().Err(err).Str("poll id", poll.PollID).Msg("Error inserting poll option")
+			return err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		utils.Logger.Error().Err(err).Msg("Error committing transaction")
+		return err
+	}
+
+	utils.Logger.Info().Str("poll id", poll.PollID).Str("thread id", poll.ThreadID).Msg("Poll created")
+	return nil
+}
+
+// Retrieve poll of thread. Returns pgx.ErrNoRows if the thread has no poll.
+func (r *PollsRepository) GetByThread(threadID string) (*models.Poll, error) {
+	query := fmt.Sprintf(`
+	SELECT poll_id, thread_id, question, multiple_choice, is_anon, closes_at, created_by, time_created
+	FROM %s WHERE thread_id = $1;`, POLLS_TABLE)
+
+	rows, _ := r.db.Query(context.Background(), query, threadID)
+	poll, err := pgx.CollectOneRow(rows, pgx.RowToAddrOfStructByName[models.Poll])
+	if err != nil {
+		if !errors.Is(err, pgx.ErrNoRows) {
+			utils.Logger.Error().Err(err).Str("thread id", threadID).Msg("Error retrieving poll")
+		}
+		return nil, err
+	}
+
+	return poll, nil
+}
+
+// Retrieve options of poll in order, with the number of votes for each
+func (r *PollsRepository) GetOptions(pollID string) ([]models.PollOptionResult, error) {
+	query := fmt.Sprintf(`
+	SELECT O.option_id, O.poll_id, O.position, O.text, COUNT(V.uid) AS num_votes
+	FROM %s O LEFT JOIN %s V ON V.option_id = O.option_id
+	WHERE O.poll_id = $1
+	GROUP BY O.option_id
+	ORDER BY O.position;`, POLL_OPTIONS_TABLE, POLL_VOTES_TABLE)
+
+	rows, _ := r.db.Query(context.Background(), query, pollID)
+	options, err := pgx.CollectRows(rows, pgx.RowToStructByName[models.PollOptionResult])
+	if err != nil {
+		utils.Logger.Error().Err(err).Str("poll id", pollID).Msg("Error retrieving poll options")
+		return nil, err
+	}
+
+	return options, nil
+}
+
+// Retrieve everyone who voted in poll, with the option they voted for
+func (r *PollsRepository) GetVoters(pollID string) ([]models.PollVoter, error) {
+	query := fmt.Sprintf(`
+	SELECT V.option_id, V.uid, U.name
+	FROM %s V INNER JOIN %s U ON U.uid = V.uid
+	WHERE V.poll_id = $1
+	ORDER BY V.time_voted;`, POLL_VOTES_TABLE, USERS_TABLE)
+
+	rows, _ := r.db.Query(context.Background(), query, pollID)
+	voters, err := pgx.CollectRows(rows, pgx.RowToStructByName[models.PollVoter])
+	if err != nil {
+		utils.Logger.Error().Err(err).Str("poll id", pollID).Msg("Error retrieving poll voters")
+		return nil, err
+	}
+
+	return voters, nil
+}
+
+// Retrieve number of users who voted in poll
+func (r *PollsRepository) CountVoters(pollID string) (int, error) {
+	query := fmt.Sprintf(`SELECT COUNT(DISTINCT uid) FROM %s WHERE poll_id = $1;`, POLL_VOTES_TABLE)
+
+	var count int
+	if err := r.db.QueryRow(context.Background(), query, pollID).Scan(&count); err != nil {
+		utils.Logger.Error().Err(err).Str("poll id", pollID).Msg("Error counting poll voters")
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// Retrieve options of poll voted for by user
+func (r *PollsRepository) GetVotes(pollID string, uid string) ([]string, error) {
+	query := fmt.Sprintf(`SELECT option_id FROM %s WHERE poll_id = $1 AND uid = $2;`, POLL_VOTES_TABLE)
+
+	rows, _ := r.db.Query(context.Background(), query, pollID, uid)
+	votes, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		utils.Logger.Error().Err(err).Str("poll id", pollID).Msg("Error retrieving votes of user")
+		return nil, err
+	}
+
+	return votes, nil
+}
+
+// Replace the votes of user in poll. Options must belong to the poll.
+func (r *PollsRepository) Vote(pollID string, uid string, optionIDs []string) error {
+	ctx := context.Background()
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		utils.Logger.Error().Err(err).Msg("Error starting transaction")
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	query := fmt.Sprintf(`DELETE FROM %s WHERE poll_id = $1 AND uid = $2;`, POLL_VOTES_TABLE)
+	if _, err := tx.Exec(ctx, query, pollID, uid); err != nil {
+		utils.Logger.Error().Err(err).Str("poll id", pollID).Msg("Error removing previous votes")
+		return err
+	}
+
+	query = fmt.Sprintf(`
+	INSERT INTO %s (poll_id, option_id, uid)
+	SELECT poll_id, option_id, $2 FROM %s WHERE poll_id = $1 AND option_id = ANY($3);`, POLL_VOTES_TABLE, POLL_OPTIONS_TABLE)
+	result, err := tx.Exec(ctx, query, pollID, uid, optionIDs)
+	if err != nil {
+		utils.Logger.Error().Err(err).Str("poll id", pollID).Msg("Error inserting votes")
+		return err
+	}
+	if result.RowsAffected() != int64(len(optionIDs)) {
+		return errors.New("option does not belong to this poll")
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		utils.Logger.Error().Err(err).Msg("Error committing transaction")
+		return err
+	}
+
+	utils.Logger.Info().Str("poll id", pollID).Str("uid", uid).Strs("options", optionIDs).Msg("Votes recorded")
+	return nil
+}
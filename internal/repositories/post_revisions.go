@@ -0,0 +1,41 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/ntu-onemdp/onemdp-backend/internal/models"
+	"github.com/ntu-onemdp/onemdp-backend/internal/utils"
+)
+
+// Post revisions table name in db. Revisions are saved by PostsRepository.Update.
+const POST_REVISIONS_TABLE = "post_revisions"
+
+// Retrieve all previous versions of a post, newest first.
+func (r *PostsRepository) GetRevisions(postID string) ([]models.PostRevision, error) {
+	query := fmt.Sprintf(`SELECT * FROM %s WHERE post_id = $1 ORDER BY revision DESC;`, POST_REVISIONS_TABLE)
+
+	rows, _ := r.Db.Query(context.Background(), query, postID)
+	revisions, err := pgx.CollectRows(rows, pgx.RowToStructByName[models.PostRevision])
+	if err != nil {
+		utils.Logger.Error().Err(err).Str("post id", postID).Msg("Error retrieving post revisions")
+		return nil, err
+	}
+
+	return revisions, nil
+}
+
+// Retrieve one previous version of a post. Returns pgx.ErrNoRows if it does not exist.
+func (r *PostsRepository) GetRevision(postID string, revision int) (*models.PostRevision, error) {
+	query := fmt.Sprintf(`SELECT * FROM %s WHERE post_id = $1 AND revision = $2;`, POST_REVISIONS_TABLE)
+
+	row, _ := r.Db.Query(context.Background(), query, postID, revision)
+	rev, err := pgx.CollectOneRow(row, pgx.RowToAddrOfStructByName[models.PostRevision])
+	if err != nil {
+		utils.Logger.Warn().Err(err).Str("post id", postID).Int("revision", revision).Msg("Post revision not found")
+		return nil, err
+	}
+
+	return rev, nil
+}
@@ -70,7 +70,9 @@ func (r *PostsRepository) Create(post *models.DbPost) error {
 
 // Get post by post_id. Returns post object if found, nil otherwise.
 func (r *PostsRepository) Get(postID string) (*models.DbPost, error) {
-	query := fmt.Sprintf(`SELECT * FROM %s WHERE post_id = $1 AND is_available = true;`, POSTS_TABLE)
+	query := fmt.Sprintf(`
+	SELECT post_id, author, thread_id, reply_to, title, content, time_created, last_edited, flagged, is_available, is_header, is_anon, num_edits
+	FROM %s WHERE post_id = $1 AND is_available = true;`, POSTS_TABLE)
 
 	utils.Logger.Trace().Msg(fmt.Sprintf("Getting post with id: %s", postID))
 
@@ -110,6 +112,10 @@ func (r *PostsRepository) GetPostsByThreadId(threadID string, uid string) ([]mod
 				ELSE U.NAME
 			END AS AUTHOR_NAME,
 			P.IS_ANON,
+			P.NUM_EDITS,
+			P.ENDORSED_BY,
+			(SELECT NAME FROM USERS WHERE UID = P.ENDORSED_BY) AS ENDORSER_NAME,
+			P.TIME_ENDORSED,
 			P.AUTHOR=$1 AS IS_AUTHOR, 	-- UID parameter
 			COALESCE(l.like_count, 0) AS num_likes,
 			COALESCE(ul.user_liked, false) AS is_liked
@@ -207,27 +213,63 @@ func (r *PostsRepository) IsAvailable(postID string) bool {
 	return isAvailable
 }
 
-// Edit content of post
+// Edit content of post. The version being replaced is saved as a new revision with the uid of the editor, and the edit
+// count of the post is incremented. No revision is saved if neither the title nor the content has changed.
 // TODO: update last edited for parent thread
-func (r *PostsRepository) Update(postID string, updated_post models.DbPost) error {
-	query := fmt.Sprintf(`
-	UPDATE %s SET title = $1, content = $2, reply_to = $3, last_edited = NOW() WHERE post_id = $4 AND is_available = true;`, POSTS_TABLE)
+func (r *PostsRepository) Update(postID string, updated_post models.DbPost, editor string) error {
+	ctx := context.Background()
+
+	tx, err := r.Db.Begin(ctx)
+	if err != nil {
+		utils.Logger.Error().Err(err).Msg("Error starting transaction")
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	// Lock the post so that concurrent edits get consecutive revision numbers
+	query := fmt.Sprintf(`SELECT title, content FROM %s WHERE post_id = $1 AND is_available = true FOR UPDATE;`, POSTS_TABLE)
+
+	var oldTitle, oldContent string
+	if err := tx.QueryRow(ctx, query, postID).Scan(&oldTitle, &oldContent); err != nil {
+		utils.Logger.Error().Err(err).Msg(fmt.Sprintf("Post with id %v not found", postID))
+		return err
+	}
+
+	numEdits := "num_edits"
+	if oldTitle != updated_post.Title || oldContent != updated_post.PostContent {
+		query = fmt.Sprintf(`
+		INSERT INTO %s (post_id, revision, title, content, replaced_by)
+		SELECT $1, COALESCE(MAX(revision), 0) + 1, $2, $3, $4 FROM %s WHERE post_id = $1;`, POST_REVISIONS_TABLE, POST_REVISIONS_TABLE)
+
+		if _, err := tx.Exec(ctx, query, postID, oldTitle, oldContent, editor); err != nil {
+			utils.Logger.Error().Err(err).Msg("Error saving post revision")
+			return err
+		}
+		numEdits = "num_edits + 1"
+	}
+
+	query = fmt.Sprintf(`
+	UPDATE %s SET title = $1, content = $2, last_edited = NOW(), num_edits = %s WHERE post_id = $3;`, POSTS_TABLE, numEdits)
 
 	utils.Logger.Trace().Msg(fmt.Sprintf("Updating content of post with id: %v", postID))
 
-	_, err := r.Db.Exec(context.Background(), query, updated_post.Title, updated_post.PostContent, updated_post.ReplyTo, postID)
-	if err != nil {
+	if _, err := tx.Exec(ctx, query, updated_post.Title, updated_post.PostContent, postID); err != nil {
 		utils.Logger.Error().Err(err).Msg("")
 		return err
 	}
 
-	utils.Logger.Debug().Msg(fmt.Sprintf("Content of post with id %v successfully updated", postID))
+	if err := tx.Commit(ctx); err != nil {
+		utils.Logger.Error().Err(err).Msg("Error committing transaction")
+		return err
+	}
+
+	utils.Logger.Debug().Msg(fmt.Sprintf("Content of post with id %v successfully updated by %v", postID, editor))
 	return nil
 }
 
 // Delete one post from database matching post_id. Returns nil if successful.
 // Soft delete is performed.
-func (r *PostsRepository) Delete(postID string) error {
+func (r *PostsRepository) Delete(postID string, deletedBy string) error {
 	ctx := context.Background()
 
 	// Begin transaction
@@ -241,10 +283,10 @@ func (r *PostsRepository) Delete(postID string) error {
 
 	// Remove post from posts table
 	query := fmt.Sprintf(`
-		UPDATE %s SET is_available = false WHERE post_id = $1 AND is_available = true RETURNING author;`, POSTS_TABLE)
+		UPDATE %s SET is_available = false, deleted_by = $2, time_deleted = NOW() WHERE post_id = $1 AND is_available = true RETURNING author, thread_id;`, POSTS_TABLE)
 
-	var author string
-	if err = tx.QueryRow(ctx, query, postID).Scan(&author); err != nil {
+	var author, threadID string
+	if err = tx.QueryRow(ctx, query, postID, deletedBy).Scan(&author, &threadID); err != nil {
 		utils.Logger.Error().Err(err).Msg("Error deleting post from database")
 		return err
 	}
@@ -264,10 +306,14 @@ func (r *PostsRepository) Delete(postID string) error {
 	}
 	utils.Logger.Trace().Msg(fmt.Sprintf("Post with id %s deleted from likes table", postID))
 
+	// Thread is no longer resolved if post was its accepted answer
+	if err = revokeAcceptedAnswer(ctx, tx, threadID, &postID); err != nil {
+		return err
+	}
+
 	// Update user karma
-	query = fmt.Sprintf(`
-		UPDATE %s SET karma = GREATEST(karma - %d, 0) WHERE uid = $1;`, USERS_TABLE, models.CREATE_POST_PTS)
-	if _, err = tx.Exec(ctx, query, author); err != nil {
+	query = takeKarmaQuery(models.CREATE_POST_PTS, POSTS_TABLE, "post_id")
+	if _, err = tx.Exec(ctx, query, author, postID); err != nil {
 		utils.Logger.Error().Err(err).Msg("Error updating user karma")
 		return err
 	}
@@ -282,7 +328,9 @@ func (r *PostsRepository) Delete(postID string) error {
 	return nil
 }
 
-// Restore deleted post by post_id. Returns nil if successful.
+// Restore deleted post by post_id and give back the karma that Delete took. The thread of the post must not be deleted.
+// Header posts are only restored together with their thread by ThreadsRepository.Restore.
+// Returns nil if successful, or pgx.ErrNoRows if there is no such deleted reply in an available thread.
 func (r *PostsRepository) Restore(postID string) error {
 	ctx := context.Background()
 
@@ -297,10 +345,14 @@ func (r *PostsRepository) Restore(postID string) error {
 
 	// Restore post from posts table
 	query := fmt.Sprintf(`
-		UPDATE %s SET is_available = true WHERE post_id = $1 AND is_available = false RETURNING author;`, POSTS_TABLE)
+		UPDATE %s P SET is_available = true, deleted_by = NULL, time_deleted = NULL
+		FROM %s T
+		WHERE P.post_id = $1 AND P.is_available = false AND NOT P.is_header AND T.thread_id = P.thread_id AND T.is_available = true
+		RETURNING P.author, P.karma_taken;`, POSTS_TABLE, THREADS_TABLE)
 
 	var author string
-	if err := tx.QueryRow(ctx, query, postID).Scan(&author); err != nil {
+	var karmaTaken int
+	if err := tx.QueryRow(ctx, query, postID).Scan(&author, &karmaTaken); err != nil {
 		utils.Logger.Error().Err(err).Msg("Error restoring post from database")
 		return err
 	}
@@ -312,9 +364,9 @@ func (r *PostsRepository) Restore(postID string) error {
 
 	// Restore user's karma
 	query = fmt.Sprintf(`
-		UPDATE %s SET karma = karma + %d WHERE uid = $1;`, USERS_TABLE, models.CREATE_POST_PTS)
+		UPDATE %s SET karma = karma + $2 WHERE uid = $1;`, USERS_TABLE)
 
-	if _, err = tx.Exec(ctx, query, author); err != nil {
+	if _, err = tx.Exec(ctx, query, author, karmaTaken); err != nil {
 		utils.Logger.Error().Err(err).Msg("Error restoring user karma")
 		return err
 	}
@@ -0,0 +1,99 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/ntu-onemdp/onemdp-backend/internal/models"
+	"github.com/ntu-onemdp/onemdp-backend/internal/utils"
+)
+
+// Roles table names in db
+const ROLES_TABLE = "roles"
+const ROLE_PERMISSIONS_TABLE = "role_permissions"
+
+type RolesRepository struct {
+	db *pgxpool.Pool
+}
+
+var Roles *RolesRepository
+
+// Retrieve all roles together with their permissions
+func (r *RolesRepository) GetAll() ([]models.Role, error) {
+	query := fmt.Sprintf(`
+	SELECT r.role, r.description, r.is_system, r.time_created,
+		COALESCE(ARRAY_AGG(p.permission ORDER BY p.permission) FILTER (WHERE p.permission IS NOT NULL), '{}') AS permissions
+	FROM %s r LEFT JOIN %s p ON r.role = p.role
+	GROUP BY r.role
+	ORDER BY r.time_created, r.role;`, ROLES_TABLE, ROLE_PERMISSIONS_TABLE)
+
+	rows, _ := r.db.Query(context.Background(), query)
+	roles, err := pgx.CollectRows(rows, pgx.RowToStructByName[models.Role])
+	if err != nil {
+		utils.Logger.Error().Err(err).Msg("Error retrieving roles")
+		return nil, err
+	}
+
+	return roles, nil
+}
+
+// Create role, or update the description and permissions of an existing role. Permissions are replaced entirely.
+func (r *RolesRepository) Upsert(role *models.Role) error {
+	ctx := context.Background()
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		utils.Logger.Error().Err(err).Msg("Error starting transaction")
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	query := fmt.Sprintf(`
+	INSERT INTO %s (role, description) VALUES ($1, $2)
+	ON CONFLICT (role) DO UPDATE SET description = EXCLUDED.description;`, ROLES_TABLE)
+	if _, err := tx.Exec(ctx, query, role.Role, role.Description); err != nil {
+		utils.Logger.Error().Err(err).Str("role", role.Role).Msg("Error saving role")
+		return err
+	}
+
+	query = fmt.Sprintf(`DELETE FROM %s WHERE role = $1;`, ROLE_PERMISSIONS_TABLE)
+	if _, err := tx.Exec(ctx, query, role.Role); err != nil {
+		utils.Logger.Error().Err(err).Str("role", role.Role).Msg("Error clearing role permissions")
+		return err
+	}
+
+	query = fmt.Sprintf(`INSERT INTO %s (role, permission) SELECT $1, UNNEST($2::text[]);`, ROLE_PERMISSIONS_TABLE)
+	if _, err := tx.Exec(ctx, query, role.Role, role.Permissions); err != nil {
+		utils.Logger.Error().Err(err).Str("role", role.Role).Msg("Error saving role permissions")
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		utils.Logger.Error().Err(err).Msg("Error committing transaction")
+		return err
+	}
+
+	utils.Logger.Info().Str("role", role.Role).Interface("permissions", role.Permissions).Msgf("Role %s saved", role.Role)
+	return nil
+}
+
+// Delete a role that is not built in. Fails with a foreign key violation if the role is still in use.
+// Returns pgx.ErrNoRows if the role does not exist or is built in.
+func (r *RolesRepository) Delete(role string) error {
+	query := fmt.Sprintf(`DELETE FROM %s WHERE role = $1 AND NOT is_system;`, ROLES_TABLE)
+
+	res, err := r.db.Exec(context.Background(), query, role)
+	if err != nil {
+		utils.Logger.Error().Err(err).Str("role", role).Msg("Error deleting role")
+		return err
+	}
+
+	if res.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+
+	utils.Logger.Info().Str("role", role).Msgf("Role %s deleted", role)
+	return nil
+}
@@ -0,0 +1,128 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/ntu-onemdp/onemdp-backend/internal/models"
+	"github.com/ntu-onemdp/onemdp-backend/internal/utils"
+)
+
+// Sessions table name in db
+const SESSIONS_TABLE = "sessions"
+
+type SessionsRepository struct {
+	db *pgxpool.Pool
+}
+
+var Sessions *SessionsRepository
+
+// Insert new session into the database. Returns nil on success.
+func (r *SessionsRepository) Insert(session *models.Session) error {
+	query := fmt.Sprintf(`
+	INSERT INTO %s (session_id, uid, token_hash, user_agent, time_created, last_used, expires_at)
+	VALUES ($1, $2, $3, $4, $5, $6, $7);`, SESSIONS_TABLE)
+
+	if _, err := r.db.Exec(context.Background(), query, session.SessionID, session.Uid, session.TokenHash, session.UserAgent, session.TimeCreated, session.LastUsed, session.ExpiresAt); err != nil {
+		utils.Logger.Error().Err(err).Str("uid", session.Uid).Msg("Error inserting session into database")
+		return err
+	}
+
+	utils.Logger.Debug().Str("uid", session.Uid).Str("session id", session.SessionID).Msg("Session inserted into database")
+	return nil
+}
+
+// Retrieve an active (not revoked and not expired) session by the hash of its refresh token.
+// Returns pgx.ErrNoRows if no active session matches.
+func (r *SessionsRepository) GetActiveByTokenHash(tokenHash string) (*models.Session, error) {
+	query := fmt.Sprintf(`SELECT * FROM %s WHERE token_hash=$1 AND revoked_at IS NULL AND expires_at > NOW();`, SESSIONS_TABLE)
+
+	row, _ := r.db.Query(context.Background(), query, tokenHash)
+	session, err := pgx.CollectOneRow(row, pgx.RowToAddrOfStructByName[models.Session])
+	if err != nil {
+		utils.Logger.Warn().Err(err).Msg("No active session found for refresh token")
+		return nil, err
+	}
+
+	return session, nil
+}
+
+// Retrieve all sessions of a user, most recently used first.
+func (r *SessionsRepository) GetByUid(uid string) ([]models.Session, error) {
+	query := fmt.Sprintf(`SELECT * FROM %s WHERE uid=$1 ORDER BY last_used DESC;`, SESSIONS_TABLE)
+
+	rows, _ := r.db.Query(context.Background(), query, uid)
+	sessions, err := pgx.CollectRows(rows, pgx.RowToStructByName[models.Session])
+	if err != nil {
+		utils.Logger.Error().Err(err).Str("uid", uid).Msg("Error retrieving sessions")
+		return nil, err
+	}
+
+	return sessions, nil
+}
+
+// Replace the refresh token of an active session and extend its expiry.
+// The old refresh token stops working immediately. Returns pgx.ErrNoRows if the session is no longer active.
+func (r *SessionsRepository) Rotate(sessionID string, oldHash string, newHash string, expiresAt time.Time) error {
+	query := fmt.Sprintf(`
+	UPDATE %s
+	SET token_hash=$1, expires_at=$2, last_used=NOW()
+	WHERE session_id=$3 AND token_hash=$4 AND revoked_at IS NULL AND expires_at > NOW();`, SESSIONS_TABLE)
+
+	res, err := r.db.Exec(context.Background(), query, newHash, expiresAt, sessionID, oldHash)
+	if err != nil {
+		utils.Logger.Error().Err(err).Str("session id", sessionID).Msg("Error rotating refresh token")
+		return err
+	}
+
+	// Refresh token was used concurrently or the session was revoked in between
+	if res.RowsAffected() == 0 {
+		utils.Logger.Warn().Str("session id", sessionID).Msg("Session no longer active, refresh token not rotated")
+		return pgx.ErrNoRows
+	}
+
+	return nil
+}
+
+// Returns true if session exists, has not been revoked and has not expired.
+func (r *SessionsRepository) IsActive(sessionID string) bool {
+	query := fmt.Sprintf(`SELECT EXISTS(SELECT 1 FROM %s WHERE session_id=$1 AND revoked_at IS NULL AND expires_at > NOW());`, SESSIONS_TABLE)
+
+	var active bool
+	if err := r.db.QueryRow(context.Background(), query, sessionID).Scan(&active); err != nil {
+		utils.Logger.Error().Err(err).Str("session id", sessionID).Msg("Error checking if session is active")
+		return false
+	}
+
+	return active
+}
+
+// Revoke a single session. Returns nil if the session has been revoked or was already revoked.
+func (r *SessionsRepository) Revoke(sessionID string) error {
+	query := fmt.Sprintf(`UPDATE %s SET revoked_at=NOW() WHERE session_id=$1 AND revoked_at IS NULL;`, SESSIONS_TABLE)
+
+	if _, err := r.db.Exec(context.Background(), query, sessionID); err != nil {
+		utils.Logger.Error().Err(err).Str("session id", sessionID).Msg("Error revoking session")
+		return err
+	}
+
+	utils.Logger.Info().Str("session id", sessionID).Msg("Session revoked")
+	return nil
+}
+
+// Revoke all active sessions of a user. Returns the number of sessions revoked.
+func (r *SessionsRepository) RevokeAllByUid(uid string) (int64, error) {
+	query := fmt.Sprintf(`UPDATE %s SET revoked_at=NOW() WHERE uid=$1 AND revoked_at IS NULL;`, SESSIONS_TABLE)
+
+	res, err := r.db.Exec(context.Background(), query, uid)
+	if err != nil {
+		utils.Logger.Error().Err(err).Str("uid", uid).Msg("Error revoking sessions")
+		return 0, err
+	}
+
+	utils.Logger.Info().Str("uid", uid).Int64("num revoked", res.RowsAffected()).Msgf("All sessions revoked for %s", uid)
+	return res.RowsAffected(), nil
+}
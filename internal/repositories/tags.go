@@ -0,0 +1,170 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/ntu-onemdp/onemdp-backend/internal/models"
+	"github.com/ntu-onemdp/onemdp-backend/internal/utils"
+)
+
+// Tags table names in db
+const TAGS_TABLE = "tags"
+const THREAD_TAGS_TABLE = "thread_tags"
+
+// Tags of thread T as a json array, for selecting into models.Thread
+var threadTagsColumn = fmt.Sprintf(`
+		COALESCE((
+			SELECT json_agg(json_build_object(
+				'tag_id', G.TAG_ID, 'name', G.NAME, 'colour', G.COLOUR, 'description', G.DESCRIPTION, 'time_created', G.TIME_CREATED
+			) ORDER BY G.NAME)
+			FROM %s TT INNER JOIN %s G ON TT.TAG_ID = G.TAG_ID
+			WHERE TT.THREAD_ID = T.THREAD_ID
+		), '[]') AS TAGS`, THREAD_TAGS_TABLE, TAGS_TABLE)
+
+type TagsRepository struct {
+	db *pgxpool.Pool
+}
+
+var Tags *TagsRepository
+
+// Retrieve all tags with the number of available threads labelled with each
+func (r *TagsRepository) GetAll() ([]models.TagCount, error) {
+	query := fmt.Sprintf(`
+	SELECT G.tag_id, G.name, G.colour, G.description, G.time_created, COUNT(T.thread_id) AS num_threads
+	FROM %s G
+	LEFT JOIN %s TT ON TT.tag_id = G.tag_id
+	LEFT JOIN %s T ON T.thread_id = TT.thread_id AND T.is_available = true
+	GROUP BY G.tag_id
+	ORDER BY G.name;`, TAGS_TABLE, THREAD_TAGS_TABLE, THREADS_TABLE)
+
+	rows, _ := r.db.Query(context.Background(), query)
+	tags, err := pgx.CollectRows(rows, pgx.RowToStructByName[models.TagCount])
+	if err != nil {
+		utils.Logger.Error().Err(err).Msg("Error retrieving tags")
+		return nil, err
+	}
+
+	return tags, nil
+}
+
+// Retrieve tag. Returns pgx.ErrNoRows if it does not exist.
+func (r *TagsRepository) Get(tagID string) (*models.Tag, error) {
+	query := fmt.Sprintf(`SELECT tag_id, name, colour, description, time_created FROM %s WHERE tag_id = $1;`, TAGS_TABLE)
+
+	rows, _ := r.db.Query(context.Background(), query, tagID)
+	tag, err := pgx.CollectOneRow(rows, pgx.RowToAddrOfStructByName[models.Tag])
+	if err != nil {
+		utils.Logger.Warn().Err(err).Str("tag id", tagID).Msg("Error retrieving tag")
+		return nil, err
+	}
+
+	return tag, nil
+}
+
+// Count how many of the given tags exist
+func (r *TagsRepository) CountExisting(tagIDs []string) (int, error) {
+	query := fmt.Sprintf(`SELECT COUNT(1) FROM %s WHERE tag_id = ANY($1);`, TAGS_TABLE)
+
+	var count int
+	if err := r.db.QueryRow(context.Background(), query, tagIDs).Scan(&count); err != nil {
+		utils.Logger.Error().Err(err).Msg("Error counting tags")
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// Insert new tag. Names are unique regardless of case.
+func (r *TagsRepository) Insert(tag *models.Tag) error {
+	query := fmt.Sprintf(`
+	INSERT INTO %s (tag_id, name, colour, description, time_created)
+	VALUES ($1, $2, $3, $4, $5);`, TAGS_TABLE)
+
+	if _, err := r.db.Exec(context.Background(), query, tag.TagID, tag.Name, tag.Colour, tag.Description, tag.TimeCreated); err != nil {
+		utils.Logger.Error().Err(err).Str("name", tag.Name).Msg("Error inserting tag")
+		return err
+	}
+
+	utils.Logger.Info().Str("tag id", tag.TagID).Str("name", tag.Name).Msg("Tag created")
+	return nil
+}
+
+// Update name, colour and description of tag. Returns pgx.ErrNoRows if it does not exist.
+func (r *TagsRepository) Update(tag *models.Tag) error {
+	query := fmt.Sprintf(`UPDATE %s SET name = $2, colour = $3, description = $4 WHERE tag_id = $1;`, TAGS_TABLE)
+
+	result, err := r.db.Exec(context.Background(), query, tag.TagID, tag.Name, tag.Colour, tag.Description)
+	if err != nil {
+		utils.Logger.Error().Err(err).Str("tag id", tag.TagID).Msg("Error updating tag")
+		return err
+	}
+	if result.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+
+	return nil
+}
+
+// Delete tag. Threads labelled with it lose the tag. Returns pgx.ErrNoRows if it does not exist.
+func (r *TagsRepository) Delete(tagID string) error {
+	query := fmt.Sprintf(`DELETE FROM %s WHERE tag_id = $1;`, TAGS_TABLE)
+
+	result, err := r.db.Exec(context.Background(), query, tagID)
+	if err != nil {
+		utils.Logger.Error().Err(err).Str("tag id", tagID).Msg("Error deleting tag")
+		return err
+	}
+	if result.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+
+	utils.Logger.Info().Str("tag id", tagID).Msg("Tag deleted")
+	return nil
+}
+
+// Replace the tags of a thread
+func (r *TagsRepository) SetThreadTags(threadID string, tagIDs []string) error {
+	ctx := context.Background()
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		utils.Logger.Error().Err(err).Msg("Error starting transaction")
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if err := setThreadTags(ctx, tx, threadID, tagIDs); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		utils.Logger.Error().Err(err).Msg("Error committing transaction")
+		return err
+	}
+
+	return nil
+}
+
+// Replace the tags of a thread within a transaction
+func setThreadTags(ctx context.Context, tx pgx.Tx, threadID string, tagIDs []string) error {
+	query := fmt.Sprintf(`DELETE FROM %s WHERE thread_id = $1;`, THREAD_TAGS_TABLE)
+	if _, err := tx.Exec(ctx, query, threadID); err != nil {
+		utils.Logger.Error().Err(err).Str("thread id", threadID).Msg("Error removing tags of thread")
+		return err
+	}
+
+	if len(tagIDs) == 0 {
+		return nil
+	}
+
+	query = fmt.Sprintf(`INSERT INTO %s (thread_id, tag_id) SELECT $1, unnest($2::text[]);`, THREAD_TAGS_TABLE)
+	if _, err := tx.Exec(ctx, query, threadID, tagIDs); err != nil {
+		utils.Logger.Error().Err(err).Str("thread id", threadID).Msg("Error adding tags to thread")
+		return err
+	}
+
+	return nil
+}
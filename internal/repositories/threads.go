@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
@@ -21,9 +22,25 @@ type ThreadsRepository struct {
 
 var Threads *ThreadsRepository
 
-// Insert new thread into the database. Returns thread ID and UUID of header post on successful insert
+// Condition on thread T having the tags in $n (text[], no filter if empty or null) and $n+1 (true if all tags must match,
+// otherwise any). Tags in $n must not repeat.
+func threadTagsFilter(n int) string {
+	return fmt.Sprintf(`(
+			COALESCE(cardinality($%[1]d::text[]), 0) = 0
+			OR (
+				SELECT COUNT(1) FROM %[3]s TT WHERE TT.THREAD_ID = T.THREAD_ID AND TT.TAG_ID = ANY($%[1]d::text[])
+			) >= CASE WHEN $%[2]d::boolean THEN cardinality($%[1]d::text[]) ELSE 1 END
+		)`, n, n+1, THREAD_TAGS_TABLE)
+}
+
+// Condition on thread T having the resolved status in $n (models.ThreadResolved, models.ThreadUnresolved or empty for all)
+func threadStatusFilter(n int) string {
+	return fmt.Sprintf(`($%[1]d::text = '' OR (T.ACCEPTED_POST IS NOT NULL) = ($%[1]d::text = '%[2]s'))`, n, models.ThreadResolved)
+}
+
+// Insert new thread into the database, labelled with the given tags. Returns thread ID and UUID of header post on successful insert
 // Although function takes in a thread object, only author, title and preview are used.
-func (r *ThreadsRepository) Insert(thread *models.DbThread) error {
+func (r *ThreadsRepository) Insert(thread *models.DbThread, tagIDs []string) error {
 	ctx := context.Background()
 
 	// Begin transaction
@@ -45,6 +62,10 @@ func (r *ThreadsRepository) Insert(thread *models.DbThread) error {
 	}
 	utils.Logger.Debug().Str("thread id", thread.ThreadID).Msg("")
 
+	if err = setThreadTags(ctx, tx, thread.ThreadID, tagIDs); err != nil {
+		return err
+	}
+
 	// Update author's karma
 	query = fmt.Sprintf(`
 	UPDATE %s
@@ -73,7 +94,7 @@ func (r *ThreadsRepository) Insert(thread *models.DbThread) error {
 // page: page number - offset is automatically calculated in this function.
 // size: page size; number of items to return
 // descending: true if sorting is descending, false if ascending
-func (r *ThreadsRepository) GetAll(column models.SortColumn, uid string, page int, size int, descending bool, searchKeyword string) ([]models.Thread, error) {
+func (r *ThreadsRepository) GetAll(column models.SortColumn, uid string, page int, size int, descending bool, filter models.ThreadFilter) ([]models.Thread, error) {
 	desc := "DESC"
 	if !descending {
 		desc = "ASC"
@@ -87,6 +108,10 @@ func (r *ThreadsRepository) GetAll(column models.SortColumn, uid string, page in
 	// $2: limit
 	// $3: offset
 	// $4: search keyword
+	// $5: tag ids
+	// $6: whether threads must have all tags
+	// $7: resolved status
+	// $8: only endorsed threads
 	query := fmt.Sprintf(`
 	SELECT
 		T.THREAD_ID,
@@ -115,6 +140,14 @@ func (r *ThreadsRepository) GetAll(column models.SortColumn, uid string, page in
 			ELSE U.NAME
 		END AS AUTHOR_NAME,
 		T.IS_ANON,
+		T.PINNED,
+		T.LOCKED,
+		T.ANNOUNCEMENT,
+		T.ACCEPTED_POST,
+		T.ACCEPTED_POST IS NOT NULL AS RESOLVED,
+		T.ENDORSED_BY,
+		(SELECT NAME FROM USERS WHERE UID = T.ENDORSED_BY) AS ENDORSER_NAME,
+		T.TIME_ENDORSED,
 		T.AUTHOR=$1 AS IS_AUTHOR, -- uid parameter
 		(
 			SELECT
@@ -137,7 +170,7 @@ func (r *ThreadsRepository) GetAll(column models.SortColumn, uid string, page in
 				WHEN F.UID = $1 THEN 1
 				ELSE 0
 			END
-		)::BOOLEAN AS IS_FAVORITED
+		)::BOOLEAN AS IS_FAVORITED,%s
 	FROM
 		THREADS T
 		INNER JOIN USERS U ON T.AUTHOR = U.UID
@@ -173,18 +206,22 @@ func (r *ThreadsRepository) GetAll(column models.SortColumn, uid string, page in
 			-- 4) Author name match (non-anonymous only)
 			OR (NOT T.IS_ANON AND U.NAME ILIKE '%%' || $4 || '%%')
 		)
+		AND %s
+		AND %s
+		AND ($8::boolean = false OR T.TIME_ENDORSED IS NOT NULL)
 	GROUP BY
 		T.THREAD_ID,
 		U.UID
 	ORDER BY
-		%s %s
+		T.PINNED DESC, -- Pinned threads always come first
+		%s %s NULLS LAST
 	LIMIT $2
-	OFFSET $3;`, column, desc)
+	OFFSET $3;`, threadTagsColumn, threadTagsFilter(5), threadStatusFilter(7), column, desc)
 
-	utils.Logger.Debug().Str("column", string(column)).Int("page", page).Int("offset", offset).Int("size", size).Bool("descending", descending).Str("searchKeyword", searchKeyword).Msg("")
+	utils.Logger.Debug().Str("column", string(column)).Int("page", page).Int("offset", offset).Int("size", size).Bool("descending", descending).Str("searchKeyword", filter.Search).Strs("tags", filter.Tags).Msg("")
 
 	// Perform query and collect rows into array.
-	rows, _ := r.db.Query(context.Background(), query, uid, size, offset, searchKeyword)
+	rows, _ := r.db.Query(context.Background(), query, uid, size, offset, filter.Search, filter.Tags, filter.Match == models.TagMatchAll, filter.Status, filter.Endorsed)
 	threads, err := pgx.CollectRows(rows, pgx.RowToStructByName[models.Thread])
 	if err != nil {
 		utils.Logger.Error().Err(err).Msg("Error collecting rows")
@@ -197,7 +234,7 @@ func (r *ThreadsRepository) GetAll(column models.SortColumn, uid string, page in
 }
 
 // Get threads metadata
-func (r *ThreadsRepository) GetMetadata(searchKeyword string) (*models.ContentMetadata, error) {
+func (r *ThreadsRepository) GetMetadata(filter models.ThreadFilter) (*models.ContentMetadata, error) {
 	query := fmt.Sprintf(`
 	SELECT COUNT(*) AS COUNT 
 	FROM %s T 
@@ -230,9 +267,12 @@ func (r *ThreadsRepository) GetMetadata(searchKeyword string) (*models.ContentMe
 
 			-- 4) Author name match (non-anonymous only)
 			OR (NOT T.IS_ANON AND U.NAME ILIKE '%%' || $1 || '%%')
-		);`, THREADS_TABLE, USERS_TABLE)
+		)
+		AND %s
+		AND %s
+		AND ($5::boolean = false OR T.TIME_ENDORSED IS NOT NULL);`, THREADS_TABLE, USERS_TABLE, threadTagsFilter(2), threadStatusFilter(4))
 
-	row, _ := r.db.Query(context.Background(), query, searchKeyword)
+	row, _ := r.db.Query(context.Background(), query, filter.Search, filter.Tags, filter.Match == models.TagMatchAll, filter.Status, filter.Endorsed)
 	defer row.Close()
 	metadata, err := pgx.CollectOneRow(row, pgx.RowToAddrOfStructByName[models.ContentMetadata])
 	if err != nil {
@@ -287,6 +327,14 @@ func (r *ThreadsRepository) GetByID(thread_id string, uid string) (*models.Threa
 			ELSE USERS.NAME
 		END AS AUTHOR_NAME,
 		T.IS_ANON,
+		T.PINNED,
+		T.LOCKED,
+		T.ANNOUNCEMENT,
+		T.ACCEPTED_POST,
+		T.ACCEPTED_POST IS NOT NULL AS RESOLVED,
+		T.ENDORSED_BY,
+		(SELECT NAME FROM USERS WHERE UID = T.ENDORSED_BY) AS ENDORSER_NAME,
+		T.TIME_ENDORSED,
 		T.AUTHOR=$1 AS IS_AUTHOR,
 		(
 			SELECT
@@ -309,7 +357,7 @@ func (r *ThreadsRepository) GetByID(thread_id string, uid string) (*models.Threa
 				WHEN F.UID = $2 THEN 1
 				ELSE 0
 			END
-		)::BOOLEAN AS IS_FAVORITED
+		)::BOOLEAN AS IS_FAVORITED,%s
 	FROM
 		%s T
 		INNER JOIN USERS ON T.AUTHOR = USERS.UID
@@ -328,7 +376,7 @@ func (r *ThreadsRepository) GetByID(thread_id string, uid string) (*models.Threa
 		T.PREVIEW,
 		T.IS_AVAILABLE,
 		T.IS_ANON,
-		USERS.NAME;`, THREADS_TABLE)
+		USERS.NAME;`, threadTagsColumn, THREADS_TABLE)
 
 	utils.Logger.Trace().Msg(fmt.Sprintf("Getting thread with id: %v", thread_id))
 
@@ -374,6 +422,19 @@ func (r *ThreadsRepository) GetAuthor(thread_id string) (string, error) {
 	return author, nil
 }
 
+// Get title of thread by thread ID
+func (r *ThreadsRepository) GetTitle(threadID string) (string, error) {
+	query := fmt.Sprintf(`SELECT title FROM %s WHERE thread_id = $1;`, THREADS_TABLE)
+
+	var title string
+	if err := r.db.QueryRow(context.Background(), query, threadID).Scan(&title); err != nil {
+		utils.Logger.Error().Err(err).Msgf("Error fetching title of thread with id %s", threadID)
+		return "", err
+	}
+
+	return title, nil
+}
+
 // Returns true if the thread exists
 func (r *ThreadsRepository) IsAvailable(thread_id string) bool {
 	query := fmt.Sprintf(`SELECT is_available FROM %s WHERE thread_id = $1;`, THREADS_TABLE)
@@ -422,13 +483,49 @@ func (r *ThreadsRepository) Update(threadID string, title string, preview string
 	return nil
 }
 
+// Check if thread is locked. Returns pgx.ErrNoRows if the thread does not exist.
+func (r *ThreadsRepository) IsLocked(threadID string) (bool, error) {
+	query := fmt.Sprintf(`SELECT locked FROM %s WHERE thread_id = $1 AND is_available = true;`, THREADS_TABLE)
+
+	var locked bool
+	if err := r.db.QueryRow(context.Background(), query, threadID).Scan(&locked); err != nil {
+		utils.Logger.Warn().Err(err).Str("thread id", threadID).Msg("Error checking if thread is locked")
+		return false, err
+	}
+
+	return locked, nil
+}
+
+// Update pinned, locked and announcement states of thread. Returns whether the thread was an announcement before the
+// update and its title, or pgx.ErrNoRows if the thread does not exist.
+func (r *ThreadsRepository) UpdateState(threadID string, state models.ThreadState) (bool, string, error) {
+	query := fmt.Sprintf(`
+	UPDATE %[1]s T
+	SET pinned = COALESCE($2, T.pinned),
+		locked = COALESCE($3, T.locked),
+		announcement = COALESCE($4, T.announcement)
+	FROM (SELECT thread_id, announcement FROM %[1]s WHERE thread_id = $1 AND is_available = true FOR UPDATE) old
+	WHERE T.thread_id = old.thread_id
+	RETURNING old.announcement, T.title;`, THREADS_TABLE)
+
+	var wasAnnouncement bool
+	var title string
+	if err := r.db.QueryRow(context.Background(), query, threadID, state.Pinned, state.Locked, state.Announcement).Scan(&wasAnnouncement, &title); err != nil {
+		utils.Logger.Warn().Err(err).Str("thread id", threadID).Msg("Error updating state of thread")
+		return false, "", err
+	}
+
+	utils.Logger.Info().Str("thread id", threadID).Interface("state", state).Msg("Thread state updated")
+	return wasAnnouncement, title, nil
+}
+
 // Perform soft delete of the thread in the database. Returns nil if successful.
 // Karma rollback:
 //  1. Author's karma is decremented by CREATE_THREAD_PTS
 //  2. All posts with matching thread_id are matched with their authors and decremented by CREATE_POST_PTS
 //  3. We do not decrement the karma of people who had liked the posts in the thread. If you had given a
 //     good answer to a post, your karma should be deserved and thus not decremented.
-func (r *ThreadsRepository) Delete(threadID string) error {
+func (r *ThreadsRepository) Delete(threadID string, deletedBy string) error {
 	ctx := context.Background()
 
 	// Begin transaction
@@ -442,11 +539,11 @@ func (r *ThreadsRepository) Delete(threadID string) error {
 	var author string
 	query := fmt.Sprintf(`
 		UPDATE %s
-		SET is_available = false, last_activity = NOW()
+		SET is_available = false, last_activity = NOW(), deleted_by = $2, time_deleted = NOW()
 		WHERE thread_id = $1 AND is_available = true
 		RETURNING author;`, THREADS_TABLE)
 
-	if err = tx.QueryRow(ctx, query, threadID).Scan(&author); err != nil {
+	if err = tx.QueryRow(ctx, query, threadID, deletedBy).Scan(&author); err != nil {
 		utils.Logger.Error().Err(err).Msg("Error deleting thread")
 		return errors.New("thread is not available or does not exist")
 	} else if author == "" {
@@ -456,12 +553,9 @@ func (r *ThreadsRepository) Delete(threadID string) error {
 	utils.Logger.Trace().Msg("Thread deleted")
 
 	// Update author's karma
-	query = fmt.Sprintf(`
-	UPDATE %s
-	SET karma = GREATEST(karma - %d, 0)
-	WHERE uid = $1;`, USERS_TABLE, models.CREATE_THREAD_PTS)
+	query = takeKarmaQuery(models.CREATE_THREAD_PTS, THREADS_TABLE, "thread_id")
 
-	if _, err = tx.Exec(ctx, query, author); err != nil {
+	if _, err = tx.Exec(ctx, query, author, threadID); err != nil {
 		utils.Logger.Error().Err(err).Msg("Error updating author's karma")
 		return err
 	}
@@ -479,13 +573,15 @@ func (r *ThreadsRepository) Delete(threadID string) error {
 	utils.Logger.Trace().Str("thread id", threadID).Msg("Thread deleted from likes table")
 
 	// Remove thread from posts table.
+	// time_deleted of the posts is the same as that of the thread, as NOW() is the start of the transaction. Restore uses
+	// this to tell them apart from posts deleted earlier.
 	query = fmt.Sprintf(`
 	UPDATE %s
-	SET is_available = false, last_edited = NOW()
+	SET is_available = false, last_edited = NOW(), deleted_by = $2, time_deleted = NOW()
 	WHERE thread_id = $1 AND is_available = true
 	RETURNING post_id, author, is_header;`, POSTS_TABLE)
 
-	rows, err := tx.Query(ctx, query, threadID)
+	rows, err := tx.Query(ctx, query, threadID, deletedBy)
 	if err != nil {
 		utils.Logger.Error().Err(err).Msg("Error deleting from posts table posts with thread id of " + threadID)
 		return err
@@ -518,20 +614,19 @@ func (r *ThreadsRepository) Delete(threadID string) error {
 		// Update karma of the author of the post
 		// Do not update karma of the author of the header post
 		if !isHeader {
-			query = fmt.Sprintf(`
-			UPDATE %s
-			SET karma = GREATEST(karma - %d, 0)
-			WHERE uid = $1;`, USERS_TABLE, models.CREATE_POST_PTS)
+			query = takeKarmaQuery(models.CREATE_POST_PTS, POSTS_TABLE, "post_id")
 
-			batch.Queue(query, author)
+			batch.Queue(query, author, postID)
 			utils.Logger.Trace().Str("query", query).Msg("Query added to batch")
 		}
 	}
 	rows.Close()
 
 	// Execute batch delete
-	br := tx.SendBatch(ctx, batch)
-	br.Close()
+	if err = tx.SendBatch(ctx, batch).Close(); err != nil {
+		utils.Logger.Error().Err(err).Msg("Error deleting posts of thread")
+		return err
+	}
 	utils.Logger.Trace().Msg("Batch delete executed")
 
 	// Commit transaction
@@ -545,37 +640,71 @@ func (r *ThreadsRepository) Delete(threadID string) error {
 	return nil
 }
 
-// (NOT USED)
-// Perform hard delete of the thread in the database. Returns nil if successful.
-// func (r *ThreadsRepository) HardDelete(threadID string) error {
-// 	query := fmt.Sprintf(`
-// 	DELETE FROM %s
-// 	WHERE thread_id = $1;`, THREADS_TABLE)
-
-// 	_, err := r.Db.Exec(context.Background(), query, threadID)
-// 	if err != nil {
-// 		utils.Logger.Error().Err(err).Msg("Error deleting thread")
-// 		return err
-// 	}
-
-// 	utils.Logger.Info().Msg(fmt.Sprintf("%s successfully deleted (HARD DELETE)", threadID))
-// 	return nil
-// }
-
-// Restore deleted thread by thread_id. Returns nil if successful.
-// Currently unused.
-// func (r *ThreadsRepository) Restore(threadID string) error {
-// 	query := fmt.Sprintf(`
-// 	UPDATE %s
-// 	SET is_available = true
-// 	WHERE thread_id = $1;`, THREADS_TABLE)
-
-// 	_, err := r.Db.Exec(context.Background(), query, threadID)
-// 	if err != nil {
-// 		utils.Logger.Error().Err(err).Msg("Error restoring thread")
-// 		return err
-// 	}
-
-// 	utils.Logger.Info().Msg(fmt.Sprintf("%s successfully restored", threadID))
-// 	return nil
-// }
+// Restore a deleted thread together with the posts deleted with it, including its header post, and give back the karma
+// that Delete took from the authors. Posts deleted before the thread stay deleted. Returns pgx.ErrNoRows if the thread is
+// not deleted.
+func (r *ThreadsRepository) Restore(threadID string) error {
+	ctx := context.Background()
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		utils.Logger.Error().Err(err).Msg("Error starting transaction")
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	var author string
+	var timeDeleted *time.Time
+	var karmaTaken int
+	query := fmt.Sprintf(`SELECT author, time_deleted, karma_taken FROM %s WHERE thread_id = $1 AND is_available = false FOR UPDATE;`, THREADS_TABLE)
+
+	if err = tx.QueryRow(ctx, query, threadID).Scan(&author, &timeDeleted, &karmaTaken); err != nil {
+		utils.Logger.Warn().Err(err).Str("thread id", threadID).Msg("Deleted thread not found")
+		return err
+	}
+
+	query = fmt.Sprintf(`
+	UPDATE %s
+	SET is_available = true, deleted_by = NULL, time_deleted = NULL
+	WHERE thread_id = $1;`, THREADS_TABLE)
+
+	if _, err = tx.Exec(ctx, query, threadID); err != nil {
+		utils.Logger.Error().Err(err).Msg("Error restoring thread")
+		return err
+	}
+
+	// Restore author's karma
+	query = fmt.Sprintf(`UPDATE %s SET karma = karma + $2 WHERE uid = $1;`, USERS_TABLE)
+
+	if _, err = tx.Exec(ctx, query, author, karmaTaken); err != nil {
+		utils.Logger.Error().Err(err).Msg("Error restoring author's karma")
+		return err
+	}
+
+	// Restore posts deleted with the thread and the karma of their authors. Posts deleted with the thread share its
+	// time_deleted; posts without a time_deleted were deleted before deletions were recorded, so they are left deleted.
+	query = fmt.Sprintf(`
+	WITH restored AS (
+		UPDATE %s
+		SET is_available = true, deleted_by = NULL, time_deleted = NULL
+		WHERE thread_id = $1 AND is_available = false AND time_deleted IS NOT NULL AND time_deleted = $2
+		RETURNING author, karma_taken
+	)
+	UPDATE %s u
+	SET karma = karma + r.karma_taken
+	FROM (SELECT author, SUM(karma_taken) AS karma_taken FROM restored GROUP BY author) r
+	WHERE u.uid = r.author;`, POSTS_TABLE, USERS_TABLE)
+
+	if _, err = tx.Exec(ctx, query, threadID, timeDeleted); err != nil {
+		utils.Logger.Error().Err(err).Msg("Error restoring posts of thread")
+		return err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		utils.Logger.Error().Err(err).Msg("Error committing transaction")
+		return err
+	}
+
+	utils.Logger.Info().Msg(fmt.Sprintf("%s successfully restored", threadID))
+	return nil
+}
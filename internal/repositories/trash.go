@@ -0,0 +1,130 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/ntu-onemdp/onemdp-backend/internal/models"
+	"github.com/ntu-onemdp/onemdp-backend/internal/utils"
+)
+
+// Soft deleted threads, posts, articles and comments. Posts of deleted threads and comments of deleted articles are left
+// out, as they come back when their thread or article is restored.
+var trashQuery = fmt.Sprintf(`
+	SELECT 'thread' AS content_type, T.thread_id AS content_id, NULL::text AS parent_id, T.title, COALESCE(T.preview, '') AS preview, T.author, T.deleted_by, T.time_deleted
+	FROM %[1]s T
+	WHERE T.is_available = false
+	UNION ALL
+	SELECT 'post', P.post_id, P.thread_id, P.title, LEFT(P.content, 250), P.author, P.deleted_by, P.time_deleted
+	FROM %[2]s P INNER JOIN %[1]s T ON T.thread_id = P.thread_id
+	WHERE P.is_available = false AND P.is_header = false AND T.is_available = true
+	UNION ALL
+	SELECT 'article', A.article_id, NULL, A.title, COALESCE(A.preview, ''), A.author, A.deleted_by, A.time_deleted
+	FROM %[3]s A
+	WHERE A.is_available = false
+	UNION ALL
+	SELECT 'comment', C.comment_id, C.article_id, '', LEFT(C.content, 250), C.author, C.deleted_by, C.time_deleted
+	FROM %[4]s C INNER JOIN %[3]s A ON A.article_id = C.article_id
+	WHERE C.is_available = false AND A.is_available = true`, THREADS_TABLE, POSTS_TABLE, ARTICLES_TABLE, COMMENTS_TABLE)
+
+type TrashRepository struct {
+	db *pgxpool.Pool
+}
+
+var Trash *TrashRepository
+
+// Retrieve a page of deleted content, most recently deleted first. Content is filtered by type if it is not empty.
+func (r *TrashRepository) GetAll(contentType string, page int, size int) ([]models.TrashItem, error) {
+	query := fmt.Sprintf(`
+	SELECT trash.*, U.name AS author_name
+	FROM (%s) trash
+	INNER JOIN %s U ON U.uid = trash.author
+	WHERE $1 = '' OR trash.content_type = $1
+	ORDER BY trash.time_deleted DESC NULLS LAST
+	LIMIT $2 OFFSET $3;`, trashQuery, USERS_TABLE)
+
+	rows, _ := r.db.Query(context.Background(), query, contentType, size, (page-1)*size)
+	items, err := pgx.CollectRows(rows, pgx.RowToStructByName[models.TrashItem])
+	if err != nil {
+		utils.Logger.Error().Err(err).Msg("Error retrieving trash")
+		return nil, err
+	}
+
+	return items, nil
+}
+
+// Count deleted content, filtered by type if it is not empty.
+func (r *TrashRepository) GetMetadata(contentType string) (*models.ContentMetadata, error) {
+	query := fmt.Sprintf(`SELECT COUNT(*) AS count FROM (%s) trash WHERE $1 = '' OR trash.content_type = $1;`, trashQuery)
+
+	row, _ := r.db.Query(context.Background(), query, contentType)
+	metadata, err := pgx.CollectOneRow(row, pgx.RowToAddrOfStructByName[models.ContentMetadata])
+	if err != nil {
+		utils.Logger.Error().Err(err).Msg("Error counting trash")
+		return nil, err
+	}
+
+	return metadata, nil
+}
+
+// Permanently delete content which was soft deleted before cutoff, together with everything that belongs to it:
+// posts of threads, comments of articles, revisions, likes, favorites and views. Replies to deleted posts and comments
+// are kept. Returns number of threads, posts, articles and comments deleted.
+func (r *TrashRepository) Purge(cutoff time.Time) (int64, error) {
+	ctx := context.Background()
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		utils.Logger.Error().Err(err).Msg("Error starting transaction for purging trash")
+		return 0, err
+	}
+	defer tx.Rollback(ctx)
+
+	// Comments go before articles and posts before threads so that their ids are collected
+	queries := []string{
+		fmt.Sprintf(`
+		DELETE FROM %s WHERE (is_available = false AND time_deleted < $1)
+		OR article_id IN (SELECT article_id FROM %s WHERE is_available = false AND time_deleted < $1)
+		RETURNING comment_id;`, COMMENTS_TABLE, ARTICLES_TABLE),
+		fmt.Sprintf(`
+		DELETE FROM %s WHERE (is_available = false AND time_deleted < $1)
+		OR thread_id IN (SELECT thread_id FROM %s WHERE is_available = false AND time_deleted < $1)
+		RETURNING post_id;`, POSTS_TABLE, THREADS_TABLE),
+		fmt.Sprintf(`DELETE FROM %s WHERE is_available = false AND time_deleted < $1 RETURNING article_id;`, ARTICLES_TABLE),
+		fmt.Sprintf(`DELETE FROM %s WHERE is_available = false AND time_deleted < $1 RETURNING thread_id;`, THREADS_TABLE),
+	}
+
+	ids := []string{}
+	for _, query := range queries {
+		rows, _ := tx.Query(ctx, query, cutoff)
+		deleted, err := pgx.CollectRows(rows, pgx.RowTo[string])
+		if err != nil {
+			utils.Logger.Error().Err(err).Msg("Error purging trash")
+			return 0, err
+		}
+		ids = append(ids, deleted...)
+	}
+
+	if len(ids) == 0 {
+		return 0, nil
+	}
+
+	// Likes, favorites, views and mentions reference content without a foreign key
+	for _, table := range []string{LIKES_TABLE, FAVORITES_TABLE, VIEWS_TABLE, MENTIONS_TABLE} {
+		query := fmt.Sprintf(`DELETE FROM %s WHERE content_id = ANY($1);`, table)
+		if _, err := tx.Exec(ctx, query, ids); err != nil {
+			utils.Logger.Error().Err(err).Msgf("Error purging deleted content from %s", table)
+			return 0, err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		utils.Logger.Error().Err(err).Msg("Error committing transaction after purging trash")
+		return 0, err
+	}
+
+	return int64(len(ids)), nil
+}
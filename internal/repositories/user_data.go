@@ -0,0 +1,267 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/ntu-onemdp/onemdp-backend/internal/models"
+	"github.com/ntu-onemdp/onemdp-backend/internal/notification"
+	"github.com/ntu-onemdp/onemdp-backend/internal/semester"
+	"github.com/ntu-onemdp/onemdp-backend/internal/utils"
+)
+
+// Tables without their own repository
+const VIEWS_TABLE = "views"
+
+// Tables with an author column referencing users
+var authoredTables = []string{THREADS_TABLE, POSTS_TABLE, ARTICLES_TABLE, COMMENTS_TABLE, FILES_TABLE}
+
+// Tables with a replaced_by column referencing the user whose edit replaced a previous version of content
+var revisionTables = []string{POST_REVISIONS_TABLE, ARTICLE_REVISIONS_TABLE}
+
+// Remove user in a single transaction. Their content, mentions and polls are transferred to newAuthor, which is either the
+// placeholder deleted user or another user, together with previous versions of the content and the edits they made. Likes
+// (together with the karma they gave), favorites, views, poll votes, mentions of them, notifications, notification
+// preferences, drafts, pending registrations and failed attempts with their email are deleted, all sessions and api keys
+// are revoked, and personal data (name, email, profile photo) is erased. The users row is kept with status removed so
+// that audit records referencing the uid stay valid.
+//
+// Returns pgx.ErrNoRows if the user does not exist or has already been removed.
+func (r *UsersRepository) RemoveUser(uid string, newAuthor string, reason *string) error {
+	ctx := context.Background()
+
+	tx, err := r.Db.Begin(ctx)
+	if err != nil {
+		utils.Logger.Error().Err(err).Msg("Failed to begin transaction")
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	// Email is erased below, but is needed to delete data recorded against it
+	var email string
+	query := fmt.Sprintf(`SELECT email FROM %s WHERE uid = $1 AND status <> $2 FOR UPDATE;`, USERS_TABLE)
+	if err := tx.QueryRow(ctx, query, uid, models.StatusRemoved).Scan(&email); err != nil {
+		if !errors.Is(err, pgx.ErrNoRows) {
+			utils.Logger.Error().Err(err).Str("uid", uid).Msg("Failed to retrieve removed user")
+		}
+		return err
+	}
+
+	// Mark user as removed and erase personal data. Email must stay unique, so it is replaced by the uid.
+	query = fmt.Sprintf(`
+	UPDATE %s SET
+		status = $1, status_reason = $2, suspended_until = NULL, date_removed = NOW(),
+		name = '[removed user]', email = 'removed:' || uid, profile_photo = NULL, tutorial_group = NULL, karma = 0
+	WHERE uid = $3 AND status <> $1;`, USERS_TABLE)
+
+	res, err := tx.Exec(ctx, query, models.StatusRemoved, reason, uid)
+	if err != nil {
+		utils.Logger.Error().Err(err).Str("uid", uid).Msg("Failed to update removed user")
+		return err
+	}
+	if res.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+
+	// Transfer content
+	for _, table := range authoredTables {
+		query = fmt.Sprintf(`UPDATE %s SET author = $1 WHERE author = $2;`, table)
+		res, err := tx.Exec(ctx, query, newAuthor, uid)
+		if err != nil {
+			utils.Logger.Error().Err(err).Str("uid", uid).Str("table", table).Msg("Failed to transfer content")
+			return err
+		}
+		utils.Logger.Debug().Str("uid", uid).Str("table", table).Int64("num rows", res.RowsAffected()).Msg("Content transferred")
+	}
+
+	// Edits of the user are transferred together with their content
+	for _, table := range revisionTables {
+		query = fmt.Sprintf(`UPDATE %s SET replaced_by = $1 WHERE replaced_by = $2;`, table)
+		if _, err := tx.Exec(ctx, query, newAuthor, uid); err != nil {
+			utils.Logger.Error().Err(err).Str("uid", uid).Str("table", table).Msg("Failed to transfer revisions")
+			return err
+		}
+	}
+
+	// Mentions made by the user follow their content
+	query = fmt.Sprintf(`UPDATE %s SET author = $1 WHERE author = $2;`, MENTIONS_TABLE)
+	if _, err := tx.Exec(ctx, query, newAuthor, uid); err != nil {
+		utils.Logger.Error().Err(err).Str("uid", uid).Msg("Failed to transfer mentions")
+		return err
+	}
+
+	query = fmt.Sprintf(`UPDATE %s SET created_by = $1 WHERE created_by = $2;`, POLLS_TABLE)
+	if _, err := tx.Exec(ctx, query, newAuthor, uid); err != nil {
+		utils.Logger.Error().Err(err).Str("uid", uid).Msg("Failed to transfer polls")
+		return err
+	}
+
+	// Take back karma given by the user's likes, as in LikesRepository.Delete
+	query = fmt.Sprintf(`
+	UPDATE %s u SET karma = GREATEST(u.karma - %d * given.num_likes, 0)
+	FROM (
+		SELECT c.author, COUNT(*) AS num_likes
+		FROM %s l JOIN (
+			SELECT thread_id AS content_id, author FROM %s WHERE is_available
+			UNION ALL SELECT post_id, author FROM %s WHERE is_available
+			UNION ALL SELECT article_id, author FROM %s WHERE is_available
+			UNION ALL SELECT comment_id, author FROM %s WHERE is_available
+		) c ON l.content_id = c.content_id
+		WHERE l.uid = $1
+		GROUP BY c.author
+	) given
+	WHERE u.uid = given.author;`, USERS_TABLE, models.LIKE_PTS, LIKES_TABLE, THREADS_TABLE, POSTS_TABLE, ARTICLES_TABLE, COMMENTS_TABLE)
+
+	if _, err := tx.Exec(ctx, query, uid); err != nil {
+		utils.Logger.Error().Err(err).Str("uid", uid).Msg("Failed to revert karma from likes")
+		return err
+	}
+
+	// Delete personal activity
+	for _, table := range []string{LIKES_TABLE, FAVORITES_TABLE, VIEWS_TABLE, POLL_VOTES_TABLE, MENTIONS_TABLE,
+		notification.NOTIFICATIONS_TABLE, notification.NOTIFICATION_MUTES_TABLE} {
+		query = fmt.Sprintf(`DELETE FROM %s WHERE uid = $1;`, table)
+		if _, err := tx.Exec(ctx, query, uid); err != nil {
+			utils.Logger.Error().Err(err).Str("uid", uid).Str("table", table).Msg("Failed to delete user activity")
+			return err
+		}
+	}
+
+	// Notifications the user triggered are kept for their recipients, without the actor
+	query = fmt.Sprintf(`UPDATE %s SET actor = NULL WHERE actor = $1;`, notification.NOTIFICATIONS_TABLE)
+	if _, err := tx.Exec(ctx, query, uid); err != nil {
+		utils.Logger.Error().Err(err).Str("uid", uid).Msg("Failed to remove actor from notifications")
+		return err
+	}
+
+	query = fmt.Sprintf(`DELETE FROM %s WHERE author = $1;`, DRAFTS_TABLE)
+	if _, err := tx.Exec(ctx, query, uid); err != nil {
+		utils.Logger.Error().Err(err).Str("uid", uid).Msg("Failed to delete drafts")
+		return err
+	}
+
+	// Delete data recorded against the email, which may include ips and user agents
+	query = fmt.Sprintf(`DELETE FROM %s WHERE email = lower($1);`, PENDING_USERS_TABLE)
+	if _, err := tx.Exec(ctx, query, email); err != nil {
+		utils.Logger.Error().Err(err).Str("uid", uid).Msg("Failed to delete pending registrations")
+		return err
+	}
+
+	query = fmt.Sprintf(`DELETE FROM %s WHERE lower(email) = lower($1);`, AUTH_FAILURES_TABLE)
+	if _, err := tx.Exec(ctx, query, email); err != nil {
+		utils.Logger.Error().Err(err).Str("uid", uid).Msg("Failed to delete failed attempts")
+		return err
+	}
+
+	// Revoke access
+	query = fmt.Sprintf(`UPDATE %s SET revoked_at = NOW() WHERE uid = $1 AND revoked_at IS NULL;`, SESSIONS_TABLE)
+	if _, err := tx.Exec(ctx, query, uid); err != nil {
+		utils.Logger.Error().Err(err).Str("uid", uid).Msg("Failed to revoke sessions")
+		return err
+	}
+
+	query = fmt.Sprintf(`UPDATE %s SET revoked_at = NOW() WHERE owner_uid = $1 AND revoked_at IS NULL;`, API_KEYS_TABLE)
+	if _, err := tx.Exec(ctx, query, uid); err != nil {
+		utils.Logger.Error().Err(err).Str("uid", uid).Msg("Failed to revoke api keys")
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		utils.Logger.Error().Err(err).Msg("Failed to commit transaction")
+		return err
+	}
+
+	utils.Logger.Info().Str("uid", uid).Str("new author", newAuthor).Msgf("User %s removed", uid)
+	return nil
+}
+
+// Collect everything stored about a user. Returns pgx.ErrNoRows if the user does not exist.
+func (r *UsersRepository) ExportUser(uid string) (*models.UserExport, error) {
+	ctx := context.Background()
+
+	// Read from a single snapshot so that the export is consistent
+	tx, err := r.Db.BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.RepeatableRead, AccessMode: pgx.ReadOnly})
+	if err != nil {
+		utils.Logger.Error().Err(err).Msg("Failed to begin transaction")
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	export := &models.UserExport{Uid: uid}
+
+	accounts, err := collectRecords(ctx, tx, fmt.Sprintf(`SELECT * FROM %s WHERE uid = $1;`, USERS_TABLE), uid)
+	if err != nil {
+		return nil, err
+	}
+	if len(accounts) == 0 {
+		return nil, pgx.ErrNoRows
+	}
+	export.Account = accounts[0]
+	email, _ := export.Account["email"].(string)
+
+	queries := []struct {
+		dst   *[]models.Record
+		query string
+		arg   string
+	}{
+		{&export.PendingUsers, fmt.Sprintf(`SELECT * FROM %s WHERE email = lower($1);`, PENDING_USERS_TABLE), email},
+		{&export.Threads, fmt.Sprintf(`SELECT * FROM %s WHERE author = $1 ORDER BY time_created;`, THREADS_TABLE), uid},
+		{&export.Posts, fmt.Sprintf(`SELECT * FROM %s WHERE author = $1 ORDER BY time_created;`, POSTS_TABLE), uid},
+		{&export.Articles, fmt.Sprintf(`SELECT * FROM %s WHERE author = $1 ORDER BY time_created;`, ARTICLES_TABLE), uid},
+		{&export.PostRevisions, fmt.Sprintf(`
+			SELECT * FROM %s
+			WHERE replaced_by = $1 OR post_id IN (SELECT post_id FROM %s WHERE author = $1)
+			ORDER BY post_id, revision;`, POST_REVISIONS_TABLE, POSTS_TABLE), uid},
+		{&export.ArticleRevisions, fmt.Sprintf(`
+			SELECT * FROM %s
+			WHERE replaced_by = $1 OR article_id IN (SELECT article_id FROM %s WHERE author = $1)
+			ORDER BY article_id, revision;`, ARTICLE_REVISIONS_TABLE, ARTICLES_TABLE), uid},
+		{&export.Comments, fmt.Sprintf(`SELECT * FROM %s WHERE author = $1 ORDER BY time_created;`, COMMENTS_TABLE), uid},
+		{&export.Drafts, fmt.Sprintf(`SELECT * FROM %s WHERE author = $1 ORDER BY time_created;`, DRAFTS_TABLE), uid},
+		{&export.Files, fmt.Sprintf(`SELECT * FROM %s WHERE author = $1 OR deleted_by = $1;`, FILES_TABLE), uid},
+		{&export.Mentions, fmt.Sprintf(`SELECT * FROM %s WHERE uid = $1 OR author = $1 ORDER BY time_created;`, MENTIONS_TABLE), uid},
+		{&export.Polls, fmt.Sprintf(`SELECT * FROM %s WHERE created_by = $1 ORDER BY time_created;`, POLLS_TABLE), uid},
+		{&export.PollVotes, fmt.Sprintf(`SELECT * FROM %s WHERE uid = $1 ORDER BY time_voted;`, POLL_VOTES_TABLE), uid},
+		{&export.Likes, fmt.Sprintf(`SELECT * FROM %s WHERE uid = $1;`, LIKES_TABLE), uid},
+		{&export.Favorites, fmt.Sprintf(`SELECT * FROM %s WHERE uid = $1;`, FAVORITES_TABLE), uid},
+		{&export.Views, fmt.Sprintf(`SELECT * FROM %s WHERE uid = $1;`, VIEWS_TABLE), uid},
+		{&export.Notifications, fmt.Sprintf(`SELECT * FROM %s WHERE uid = $1 OR actor = $1 ORDER BY time_created;`, notification.NOTIFICATIONS_TABLE), uid},
+		{&export.NotificationMutes, fmt.Sprintf(`SELECT * FROM %s WHERE uid = $1;`, notification.NOTIFICATION_MUTES_TABLE), uid},
+		{&export.Sessions, fmt.Sprintf(`
+			SELECT session_id, uid, user_agent, time_created, last_used, expires_at, revoked_at
+			FROM %s WHERE uid = $1 ORDER BY time_created;`, SESSIONS_TABLE), uid},
+		{&export.ApiKeys, fmt.Sprintf(`
+			SELECT key_id, name, owner_uid, max_role, scopes, time_created, expires_at, last_used, revoked_at
+			FROM %s WHERE owner_uid = $1 ORDER BY time_created;`, API_KEYS_TABLE), uid},
+		{&export.Redemptions, fmt.Sprintf(`SELECT * FROM %s WHERE uid = $1;`, semester.REDEMPTIONS_TABLE), uid},
+		{&export.AuthFailures, fmt.Sprintf(`SELECT * FROM %s WHERE lower(email) = lower($1) ORDER BY time_created;`, AUTH_FAILURES_TABLE), email},
+	}
+
+	for _, q := range queries {
+		if *q.dst, err = collectRecords(ctx, tx, q.query, q.arg); err != nil {
+			return nil, err
+		}
+	}
+
+	utils.Logger.Info().Str("uid", uid).Msgf("Data of %s exported", uid)
+	return export, nil
+}
+
+func collectRecords(ctx context.Context, tx pgx.Tx, query string, arg string) ([]models.Record, error) {
+	rows, _ := tx.Query(ctx, query, arg)
+	records, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (models.Record, error) {
+		return pgx.RowToMap(row)
+	})
+	if err != nil {
+		utils.Logger.Error().Err(err).Str("query", query).Msg("Error collecting records")
+		return nil, err
+	}
+
+	// Encode as empty arrays instead of null
+	if records == nil {
+		records = []models.Record{}
+	}
+	return records, nil
+}
@@ -46,12 +46,12 @@ var Users *UsersRepository
 //	err := usersRepo.AddPendingUser(newUser)
 func (r *UsersRepository) AddPendingUser(user *models.PendingUser) error {
 	query := `
-	INSERT INTO pending_users (email, role, semester) 
-	SELECT $1, $2, $3
+	INSERT INTO pending_users (email, role, semester, name, tutorial_group) 
+	SELECT $1, $2, $3, $4, $5
 	WHERE NOT EXISTS (
-    SELECT 1 FROM users WHERE email = $1);` // Ensure that the user does not already exist in the users table
+    SELECT 1 FROM users WHERE lower(email) = lower($1));` // Ensure that the user does not already exist in the users table
 
-	_, err := r.Db.Exec(context.Background(), query, user.Email, user.Role, user.Semester)
+	_, err := r.Db.Exec(context.Background(), query, user.Email, user.Role, user.Semester, user.Name, user.TutorialGroup)
 	if err != nil {
 		utils.Logger.Error().Err(err).Msg("")
 		return err
@@ -61,6 +61,58 @@ func (r *UsersRepository) AddPendingUser(user *models.PendingUser) error {
 	return nil
 }
 
+// Insert or overwrite pending users in a single transaction. Either all users are saved or none are.
+// Returns whether each user was newly created (true) or an existing pending user was overwritten (false).
+// Users that are already registered must be filtered out by the caller, see GetRegisteredEmails.
+func (r *UsersRepository) AddPendingUsers(users []models.PendingUser) ([]bool, error) {
+	ctx := context.Background()
+
+	tx, err := r.Db.Begin(ctx)
+	if err != nil {
+		utils.Logger.Error().Err(err).Msg("Failed to begin transaction")
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	// xmax is 0 only for rows that were inserted rather than updated
+	query := fmt.Sprintf(`
+	INSERT INTO %s (email, role, semester, name, tutorial_group)
+	VALUES ($1, $2, $3, $4, $5)
+	ON CONFLICT (email) DO UPDATE
+	SET role = EXCLUDED.role, semester = EXCLUDED.semester, name = EXCLUDED.name, tutorial_group = EXCLUDED.tutorial_group
+	RETURNING (xmax = 0);`, PENDING_USERS_TABLE)
+
+	created := make([]bool, len(users))
+	for i, user := range users {
+		if err := tx.QueryRow(ctx, query, user.Email, user.Role, user.Semester, user.Name, user.TutorialGroup).Scan(&created[i]); err != nil {
+			utils.Logger.Error().Err(err).Str("email", user.Email).Msg("Failed to insert pending user")
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		utils.Logger.Error().Err(err).Msg("Failed to commit transaction")
+		return nil, err
+	}
+
+	utils.Logger.Info().Msgf("%d pending users saved", len(users))
+	return created, nil
+}
+
+// Retrieve which of emails belong to registered users. Emails are compared case-insensitively and returned in lowercase.
+func (r *UsersRepository) GetRegisteredEmails(emails []string) ([]string, error) {
+	query := fmt.Sprintf(`SELECT lower(email) FROM %s WHERE lower(email) = ANY($1);`, USERS_TABLE)
+
+	rows, _ := r.Db.Query(context.Background(), query, emails)
+	registered, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		utils.Logger.Error().Err(err).Msg("Error retrieving registered emails")
+		return nil, err
+	}
+
+	return registered, nil
+}
+
 // RegisterUserFromPending finalizes a user's registration by moving them from the pending_users table to the users table.
 //
 // Parameters:
@@ -81,7 +133,7 @@ func (r *UsersRepository) RegisterUserFromPending(uid string, email string, name
 	defer tx.Rollback(ctx)
 
 	// Retrieve pending user
-	query := fmt.Sprintf(`SELECT * FROM %s WHERE email=$1;`, PENDING_USERS_TABLE)
+	query := fmt.Sprintf(`SELECT * FROM %s WHERE email=lower($1);`, PENDING_USERS_TABLE)
 
 	row, _ := tx.Query(ctx, query, email)
 	defer row.Close()
@@ -92,17 +144,17 @@ func (r *UsersRepository) RegisterUserFromPending(uid string, email string, name
 	}
 	utils.Logger.Trace().Msgf("Retrieved pending user: %s", pending_user.Email)
 
-	// Create new user from pending user
-	user := models.CreateUser(uid, name, pending_user.Email, pending_user.Role)
+	// Create new user from pending user, keeping the semester and tutorial group from the roster
+	user := models.CreateUserFromPending(&pending_user, uid, name)
 
 	// Insert user into users table
 	query = fmt.Sprintf(`
-	INSERT INTO %s (email, uid, name, role, semester)
-	VALUES ($1, $2, $3, $4, $5)
+	INSERT INTO %s (email, uid, name, role, semester, tutorial_group)
+	VALUES ($1, $2, $3, $4, $5, $6)
 	ON CONFLICT (email) DO UPDATE
-	SET uid = EXCLUDED.uid, name = EXCLUDED.name, role = EXCLUDED.role, semester = EXCLUDED.semester;`, USERS_TABLE)
+	SET uid = EXCLUDED.uid, name = EXCLUDED.name, role = EXCLUDED.role, semester = EXCLUDED.semester, tutorial_group = EXCLUDED.tutorial_group;`, USERS_TABLE)
 
-	if _, err := tx.Exec(ctx, query, user.Email, user.Uid, user.Name, user.Role, user.Semester); err != nil {
+	if _, err := tx.Exec(ctx, query, user.Email, user.Uid, user.Name, user.Role, user.Semester, user.TutorialGroup); err != nil {
 		utils.Logger.Error().Err(err).Msg("Failed to insert user into users table")
 		return err
 	}
@@ -127,9 +179,9 @@ func (r *UsersRepository) RegisterUserFromPending(uid string, email string, name
 
 // Register user by adding them into users table.
 func (r *UsersRepository) RegisterUser(user *models.User) error {
-	query := fmt.Sprintf(`INSERT INTO %s (UID, NAME, EMAIL, ROLE, SEMESTER) VALUES ($1, $2, $3, $4, $5);`, USERS_TABLE)
+	query := fmt.Sprintf(`INSERT INTO %s (UID, NAME, EMAIL, ROLE, SEMESTER, TUTORIAL_GROUP) VALUES ($1, $2, $3, $4, $5, $6);`, USERS_TABLE)
 
-	if _, err := r.Db.Exec(context.Background(), query, user.Uid, user.Name, user.Email, user.Role, user.Semester); err != nil {
+	if _, err := r.Db.Exec(context.Background(), query, user.Uid, user.Name, user.Email, user.Role, user.Semester, user.TutorialGroup); err != nil {
 		utils.Logger.Error().Err(err).Msgf("Error inserting user with uid %s", user.Uid)
 		return err
 	}
@@ -140,7 +192,7 @@ func (r *UsersRepository) RegisterUser(user *models.User) error {
 
 // Checks if user is pending registration
 func (r *UsersRepository) IsUserPending(email string) (bool, error) {
-	query := fmt.Sprintf(`SELECT EXISTS(SELECT 1 FROM %s WHERE email=$1);`, PENDING_USERS_TABLE)
+	query := fmt.Sprintf(`SELECT EXISTS(SELECT 1 FROM %s WHERE email=lower($1));`, PENDING_USERS_TABLE)
 	var exists bool
 	err := r.Db.QueryRow(context.Background(), query, email).Scan(&exists)
 	if err != nil {
@@ -185,22 +237,54 @@ func (r *UsersRepository) GetAllUsers() ([]models.User, error) {
 	return users, nil
 }
 
-// Retrieve user's status using uid.
+// Retrieve user's account status using uid.
 // Throws error if uid cannot be found
-// Deprecate in the future
-func (r *UsersRepository) GetStatus(uid string) (string, error) {
-	query := fmt.Sprintf(`SELECT status FROM %s WHERE uid=$1;`, USERS_TABLE)
+func (r *UsersRepository) GetAccountStatus(uid string) (*models.AccountStatus, error) {
+	query := fmt.Sprintf(`SELECT status, status_reason, suspended_until FROM %s WHERE uid=$1;`, USERS_TABLE)
 
-	var status string
-	err := r.Db.QueryRow(context.Background(), query, uid).Scan(&status)
+	row, _ := r.Db.Query(context.Background(), query, uid)
+	status, err := pgx.CollectOneRow(row, pgx.RowToAddrOfStructByName[models.AccountStatus])
 	if err != nil {
-		utils.Logger.Error().Err(err).Msg("")
-		return "", err
+		utils.Logger.Error().Err(err).Str("uid", uid).Msg("Error retrieving account status")
+		return nil, err
 	}
 
 	return status, nil
 }
 
+// Update user's account status. Returns pgx.ErrNoRows if user does not exist or has been removed.
+func (r *UsersRepository) UpdateAccountStatus(uid string, status *models.AccountStatus) error {
+	query := fmt.Sprintf(`UPDATE %s SET status=$1, status_reason=$2, suspended_until=$3 WHERE uid=$4 AND status<>$5;`, USERS_TABLE)
+
+	res, err := r.Db.Exec(context.Background(), query, status.Status, status.StatusReason, status.SuspendedUntil, uid, models.StatusRemoved)
+	if err != nil {
+		utils.Logger.Error().Err(err).Str("uid", uid).Msg("Error updating account status")
+		return err
+	}
+
+	if res.RowsAffected() == 0 {
+		utils.Logger.Warn().Str("uid", uid).Msg("User not found, account status not updated")
+		return pgx.ErrNoRows
+	}
+
+	utils.Logger.Info().Str("uid", uid).Str("status", status.Status).Msgf("Account status of %s updated", uid)
+	return nil
+}
+
+// Deactivate all active students of a semester. Returns number of users deactivated.
+func (r *UsersRepository) DeactivateSemester(semester string, reason *string) (int64, error) {
+	query := fmt.Sprintf(`UPDATE %s SET status=$1, status_reason=$2, suspended_until=NULL WHERE semester=$3 AND role='student' AND status=$4;`, USERS_TABLE)
+
+	res, err := r.Db.Exec(context.Background(), query, models.StatusDeactivated, reason, semester, models.StatusActive)
+	if err != nil {
+		utils.Logger.Error().Err(err).Str("semester", semester).Msg("Error deactivating users of semester")
+		return 0, err
+	}
+
+	utils.Logger.Info().Str("semester", semester).Int64("num deactivated", res.RowsAffected()).Msgf("Students of semester %s deactivated", semester)
+	return res.RowsAffected(), nil
+}
+
 // Retrieve user's information from uid
 // This function *checks* if user is active before returning. If the user's status is not 'active',
 // an error is return instead.
@@ -234,8 +318,9 @@ func (r *UsersRepository) GetUserByUidAdmin(uid string) (*models.User, error) {
 }
 
 // Retrieve public profile information by uid
+// Profiles of suspended and deactivated users remain visible. Banned users are hidden.
 func (r *UsersRepository) GetUserProfile(uid string) (*models.UserProfile, error) {
-	query := fmt.Sprintf(`SELECT uid, email, name, role, profile_photo, semester, karma FROM %s WHERE uid=$1 AND status='active';`, USERS_TABLE)
+	query := fmt.Sprintf(`SELECT uid, email, name, role, profile_photo, semester, karma FROM %s WHERE uid=$1 AND status IN ('active', 'suspended', 'deactivated');`, USERS_TABLE)
 
 	row, _ := r.Db.Query(context.Background(), query, uid)
 	profile, err := pgx.CollectOneRow(row, pgx.RowToStructByName[models.UserProfile])
@@ -298,6 +383,24 @@ func (r *UsersRepository) GetTopKarma(n int) ([]models.UserProfile, error) {
 	return profiles, nil
 }
 
+// Search active users by name for the mention picker. Names starting with q are listed first.
+func (r *UsersRepository) Search(q string, limit int) ([]models.MentionCandidate, error) {
+	query := fmt.Sprintf(`
+	SELECT UID, NAME, ROLE FROM %s
+	WHERE STATUS = 'active' AND UID <> $3 AND NAME ILIKE '%%' || $1 || '%%'
+	ORDER BY NAME ILIKE $1 || '%%' DESC, NAME
+	LIMIT $2;`, USERS_TABLE)
+
+	rows, _ := r.Db.Query(context.Background(), query, q, limit, constants.DELETED_USER_UID)
+	users, err := pgx.CollectRows(rows, pgx.RowToStructByName[models.MentionCandidate])
+	if err != nil {
+		utils.Logger.Error().Err(err).Str("query", q).Msg("Error searching users")
+		return nil, err
+	}
+
+	return users, nil
+}
+
 // Update user's role
 func (r *UsersRepository) UpdateUserRole(uid string, role string) error {
 	query := fmt.Sprintf(`UPDATE %s SET role=$1 WHERE uid=$2;`, USERS_TABLE)
@@ -1,10 +1,14 @@
 package semester
 
-import gonanoid "github.com/matoous/go-nanoid/v2"
+import (
+	"time"
+
+	gonanoid "github.com/matoous/go-nanoid/v2"
+	constants "github.com/ntu-onemdp/onemdp-backend/config"
+)
 
 type Semester struct {
 	Semester  string `db:"semester"`
-	Code      string `db:"code"`
 	IsCurrent bool   `db:"is_current"`
 }
 
@@ -12,11 +16,61 @@ func NewSemester(semester string) *Semester {
 
 	return &Semester{
 		Semester:  semester,
-		Code:      generateEnrolmentCode(),
 		IsCurrent: true,
 	}
 }
 
+// Code used to register into a semester. Each semester has one default code and any number of additional codes,
+// e.g. a separate code for TAs or one per tutorial group.
+type EnrolmentCode struct {
+	CodeID        string     `json:"code_id" db:"code_id"`
+	Code          string     `json:"code" db:"code"`
+	Semester      string     `json:"semester" db:"semester"`
+	Role          string     `json:"role" db:"role"`                     // Role granted to users registering with this code
+	TutorialGroup *string    `json:"tutorial_group" db:"tutorial_group"` // Tutorial group assigned to users registering with this code
+	MaxUses       *int       `json:"max_uses" db:"max_uses"`             // Unlimited if nil
+	NumUses       int        `json:"num_uses" db:"num_uses"`
+	ExpiresAt     *time.Time `json:"expires_at" db:"expires_at"` // Never expires if nil
+	IsDefault     bool       `json:"is_default" db:"is_default"`
+	Disabled      bool       `json:"disabled" db:"disabled"`
+	CreatedBy     *string    `json:"created_by" db:"created_by"`
+	TimeCreated   time.Time  `json:"time_created" db:"time_created"`
+}
+
+func NewEnrolmentCode(semester string, role string, tutorialGroup *string, maxUses *int, expiresAt *time.Time, createdBy *string) *EnrolmentCode {
+	return &EnrolmentCode{
+		CodeID:        "e" + gonanoid.Must(constants.CONTENT_ID_LENGTH),
+		Code:          generateEnrolmentCode(),
+		Semester:      semester,
+		Role:          role,
+		TutorialGroup: tutorialGroup,
+		MaxUses:       maxUses,
+		NumUses:       0,
+		ExpiresAt:     expiresAt,
+		IsDefault:     false,
+		Disabled:      false,
+		CreatedBy:     createdBy,
+		TimeCreated:   time.Now(),
+	}
+}
+
+// Default student code of a semester. Never expires and has no usage cap.
+func newDefaultCode(semester string) *EnrolmentCode {
+	code := NewEnrolmentCode(semester, "student", nil, nil, nil, nil)
+	code.IsDefault = true
+
+	return code
+}
+
+// Record of a user registering with an enrolment code
+type Redemption struct {
+	CodeID       string    `json:"code_id" db:"code_id"`
+	Uid          string    `json:"uid" db:"uid"`
+	Name         string    `json:"name" db:"name"`
+	Email        string    `json:"email" db:"email"`
+	TimeRedeemed time.Time `json:"time_redeemed" db:"time_redeemed"`
+}
+
 func generateEnrolmentCode() string {
 	// Alphabets should not contain ambigious characters i.e. iIlLoO0
 	const alphabets = "abcdefghjkmnpqrstuvwxyzABCDEFGHJKMNPQRSTUVWXYZ23456789"
@@ -2,14 +2,18 @@ package semester
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/ntu-onemdp/onemdp-backend/internal/utils"
 )
 
 const SEMESTER_TABLE = "semesters"
+const ENROLMENT_CODES_TABLE = "enrolment_codes"
+const REDEMPTIONS_TABLE = "enrolment_code_redemptions"
 
 type SemesterRepository struct {
 	db *pgxpool.Pool
@@ -17,8 +21,8 @@ type SemesterRepository struct {
 
 var repo *SemesterRepository
 
-// Insert new sem
-func (r *SemesterRepository) insert(semester Semester) error {
+// Insert new sem together with its default enrolment code
+func (r *SemesterRepository) insert(semester Semester, code EnrolmentCode) error {
 	ctx := context.Background()
 
 	// Begin transaction
@@ -38,19 +42,24 @@ func (r *SemesterRepository) insert(semester Semester) error {
 	}
 
 	// Insert new semester into database
-	query = fmt.Sprintf(`INSERT INTO %s (SEMESTER, CODE, IS_CURRENT) VALUES $1, $2, $3;`, SEMESTER_TABLE)
-	if _, err := tx.Exec(ctx, query, semester.Semester, semester.Code, semester.IsCurrent); err != nil {
+	query = fmt.Sprintf(`INSERT INTO %s (SEMESTER, IS_CURRENT) VALUES ($1, $2);`, SEMESTER_TABLE)
+	if _, err := tx.Exec(ctx, query, semester.Semester, semester.IsCurrent); err != nil {
 		utils.Logger.Error().Err(err).Msg("Error inserting new semester into database")
 		return err
 	}
 
+	// Insert default code of new semester
+	if err := insertCode(ctx, tx, code); err != nil {
+		return err
+	}
+
 	// Commit transaction
 	if err = tx.Commit(ctx); err != nil {
 		utils.Logger.Error().Err(err).Msg("Error committing transaction")
 		return err
 	}
 
-	utils.Logger.Info().Str("Semester", semester.Semester).Str("Code", semester.Code).Msgf("New semester %s successfully updated in database.", semester.Semester)
+	utils.Logger.Info().Str("Semester", semester.Semester).Str("code id", code.CodeID).Msgf("New semester %s successfully updated in database.", semester.Semester)
 	return nil
 }
 
@@ -61,34 +70,200 @@ func (r *SemesterRepository) getCurrentSem() (*Semester, error) {
 	row, _ := r.db.Query(context.Background(), query)
 	semester, err := pgx.CollectExactlyOneRow(row, pgx.RowToAddrOfStructByName[Semester])
 	if err != nil {
-		utils.Logger.Error().Err(err).Msg("Error retrieving code for the current semester")
+		utils.Logger.Error().Err(err).Msg("Error retrieving the current semester")
 		return nil, err
 	}
 
-	utils.Logger.Debug().Str("code", semester.Code).Msg("Code for current semester retrieved from db.")
+	utils.Logger.Debug().Str("semester", semester.Semester).Msg("Current semester retrieved from db.")
 	return semester, nil
 }
 
-// Set new code for current sem
-func (r *SemesterRepository) RefreshCode(code string) error {
-	query := fmt.Sprintf(`UPDATE %s SET CODE=$1 WHERE IS_CURRENT;`, SEMESTER_TABLE)
+// Check if semester exists
+func (r *SemesterRepository) exists(semester string) (bool, error) {
+	query := fmt.Sprintf(`SELECT EXISTS(SELECT 1 FROM %s WHERE SEMESTER=$1);`, SEMESTER_TABLE)
+
+	var exists bool
+	if err := r.db.QueryRow(context.Background(), query, semester).Scan(&exists); err != nil {
+		utils.Logger.Error().Err(err).Str("semester", semester).Msg("Error checking if semester exists")
+		return false, err
+	}
+
+	return exists, nil
+}
+
+// Retrieve default code of semester
+func (r *SemesterRepository) getDefaultCode(semester string) (*EnrolmentCode, error) {
+	query := fmt.Sprintf(`SELECT * FROM %s WHERE SEMESTER=$1 AND IS_DEFAULT;`, ENROLMENT_CODES_TABLE)
 
-	res, err := r.db.Exec(context.Background(), query, code)
+	row, _ := r.db.Query(context.Background(), query, semester)
+	code, err := pgx.CollectExactlyOneRow(row, pgx.RowToAddrOfStructByName[EnrolmentCode])
 	if err != nil {
-		utils.Logger.Error().Err(err).Msg("Error updating code for current semester")
+		utils.Logger.Error().Err(err).Str("semester", semester).Msg("Error retrieving default code for semester")
+		return nil, err
+	}
+
+	return code, nil
+}
+
+// Replace the default code of the semester. The old default code is disabled but kept for auditing.
+func (r *SemesterRepository) RefreshCode(code EnrolmentCode) error {
+	ctx := context.Background()
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		utils.Logger.Error().Err(err).Msg("Error starting transaction")
 		return err
 	}
+	defer tx.Rollback(ctx)
+
+	query := fmt.Sprintf(`UPDATE %s SET IS_DEFAULT=FALSE, DISABLED=TRUE WHERE SEMESTER=$1 AND IS_DEFAULT;`, ENROLMENT_CODES_TABLE)
 
-	if res.RowsAffected() != 1 {
+	res, err := tx.Exec(ctx, query, code.Semester)
+	if err != nil {
+		utils.Logger.Error().Err(err).Msg("Error disabling old default code for current semester")
+		return err
+	}
+
+	if res.RowsAffected() > 1 {
 		utils.Logger.Error().Int64("Rows affected", res.RowsAffected()).Msg("Number of rows updated is not 1")
+		return pgx.ErrTooManyRows
+	}
+
+	if err := insertCode(ctx, tx, code); err != nil {
+		return err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		utils.Logger.Error().Err(err).Msg("Error committing transaction")
+		return err
+	}
+
+	utils.Logger.Info().Str("semester", code.Semester).Msg("Default code successfully updated for current semester")
+	return nil
+}
+
+// Insert a new enrolment code
+func (r *SemesterRepository) insertCode(code EnrolmentCode) error {
+	return insertCode(context.Background(), r.db, code)
+}
+
+// Common interface of pgxpool.Pool and pgx.Tx used to insert codes inside and outside of transactions
+type executor interface {
+	Exec(ctx context.Context, sql string, arguments ...any) (pgconn.CommandTag, error)
+}
 
-		if res.RowsAffected() > 1 {
-			return pgx.ErrTooManyRows
-		} else {
-			return pgx.ErrNoRows
-		}
+func insertCode(ctx context.Context, db executor, code EnrolmentCode) error {
+	query := fmt.Sprintf(`
+	INSERT INTO %s (CODE_ID, CODE, SEMESTER, ROLE, TUTORIAL_GROUP, MAX_USES, NUM_USES, EXPIRES_AT, IS_DEFAULT, DISABLED, CREATED_BY, TIME_CREATED)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12);`, ENROLMENT_CODES_TABLE)
+
+	if _, err := db.Exec(ctx, query, code.CodeID, code.Code, code.Semester, code.Role, code.TutorialGroup, code.MaxUses, code.NumUses, code.ExpiresAt, code.IsDefault, code.Disabled, code.CreatedBy, code.TimeCreated); err != nil {
+		utils.Logger.Error().Err(err).Str("semester", code.Semester).Msg("Error inserting enrolment code into database")
+		return err
 	}
 
-	utils.Logger.Info().Str("New code", code).Msg("Code successfully updated for current semester")
+	utils.Logger.Info().Str("code id", code.CodeID).Str("semester", code.Semester).Str("role", code.Role).Msg("Enrolment code created")
 	return nil
 }
+
+// Returns true if err is a violation of a unique constraint
+func isUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "23505"
+}
+
+// Retrieve all codes of a semester, newest first
+func (r *SemesterRepository) getCodes(semester string) ([]EnrolmentCode, error) {
+	query := fmt.Sprintf(`SELECT * FROM %s WHERE SEMESTER=$1 ORDER BY TIME_CREATED DESC;`, ENROLMENT_CODES_TABLE)
+
+	rows, _ := r.db.Query(context.Background(), query, semester)
+	codes, err := pgx.CollectRows(rows, pgx.RowToStructByName[EnrolmentCode])
+	if err != nil {
+		utils.Logger.Error().Err(err).Str("semester", semester).Msg("Error retrieving enrolment codes")
+		return nil, err
+	}
+
+	return codes, nil
+}
+
+// Enable or disable a code. Returns pgx.ErrNoRows if code does not exist.
+func (r *SemesterRepository) setDisabled(codeID string, disabled bool) error {
+	query := fmt.Sprintf(`UPDATE %s SET DISABLED=$1 WHERE CODE_ID=$2;`, ENROLMENT_CODES_TABLE)
+
+	res, err := r.db.Exec(context.Background(), query, disabled, codeID)
+	if err != nil {
+		utils.Logger.Error().Err(err).Str("code id", codeID).Msg("Error updating enrolment code")
+		return err
+	}
+
+	if res.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+
+	utils.Logger.Info().Str("code id", codeID).Bool("disabled", disabled).Msg("Enrolment code updated")
+	return nil
+}
+
+// Atomically claim one use of a code of the current semester. The code must not be disabled, expired or used up.
+// Returns pgx.ErrNoRows if the code cannot be redeemed.
+func (r *SemesterRepository) claim(code string) (*EnrolmentCode, error) {
+	query := fmt.Sprintf(`
+	UPDATE %s SET NUM_USES=NUM_USES+1
+	WHERE CODE=$1
+		AND NOT DISABLED
+		AND (EXPIRES_AT IS NULL OR EXPIRES_AT > NOW())
+		AND (MAX_USES IS NULL OR NUM_USES < MAX_USES)
+		AND SEMESTER=(SELECT SEMESTER FROM %s WHERE IS_CURRENT)
+	RETURNING *;`, ENROLMENT_CODES_TABLE, SEMESTER_TABLE)
+
+	row, _ := r.db.Query(context.Background(), query, code)
+	claimed, err := pgx.CollectOneRow(row, pgx.RowToAddrOfStructByName[EnrolmentCode])
+	if err != nil {
+		utils.Logger.Warn().Err(err).Msg("Enrolment code could not be claimed")
+		return nil, err
+	}
+
+	return claimed, nil
+}
+
+// Give back a use of a code which was claimed but not redeemed, e.g. because registration failed.
+func (r *SemesterRepository) release(codeID string) error {
+	query := fmt.Sprintf(`UPDATE %s SET NUM_USES=NUM_USES-1 WHERE CODE_ID=$1 AND NUM_USES > 0;`, ENROLMENT_CODES_TABLE)
+
+	if _, err := r.db.Exec(context.Background(), query, codeID); err != nil {
+		utils.Logger.Error().Err(err).Str("code id", codeID).Msg("Error releasing enrolment code")
+		return err
+	}
+
+	return nil
+}
+
+// Record that uid registered with code
+func (r *SemesterRepository) insertRedemption(codeID string, uid string) error {
+	query := fmt.Sprintf(`INSERT INTO %s (CODE_ID, UID) VALUES ($1, $2);`, REDEMPTIONS_TABLE)
+
+	if _, err := r.db.Exec(context.Background(), query, codeID, uid); err != nil {
+		utils.Logger.Error().Err(err).Str("code id", codeID).Str("uid", uid).Msg("Error recording redemption")
+		return err
+	}
+
+	return nil
+}
+
+// Retrieve all users who registered with code, newest first
+func (r *SemesterRepository) getRedemptions(codeID string) ([]Redemption, error) {
+	query := fmt.Sprintf(`
+	SELECT r.CODE_ID, r.UID, COALESCE(u.NAME, '') AS NAME, u.EMAIL, r.TIME_REDEEMED
+	FROM %s r JOIN USERS u ON r.UID = u.UID
+	WHERE r.CODE_ID=$1
+	ORDER BY r.TIME_REDEEMED DESC;`, REDEMPTIONS_TABLE)
+
+	rows, _ := r.db.Query(context.Background(), query, codeID)
+	redemptions, err := pgx.CollectRows(rows, pgx.RowToStructByName[Redemption])
+	if err != nil {
+		utils.Logger.Error().Err(err).Str("code id", codeID).Msg("Error retrieving redemptions")
+		return nil, err
+	}
+
+	return redemptions, nil
+}
@@ -1,13 +1,15 @@
 package semester
 
 import (
+	"time"
+
 	"github.com/ntu-onemdp/onemdp-backend/internal/utils"
 )
 
 type SemesterService struct {
 	semesterRepo *SemesterRepository
 
-	// Cache the current semester and code for easy retrieval.
+	// Cache the current semester and default code for easy retrieval.
 	currentSemester *string
 	code            *string
 }
@@ -21,9 +23,15 @@ func NewSemesterService(semRepo *SemesterRepository) *SemesterService {
 		return nil
 	}
 
+	code, err := semRepo.getDefaultCode(semester.Semester)
+	if err != nil {
+		utils.Logger.Error().Err(err).Msg("Error initializing semester service")
+		return nil
+	}
+
 	return &SemesterService{
 		semesterRepo:    semRepo,
-		code:            &semester.Code,
+		code:            &code.Code,
 		currentSemester: &semester.Semester,
 	}
 }
@@ -33,7 +41,12 @@ func (s *SemesterService) GetCurrentSem() *string {
 	return s.currentSemester
 }
 
-// Get the enrolment code for the current sem
+// Check if semester has been created
+func (s *SemesterService) Exists(semester string) (bool, error) {
+	return s.semesterRepo.exists(semester)
+}
+
+// Get the default enrolment code for the current sem
 func (s *SemesterService) GetCode() string {
 	return *s.code
 }
@@ -41,37 +54,97 @@ func (s *SemesterService) GetCode() string {
 // Insert a new semester into the system
 func (s *SemesterService) NewSemester(sem string) error {
 	semester := NewSemester(sem)
+	code := newDefaultCode(sem)
 
 	// Insert new semester into the repo
-	if err := s.semesterRepo.insert(*semester); err != nil {
+	if err := s.semesterRepo.insert(*semester, *code); err != nil {
 		utils.Logger.Error().Err(err).Msg("Error inserting new semester into repo")
 		return err
 	}
 
 	// Update cache
-	s.code = &semester.Code
+	s.code = &code.Code
 	s.currentSemester = &semester.Semester
-	utils.Logger.Trace().Str("current semester", *s.currentSemester).Str("enrolment code", *s.code).Msg("Semester service cache updated successfully")
+	utils.Logger.Trace().Str("current semester", *s.currentSemester).Msg("Semester service cache updated successfully")
 
 	return nil
 }
 
-// Refresh code for current semester
+// Refresh default code for current semester
 // Returns new code if successful
 func (s *SemesterService) RefreshCode() (string, error) {
 	// Generate new code
-	code := generateEnrolmentCode()
+	code := newDefaultCode(*s.currentSemester)
 
 	// Update repo with new enrolment code
-	if err := s.semesterRepo.RefreshCode(code); err != nil {
+	if err := s.semesterRepo.RefreshCode(*code); err != nil {
 		utils.Logger.Error().Err(err).Msg("Error updating database with new enrolment code")
 		return "", err
 	}
 
 	// Update cache
-	s.code = &code
-	utils.Logger.Trace().Str("enrolment code", code).Msg("Enrolment code updated in cache")
-	utils.Logger.Info().Str("enrolment code", code).Msg("Enrolment code updated successfully.")
+	s.code = &code.Code
+	utils.Logger.Trace().Str("code id", code.CodeID).Msg("Enrolment code updated in cache")
+	utils.Logger.Info().Str("code id", code.CodeID).Msg("Enrolment code updated successfully.")
+
+	return code.Code, nil
+}
+
+// Create an additional enrolment code for the current semester. A new code is generated if the code collides with an
+// existing one.
+func (s *SemesterService) CreateCode(role string, tutorialGroup *string, maxUses *int, expiresAt *time.Time, createdBy string) (*EnrolmentCode, error) {
+	const MAX_ATTEMPTS = 3
+
+	var err error
+	for attempt := 1; attempt <= MAX_ATTEMPTS; attempt++ {
+		code := NewEnrolmentCode(*s.currentSemester, role, tutorialGroup, maxUses, expiresAt, &createdBy)
+
+		err = s.semesterRepo.insertCode(*code)
+		if err == nil {
+			return code, nil
+		}
+		if !isUniqueViolation(err) {
+			return nil, err
+		}
+
+		utils.Logger.Warn().Int("attempt", attempt).Msg("Generated enrolment code already exists, retrying with a new code")
+	}
+
+	return nil, err
+}
+
+// List all enrolment codes of a semester. Defaults to the current semester if semester is empty.
+func (s *SemesterService) GetCodes(semester string) ([]EnrolmentCode, error) {
+	if semester == "" {
+		semester = *s.currentSemester
+	}
+
+	return s.semesterRepo.getCodes(semester)
+}
+
+// Disable or re-enable an enrolment code
+func (s *SemesterService) SetCodeDisabled(codeID string, disabled bool) error {
+	return s.semesterRepo.setDisabled(codeID, disabled)
+}
+
+// List users who registered with a code
+func (s *SemesterService) GetRedemptions(codeID string) ([]Redemption, error) {
+	return s.semesterRepo.getRedemptions(codeID)
+}
+
+// Claim one use of an enrolment code of the current semester.
+// The caller must either call Redeem once the user is registered, or Release if registration fails.
+// Returns pgx.ErrNoRows if the code is invalid, disabled, expired or used up.
+func (s *SemesterService) Claim(code string) (*EnrolmentCode, error) {
+	return s.semesterRepo.claim(code)
+}
+
+// Record that uid has registered with a claimed code
+func (s *SemesterService) Redeem(code *EnrolmentCode, uid string) error {
+	return s.semesterRepo.insertRedemption(code.CodeID, uid)
+}
 
-	return code, nil
+// Give back a claimed use of a code
+func (s *SemesterService) Release(code *EnrolmentCode) error {
+	return s.semesterRepo.release(code.CodeID)
 }
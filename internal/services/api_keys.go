@@ -0,0 +1,55 @@
+package services
+
+import (
+	"errors"
+	"time"
+
+	"github.com/ntu-onemdp/onemdp-backend/internal/models"
+	"github.com/ntu-onemdp/onemdp-backend/internal/repositories"
+	"github.com/ntu-onemdp/onemdp-backend/internal/utils"
+)
+
+type ApiKeyService struct {
+	repo *repositories.ApiKeysRepository
+}
+
+var ApiKeys *ApiKeyService
+
+// Create a new api key owned by ownerUid. Returns the key and its plaintext value.
+func (s *ApiKeyService) Create(name string, ownerUid string, maxRole string, scopes []string, expiresAt *time.Time) (*models.ApiKey, string, error) {
+	if expiresAt != nil && expiresAt.Before(time.Now()) {
+		return nil, "", errors.New("expiry must be in the future")
+	}
+
+	key, plaintext := models.NewApiKey(name, ownerUid, maxRole, scopes, expiresAt)
+
+	if err := s.repo.Insert(key); err != nil {
+		return nil, "", err
+	}
+
+	return key, plaintext, nil
+}
+
+// Look up an active api key by its plaintext value and record its use.
+// Returns an error if the key does not exist, has expired or has been revoked.
+func (s *ApiKeyService) Authenticate(plaintext string) (*models.ApiKey, error) {
+	key, err := s.repo.GetActiveByHash(utils.HashToken(plaintext))
+	if err != nil {
+		return nil, err
+	}
+
+	// Failing to record the use should not reject the request
+	_ = s.repo.UpdateLastUsed(key.KeyID)
+
+	return key, nil
+}
+
+// List all api keys, including revoked and expired keys.
+func (s *ApiKeyService) GetAll() ([]models.ApiKey, error) {
+	return s.repo.GetAll()
+}
+
+// Revoke an api key. Takes effect on the next request made with the key.
+func (s *ApiKeyService) Revoke(keyID string) error {
+	return s.repo.Revoke(keyID)
+}
@@ -1,6 +1,9 @@
 package services
 
 import (
+	"strings"
+
+	"github.com/jackc/pgx/v5"
 	"github.com/ntu-onemdp/onemdp-backend/internal/models"
 	"github.com/ntu-onemdp/onemdp-backend/internal/repositories"
 	"github.com/ntu-onemdp/onemdp-backend/internal/utils"
@@ -33,6 +36,8 @@ func (s *ArticleService) CreateNewArticle(author string, title string, content s
 		return "", err
 	}
 
+	go Mentions.Record(articleMentionSource(article.ArticleID, article.Author, article.Title), article.Content)
+
 	return article.ArticleID, nil
 }
 
@@ -50,8 +55,8 @@ func (s *ArticleService) GetMetadata() (*models.ContentMetadata, error) {
 	return s.articleRepo.GetMetadata()
 }
 
-// Retrieve article and all related comments
-func (s *ArticleService) GetArticle(articleID string, uid string) (*models.Article, []models.Comment, error) {
+// Retrieve article and its comments, with replies nested up to commentDepth levels
+func (s *ArticleService) GetArticle(articleID string, uid string, commentDepth int) (*models.Article, []models.Comment, error) {
 	// Retrieve article
 	article, err := s.articleRepo.GetByID(articleID, uid)
 	if err != nil {
@@ -60,7 +65,7 @@ func (s *ArticleService) GetArticle(articleID string, uid string) (*models.Artic
 	}
 
 	// Retrive comments
-	comments, err := s.commentsRepo.GetCommentsByArticleID(articleID, uid)
+	comments, err := Comments.GetTree(articleID, uid, commentDepth)
 	if err != nil {
 		utils.Logger.Trace().Err(err).Msg("Error retrieving comments")
 		return article, nil, err
@@ -75,27 +80,118 @@ func (s *ArticleService) ArticleExists(articleID string) bool {
 }
 
 // Delete article and all associated comments
-func (s *ArticleService) DeleteArticle(articleID string, uid string) error {
-	// Check if role is admin or staff
-	hasStaffPermission, err := Users.HasStaffPermission(uid)
-	if err != nil {
-		utils.Logger.Error().Err(err).Msg("Error checkecing for staff permission")
-		return err
-	}
-
-	// Not staff/admin: check if user is author of the article
-	if !hasStaffPermission {
+func (s *ArticleService) DeleteArticle(articleID string, p *models.Principal) error {
+	// Cannot hide content: check if user is author of the article
+	if !p.Can(models.PermContentHide) {
 		// Get uid of article author
 		author, err := s.articleRepo.GetAuthor(articleID)
 		if err != nil {
 			utils.Logger.Error().Err(err).Msg("Error getting article author")
 			return err
 		}
-		if author != uid {
-			utils.Logger.Warn().Msgf("User %s is not author of article %s", uid, articleID)
+		if author != p.Uid {
+			utils.Logger.Warn().Msgf("User %s is not author of article %s", p.Uid, articleID)
 			return utils.NewErrUnauthorized()
 		}
 	}
 
-	return s.articleRepo.Delete(articleID)
+	return s.articleRepo.Delete(articleID, p.Uid)
+}
+
+// Update article. Only the title and the content can be updated, and the previous version is kept as a revision.
+// Article can only be updated by the author of the article or by users who can moderate content
+func (s *ArticleService) UpdateArticle(articleID string, title string, content string, p *models.Principal) error {
+	author, err := s.articleRepo.GetAuthor(articleID)
+	if err != nil {
+		utils.Logger.Error().Err(err).Msg("Error getting article author")
+		return err
+	}
+	if !p.Can(models.PermContentModerate) && author != p.Uid {
+		utils.Logger.Warn().Msgf("User %s is not author of article %s", p.Uid, articleID)
+		return utils.NewErrUnauthorized()
+	}
+
+	sanitized := utils.SanitizeContent(content)
+	if err := s.articleRepo.Update(articleID, title, sanitized, models.GetPreview(content), p.Uid); err != nil {
+		return err
+	}
+
+	// Mentions are made by the author of the article, not by the moderator editing it
+	if author != "" {
+		go Mentions.Record(articleMentionSource(articleID, author, title), sanitized)
+	}
+
+	return nil
+}
+
+// Source of the mentions in an article
+func articleMentionSource(articleID string, author string, title string) models.MentionSource {
+	return models.MentionSource{
+		ContentID:   articleID,
+		ContentType: models.TrashArticle,
+		Author:      author,
+		LinkID:      articleID,
+		Title:       title,
+	}
+}
+
+// Retrieve all previous versions of an available article, newest first
+func (s *ArticleService) GetRevisions(articleID string) ([]models.ArticleRevision, error) {
+	if !s.ArticleExists(articleID) {
+		return nil, pgx.ErrNoRows
+	}
+
+	return s.articleRepo.GetRevisions(articleID)
+}
+
+// Compare two versions of an article. Revision 0 is the current version.
+func (s *ArticleService) DiffRevisions(articleID string, from int, to int) (*models.RevisionDiff, error) {
+	if !s.ArticleExists(articleID) {
+		return nil, pgx.ErrNoRows
+	}
+
+	fromRev, err := s.getVersion(articleID, from)
+	if err != nil {
+		return nil, err
+	}
+	toRev, err := s.getVersion(articleID, to)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.RevisionDiff{
+		From:      from,
+		To:        to,
+		FromTitle: fromRev.Title,
+		ToTitle:   toRev.Title,
+		Lines:     utils.LineDiff(splitTags(fromRev.Content), splitTags(toRev.Content)),
+	}, nil
+}
+
+// Restore a previous version of an article. The version being replaced is kept as a new revision, so rollbacks can
+// themselves be undone. Only users who can moderate content may roll back articles.
+func (s *ArticleService) RollbackArticle(articleID string, revision int, p *models.Principal) error {
+	if !p.Can(models.PermContentModerate) {
+		return utils.NewErrUnauthorized()
+	}
+
+	rev, err := s.articleRepo.GetRevision(articleID, revision)
+	if err != nil {
+		return err
+	}
+
+	return s.articleRepo.Update(articleID, rev.Title, rev.Content, models.GetPreview(rev.Content), p.Uid)
+}
+
+func (s *ArticleService) getVersion(articleID string, revision int) (*models.ArticleRevision, error) {
+	if revision == 0 {
+		return s.articleRepo.GetCurrentVersion(articleID)
+	}
+	return s.articleRepo.GetRevision(articleID, revision)
+}
+
+// Sanitized content is usually a single line of html. Put each block element on its own line so that it can be diffed
+// line by line.
+func splitTags(content string) string {
+	return strings.ReplaceAll(content, "><", ">\n<")
 }
@@ -0,0 +1,32 @@
+package services
+
+import (
+	"github.com/ntu-onemdp/onemdp-backend/internal/models"
+	"github.com/ntu-onemdp/onemdp-backend/internal/repositories"
+)
+
+type AuthFailureService struct {
+	repo *repositories.AuthFailuresRepository
+}
+
+var AuthFailures *AuthFailureService
+
+// Record a failed login or registration attempt. Email is optional.
+func (s *AuthFailureService) Record(endpoint string, ip string, email string, reason string, userAgent string) error {
+	failure := &models.AuthFailure{
+		Endpoint:  endpoint,
+		Ip:        ip,
+		Reason:    reason,
+		UserAgent: userAgent,
+	}
+	if email != "" {
+		failure.Email = &email
+	}
+
+	return s.repo.Insert(failure)
+}
+
+// Retrieve most recent failed attempts, optionally filtered by ip and email.
+func (s *AuthFailureService) GetRecent(ip string, email string, limit int) ([]models.AuthFailure, error) {
+	return s.repo.GetRecent(ip, email, limit)
+}
@@ -31,10 +31,6 @@ func NewGCSFileService() *GCSFileService {
 
 var GCSFileServiceInstance *GCSFileService
 
-func init() {
-	GCSFileServiceInstance = NewGCSFileService()
-}
-
 // Upload file to pdfstore in GCS
 func (s *GCSFileService) Upload(file *multipart.FileHeader, filename string) error {
 	handler := s.bucket.Object(s.dir + filename)
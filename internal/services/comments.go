@@ -1,7 +1,11 @@
 package services
 
 import (
+	"errors"
+
+	c "github.com/ntu-onemdp/onemdp-backend/config"
 	"github.com/ntu-onemdp/onemdp-backend/internal/models"
+	"github.com/ntu-onemdp/onemdp-backend/internal/notification"
 	"github.com/ntu-onemdp/onemdp-backend/internal/repositories"
 	"github.com/ntu-onemdp/onemdp-backend/internal/utils"
 )
@@ -21,34 +25,120 @@ func NewCommentService(repo *repositories.CommentsRepository) *CommentService {
 	}
 }
 
-// Create a new comment and insert into the repository.
+// Create a new comment and insert into the repository. replyTo is the parent comment, which must be an available
+// comment on the same article, or nil for a top level comment.
 // Returns comment id on success
-func (s *CommentService) Create(authorUID string, articleID string, content string) (string, error) {
-	comment := s.commentFactory.New(authorUID, articleID, content)
+func (s *CommentService) Create(authorUID string, articleID string, replyTo *string, content string) (string, error) {
+	if replyTo != nil {
+		parent, err := s.repo.Get(*replyTo)
+		if err != nil || !parent.IsAvailable || parent.ArticleID != articleID {
+			return "", errors.New("comment being replied to does not exist on this article")
+		}
+	}
+
+	comment := s.commentFactory.New(authorUID, articleID, replyTo, content)
 
 	if err := s.repo.Create(comment); err != nil {
 		return "", err
 	}
 
+	go s.recordMentions(comment)
+	go s.notifyComment(comment)
+
 	return comment.CommentID, nil
 }
 
-// Check if comment exists and is available
-func (s *CommentService) CommentExists(commentID string) bool {
-	return s.repo.IsAvailable(commentID)
+// Retrieve comments of an article as a tree, nested up to depth levels. Deleted comments are kept as tombstones if
+// they have replies which are not deleted.
+func (s *CommentService) GetTree(articleID string, uid string, depth int) ([]models.Comment, error) {
+	comments, err := s.repo.GetCommentsByArticleID(articleID, uid)
+	if err != nil {
+		return nil, err
+	}
+
+	return buildCommentTree(comments, nil, depth), nil
 }
 
-// Delete comment if uid matches author
-func (s *CommentService) Delete(commentID string, uid string) error {
-	// Check if role is admin or staff
-	hasStaffPermission, err := Users.HasStaffPermission(uid)
+// Retrieve the replies to a comment as a tree, nested up to depth levels. The comment itself may have been deleted.
+func (s *CommentService) GetReplies(commentID string, uid string, depth int) ([]models.Comment, error) {
+	comment, err := s.repo.Get(commentID)
 	if err != nil {
-		utils.Logger.Error().Err(err).Msg("Error checkecing for staff permission")
+		return nil, err
+	}
+
+	comments, err := s.repo.GetCommentsByArticleID(comment.ArticleID, uid)
+	if err != nil {
+		return nil, err
+	}
+
+	return buildCommentTree(comments, &commentID, depth), nil
+}
+
+// Update content of comment.
+// Comment can only be updated by the author of the comment or by users who can moderate content
+func (s *CommentService) Update(commentID string, content string, p *models.Principal) error {
+	comment, err := s.repo.Get(commentID)
+	if err != nil {
+		utils.Logger.Error().Err(err).Msg("Error getting comment")
 		return err
 	}
 
-	// Not staff/admin: check if user is author of the article
-	if !hasStaffPermission {
+	if !p.Can(models.PermContentModerate) && p.Uid != comment.AuthorUID {
+		utils.Logger.Warn().Msgf("User %s is not author of comment %s", p.Uid, commentID)
+		return utils.NewErrUnauthorized()
+	}
+
+	comment.Content = utils.SanitizeContent(content)
+	if err := s.repo.Update(commentID, comment.Content); err != nil {
+		return err
+	}
+
+	// Mentions are made by the author of the comment, not by the moderator editing it
+	go s.recordMentions(comment)
+
+	return nil
+}
+
+// Notify the author of the article and the author of the comment being replied to of a new comment
+func (s *CommentService) notifyComment(comment *models.DbComment) {
+	articleAuthor, err := repositories.Articles.GetAuthor(comment.ArticleID)
+	if err != nil {
+		return
+	}
+	title, _ := repositories.Articles.GetTitle(comment.ArticleID)
+
+	notify(articleAuthor, notification.TypeComment, comment.AuthorUID, false, comment.ArticleID, title)
+
+	if comment.ReplyTo != nil {
+		parent, err := s.repo.Get(*comment.ReplyTo)
+		if err == nil && parent.AuthorUID != articleAuthor {
+			notify(parent.AuthorUID, notification.TypeReply, comment.AuthorUID, false, comment.ArticleID, title)
+		}
+	}
+}
+
+// Record users mentioned in comment. Mentioned users are sent to the article, titled by the article's title.
+func (s *CommentService) recordMentions(comment *models.DbComment) {
+	title, _ := repositories.Articles.GetTitle(comment.ArticleID)
+
+	Mentions.Record(models.MentionSource{
+		ContentID:   comment.CommentID,
+		ContentType: models.TrashComment,
+		Author:      comment.AuthorUID,
+		LinkID:      comment.ArticleID,
+		Title:       title,
+	}, comment.Content)
+}
+
+// Check if comment exists and is available
+func (s *CommentService) CommentExists(commentID string) bool {
+	return s.repo.IsAvailable(commentID)
+}
+
+// Delete comment if uid matches author
+func (s *CommentService) Delete(commentID string, p *models.Principal) error {
+	// Cannot hide content: check if user is author of the comment
+	if !p.Can(models.PermContentHide) {
 		// Get uid of comment's author
 		author, err := s.repo.GetAuthor(commentID)
 		if err != nil {
@@ -57,11 +147,81 @@ func (s *CommentService) Delete(commentID string, uid string) error {
 		}
 
 		// uid of user does not match author
-		if uid != author {
-			utils.Logger.Warn().Msgf("User %s is not author of comment %s", uid, commentID)
+		if p.Uid != author {
+			utils.Logger.Warn().Msgf("User %s is not author of comment %s", p.Uid, commentID)
 			return utils.NewErrUnauthorized()
 		}
 	}
 
-	return s.repo.Delete(commentID)
+	return s.repo.Delete(commentID, p.Uid)
+}
+
+// Nest comments under their parents, returning the replies to root (top level comments if root is nil) in the order
+// given. Replies are included up to depth levels below root, and only counted beyond that. Deleted comments without
+// any replies that are not deleted are left out.
+func buildCommentTree(comments []models.Comment, root *string, depth int) []models.Comment {
+	depth = min(max(depth, 1), c.MAX_COMMENT_DEPTH)
+
+	ids := make(map[string]bool, len(comments))
+	for _, comment := range comments {
+		ids[comment.CommentID] = true
+	}
+
+	// Comments whose parent is missing are shown at the top level
+	children := make(map[string][]int)
+	for i, comment := range comments {
+		parent := ""
+		if comment.ReplyTo != nil && ids[*comment.ReplyTo] {
+			parent = *comment.ReplyTo
+		}
+		children[parent] = append(children[parent], i)
+	}
+
+	// Whether a comment or any of its replies is not deleted
+	visible := make(map[string]bool, len(comments))
+	var markVisible func(i int) bool
+	markVisible = func(i int) bool {
+		comment := comments[i]
+		v := comment.IsAvailable
+		for _, child := range children[comment.CommentID] {
+			if markVisible(child) {
+				v = true
+			}
+		}
+		visible[comment.CommentID] = v
+		return v
+	}
+	for _, i := range children[""] {
+		markVisible(i)
+	}
+
+	var build func(parent string, level int) []models.Comment
+	build = func(parent string, level int) []models.Comment {
+		tree := []models.Comment{}
+		for _, i := range children[parent] {
+			comment := comments[i]
+			if !visible[comment.CommentID] {
+				continue
+			}
+
+			comment.Replies = []models.Comment{}
+			if level < depth {
+				comment.Replies = build(comment.CommentID, level+1)
+				comment.NumReplies = len(comment.Replies)
+			} else {
+				for _, child := range children[comment.CommentID] {
+					if visible[comments[child].CommentID] {
+						comment.NumReplies++
+					}
+				}
+			}
+			tree = append(tree, comment)
+		}
+		return tree
+	}
+
+	if root == nil {
+		return build("", 1)
+	}
+	return build(*root, 1)
 }
@@ -0,0 +1,179 @@
+package services
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	c "github.com/ntu-onemdp/onemdp-backend/config"
+	"github.com/ntu-onemdp/onemdp-backend/internal/models"
+	"github.com/ntu-onemdp/onemdp-backend/internal/repositories"
+	"github.com/ntu-onemdp/onemdp-backend/internal/utils"
+)
+
+type DraftService struct {
+	repo *repositories.DraftsRepository
+}
+
+var Drafts *DraftService
+
+// Create a new draft. Post drafts need the thread they belong to and comment drafts need their article.
+func (s *DraftService) Create(author string, targetType string, targetID *string, replyTo *string, title string, content string, isAnon bool) (*models.Draft, error) {
+	switch targetType {
+	case models.DraftThread, models.DraftArticle:
+		targetID, replyTo = nil, nil
+	case models.DraftPost:
+		if targetID == nil || !Threads.ThreadExists(*targetID) {
+			return nil, errors.New("thread of post draft does not exist")
+		}
+		if replyTo != nil && !Posts.PostExists(*replyTo) {
+			return nil, errors.New("post being replied to does not exist")
+		}
+	case models.DraftComment:
+		if targetID == nil || !Articles.ArticleExists(*targetID) {
+			return nil, errors.New("article of comment draft does not exist")
+		}
+		if replyTo != nil && !Comments.CommentExists(*replyTo) {
+			return nil, errors.New("comment being replied to does not exist")
+		}
+	default:
+		return nil, errors.New("invalid draft type")
+	}
+
+	draft := models.NewDraft(author, targetType, targetID, replyTo, title, content, isAnon)
+	if err := s.repo.Insert(draft); err != nil {
+		return nil, err
+	}
+
+	return draft, nil
+}
+
+// Retrieve a draft of author
+func (s *DraftService) Get(draftID string, author string) (*models.Draft, error) {
+	return s.repo.Get(draftID, author)
+}
+
+// Retrieve all drafts of author, optionally filtered by target type
+func (s *DraftService) GetAll(author string, targetType string) ([]models.Draft, error) {
+	if targetType != "" && !models.IsValidDraftType(targetType) {
+		return nil, errors.New("invalid draft type")
+	}
+
+	return s.repo.GetByAuthor(author, targetType)
+}
+
+// Autosave a draft. lastEdited is when the client made the edit, so that when the same draft is open in several tabs
+// the latest edit wins regardless of the order the saves arrive in. It is capped at the current time.
+//
+// Returns the saved draft and true, or the stored draft and false if it has already been saved with a later edit.
+func (s *DraftService) Save(draftID string, author string, title string, content string, isAnon bool, lastEdited *time.Time) (*models.Draft, bool, error) {
+	now := time.Now()
+	if lastEdited == nil || lastEdited.After(now) {
+		lastEdited = &now
+	}
+
+	draft, err := s.repo.Update(draftID, author, title, utils.SanitizeContent(content), isAnon, *lastEdited)
+	if err == nil {
+		return draft, true, nil
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return nil, false, err
+	}
+
+	// Nothing was updated, either because the draft does not exist or because the save is stale
+	current, getErr := s.repo.Get(draftID, author)
+	if getErr != nil {
+		return nil, false, getErr
+	}
+
+	utils.Logger.Debug().Str("draft id", draftID).Time("last edited", current.LastEdited).Msg("Stale draft save ignored")
+	return current, false, nil
+}
+
+// Delete a draft of author
+func (s *DraftService) Delete(draftID string, author string) error {
+	return s.repo.Delete(draftID, author)
+}
+
+// Publish a draft through the service that creates its type of content. The draft is deleted before the content is
+// created, so that publishing the same draft twice at once creates the content only once, and put back if the content
+// cannot be created. Returns the id of the created thread, post, article or comment.
+func (s *DraftService) Publish(draftID string, author string) (string, error) {
+	draft, err := s.repo.Take(draftID, author)
+	if err != nil {
+		return "", err
+	}
+
+	id, err := s.publish(draft)
+	if err != nil {
+		if insertErr := s.repo.Insert(draft); insertErr != nil {
+			utils.Logger.Error().Err(insertErr).Str("draft id", draftID).Msg("Draft could not be published or put back")
+		}
+		return "", err
+	}
+
+	utils.Logger.Info().Str("draft id", draftID).Str("content id", id).Msgf("Draft published as %s", draft.TargetType)
+	return id, nil
+}
+
+// Create the content of a draft which has been taken from the repository
+func (s *DraftService) publish(draft *models.Draft) (string, error) {
+	author := draft.Author
+
+	if strings.TrimSpace(draft.Content) == "" {
+		return "", errors.New("draft has no content")
+	}
+
+	var id string
+	var err error
+	switch draft.TargetType {
+	case models.DraftThread:
+		if strings.TrimSpace(draft.Title) == "" {
+			return "", errors.New("draft has no title")
+		}
+		id, err = Threads.CreateNewThread(author, draft.Title, draft.Content, draft.IsAnon, nil)
+	case models.DraftPost:
+		if draft.TargetID == nil || !Threads.ThreadExists(*draft.TargetID) {
+			return "", errors.New("thread of post draft no longer exists")
+		}
+		id, err = Posts.CreateNewPost(author, draft.ReplyTo, *draft.TargetID, draft.Title, draft.Content, draft.IsAnon)
+	case models.DraftArticle:
+		if strings.TrimSpace(draft.Title) == "" {
+			return "", errors.New("draft has no title")
+		}
+		id, err = Articles.CreateNewArticle(author, draft.Title, draft.Content)
+	case models.DraftComment:
+		if draft.TargetID == nil || !Articles.ArticleExists(*draft.TargetID) {
+			return "", errors.New("article of comment draft no longer exists")
+		}
+		id, err = Comments.Create(author, *draft.TargetID, draft.ReplyTo, draft.Content)
+	default:
+		return "", errors.New("invalid draft type")
+	}
+	if err != nil {
+		return "", err
+	}
+
+	return id, nil
+}
+
+// Delete drafts that have not been edited for DRAFT_RETENTION. Returns number of drafts deleted.
+func (s *DraftService) DeleteOld() (int64, error) {
+	return s.repo.DeleteOlderThan(time.Now().Add(-c.DRAFT_RETENTION))
+}
+
+// Delete old drafts now and every DRAFT_CLEANUP_INTERVAL in the background.
+func (s *DraftService) StartCleanup() {
+	go func() {
+		ticker := time.NewTicker(c.DRAFT_CLEANUP_INTERVAL)
+		defer ticker.Stop()
+
+		for {
+			if n, err := s.DeleteOld(); err == nil && n > 0 {
+				utils.Logger.Info().Int64("deleted", n).Msg("Old drafts deleted")
+			}
+			<-ticker.C
+		}
+	}()
+}
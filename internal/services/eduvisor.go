@@ -39,7 +39,7 @@ func NewEduvisorService() *EduvisorService {
 		}
 
 		// Create eduvisor user
-		eduvisor = models.CreateSpecialUser(uid.String(), constants.EDUVISOR_NAME, constants.EDUVISOR_EMAIL, models.Bot.String())
+		eduvisor = models.CreateSpecialUser(uid.String(), constants.EDUVISOR_NAME, constants.EDUVISOR_EMAIL, models.RoleBot)
 
 		// Insert into users table directly. (Do not use services as this is not a normal student user.)
 		if err = repositories.Users.RegisterUser(eduvisor); err != nil {
@@ -149,7 +149,7 @@ func (s *EduvisorService) SendThread(threadID string) {
 	}
 
 	title := "Eduvisor response"
-	if err = Posts.CreateNewPost(Eduvisor.EduvisorModel.Uid, nil, posts[0].ThreadId, title, response.Response, false); err != nil {
+	if _, err = Posts.CreateNewPost(Eduvisor.EduvisorModel.Uid, nil, posts[0].ThreadId, title, response.Response, false); err != nil {
 		utils.Logger.Error().Err(err).Msg("Error inserting eduvisor's response to posts db")
 		return
 	}
@@ -0,0 +1,95 @@
+package services
+
+import (
+	"errors"
+	"time"
+
+	constants "github.com/ntu-onemdp/onemdp-backend/config"
+	"github.com/ntu-onemdp/onemdp-backend/internal/models"
+	"github.com/ntu-onemdp/onemdp-backend/internal/repositories"
+	"github.com/ntu-onemdp/onemdp-backend/internal/utils"
+)
+
+type ImpersonationService struct {
+	repo *repositories.ImpersonationAuditRepository
+}
+
+var Impersonation *ImpersonationService
+
+// Issue a short-lived token letting the admin in p act as target. The token is read-only unless allowWrites is set.
+// Admins can only impersonate active users whose permissions they also hold, and not users who can manage users, so
+// that impersonation never grants more access than the admin already has. Returns the token and its expiry.
+func (s *ImpersonationService) Start(p *models.Principal, target string, allowWrites bool, reason string, ip string, path string) (string, time.Time, error) {
+	if p.Method != models.AuthJwt || p.IsImpersonated() {
+		return "", time.Time{}, errors.New("impersonation must be started from your own login session")
+	}
+
+	if target == p.Uid {
+		return "", time.Time{}, errors.New("you cannot impersonate yourself")
+	}
+
+	status, err := Users.GetAccountStatus(target)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	if status.Status != models.StatusActive {
+		return "", time.Time{}, errors.New("only active users can be impersonated")
+	}
+
+	targetRole, err := Users.GetRole(target)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	role, err := Roles.HeldBy(p, targetRole)
+	if errors.Is(err, ErrRoleNotHeld) {
+		return "", time.Time{}, errors.New("user has permissions you do not have")
+	}
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	if role.Can(models.PermUsersManage) {
+		return "", time.Time{}, errors.New("users who can manage users cannot be impersonated")
+	}
+
+	expiresAt := time.Now().Add(constants.IMPERSONATION_TOKEN_TTL)
+	claim := models.NewImpersonationClaim(target, p.Uid, p.SessionID, !allowWrites, expiresAt)
+	token, err := JwtHandler.GenerateJwt(claim)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	// Token must not be handed out unless its issuing is on record
+	if err := s.repo.Insert(&models.ImpersonationAudit{
+		ImpersonatorUid: p.Uid,
+		TargetUid:       target,
+		SessionID:       p.SessionID,
+		Method:          models.IMPERSONATION_START,
+		Path:            path,
+		Status:          0,
+		Ip:              ip,
+		Reason:          &reason,
+	}); err != nil {
+		return "", time.Time{}, err
+	}
+
+	utils.Logger.Warn().Str("impersonator", p.Uid).Str("target", target).Bool("read only", !allowWrites).Msgf("%s started impersonating %s", p.Uid, target)
+	return token, expiresAt, nil
+}
+
+// Record a request made with an impersonation token
+func (s *ImpersonationService) Record(p *models.Principal, method string, path string, status int, ip string) error {
+	return s.repo.Insert(&models.ImpersonationAudit{
+		ImpersonatorUid: p.ImpersonatorUid,
+		TargetUid:       p.Uid,
+		SessionID:       p.SessionID,
+		Method:          method,
+		Path:            path,
+		Status:          status,
+		Ip:              ip,
+	})
+}
+
+// Retrieve most recent audit entries, optionally filtered by impersonator and target uid.
+func (s *ImpersonationService) GetRecent(impersonator string, target string, limit int) ([]models.ImpersonationAudit, error) {
+	return s.repo.GetRecent(impersonator, target, limit)
+}
@@ -5,6 +5,7 @@ import (
 )
 
 func Init() {
+	GCSFileServiceInstance = NewGCSFileService()
 	Threads = NewThreadService(repositories.Threads, repositories.Posts, repositories.Likes)
 	Posts = NewPostService(repositories.Posts)
 	Likes = &LikeService{repositories.Likes}
@@ -14,4 +15,14 @@ func Init() {
 	Articles = NewArticleService(repositories.Articles, repositories.Comments)
 	Comments = NewCommentService(repositories.Comments)
 	Files = NewFileService(repositories.Files)
+	Sessions = &SessionService{repositories.Sessions}
+	ApiKeys = &ApiKeyService{repositories.ApiKeys}
+	AuthFailures = &AuthFailureService{repositories.AuthFailures}
+	Roles = NewRoleService(repositories.Roles)
+	Impersonation = &ImpersonationService{repositories.ImpersonationAudit}
+	Drafts = &DraftService{repositories.Drafts}
+	Trash = &TrashService{repositories.Trash}
+	Tags = &TagService{repositories.Tags}
+	Polls = &PollService{repositories.Polls}
+	Mentions = &MentionService{repositories.Mentions}
 }
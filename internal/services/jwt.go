@@ -1,26 +1,48 @@
 package services
 
 import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
 	"fmt"
-	"net/http"
 	"os"
+	"path/filepath"
+	"slices"
+	"strings"
 	"time"
 
-	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt/v5"
+	constants "github.com/ntu-onemdp/onemdp-backend/config"
 	"github.com/ntu-onemdp/onemdp-backend/internal/models"
 	"github.com/ntu-onemdp/onemdp-backend/internal/utils"
 )
 
-// JWT object storing secret key
+// A key used to sign or verify tokens. Private is nil for keys that can only verify (retired keys).
+type jwtKey struct {
+	kid     string
+	method  jwt.SigningMethod
+	private crypto.Signer
+	public  crypto.PublicKey
+}
+
+// JWT object storing the signing key and all keys accepted for verification
 type Jwt struct {
-	secretKey []byte
+	signingKey       *jwtKey
+	verificationKeys map[string]*jwtKey // Keyed by kid
 }
 
 // Store global JWT handler instance
 var JwtHandler *Jwt
 
-// Load secret key from file. Panics if secret key cannot be found.
+// Load signing and verification keys from the key directory.
+//
+// Every file in the directory is a PEM encoded RSA or Ed25519 key, and its filename (without extension) is used as the kid.
+// Private keys (<kid>.pem) can sign and verify. Public keys (<kid>.pub.pem) can only verify, which is used to keep retired
+// keys valid until tokens signed by them expire. The signing key is chosen with JWT_SIGNING_KID, defaulting to the private
+// key with the largest kid (name keys by date, e.g. 20250912.pem, so the newest key is used).
 func InitJwt() {
 	// Get app env
 	env, found := os.LookupEnv("ENV")
@@ -29,42 +51,130 @@ func InitJwt() {
 		env = "PROD"
 	}
 
-	var path string
+	var dir string
 	switch env {
 	case "PROD", "QA":
-		path = "secrets/jwt-key"
+		dir = "secrets/jwt-keys"
 	case "DEV":
-		path = "config/jwt-key.txt"
+		dir = "config/jwt-keys"
 	default:
-		path = "run/secrets/jwt-key" // only used when running from docker compose, can be removed.
+		dir = "run/secrets/jwt-keys" // only used when running from docker compose, can be removed.
+	}
+
+	JwtHandler = &Jwt{
+		verificationKeys: make(map[string]*jwtKey),
 	}
-	key, err := os.ReadFile(path)
+
+	entries, err := os.ReadDir(dir)
 	if err != nil {
-		utils.Logger.Warn().Err(err).Msgf("Error reading secret from %s", path)
+		utils.Logger.Warn().Err(err).Msgf("Error reading JWT keys from %s", dir)
 	}
 
-	// Check if secret key was read correctly
-	if len(key) == 0 {
-		utils.Logger.Warn().Msg("JWT secret key is empty!")
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".pem") {
+			continue
+		}
+
+		kid := strings.TrimSuffix(strings.TrimSuffix(entry.Name(), ".pem"), ".pub")
+		key, err := loadJwtKey(filepath.Join(dir, entry.Name()), kid)
+		if err != nil {
+			utils.Logger.Warn().Err(err).Msgf("Skipping JWT key %s", entry.Name())
+			continue
+		}
+
+		JwtHandler.verificationKeys[kid] = key
+		utils.Logger.Info().Str("kid", kid).Str("alg", key.method.Alg()).Bool("can sign", key.private != nil).Msg("JWT key loaded")
 	}
 
-	JwtHandler = &Jwt{
-		secretKey: key,
+	// Choose signing key
+	signingKid, found := os.LookupEnv("JWT_SIGNING_KID")
+	if !found {
+		kids := make([]string, 0, len(JwtHandler.verificationKeys))
+		for kid, key := range JwtHandler.verificationKeys {
+			if key.private != nil {
+				kids = append(kids, kid)
+			}
+		}
+		slices.Sort(kids)
+
+		if len(kids) > 0 {
+			signingKid = kids[len(kids)-1]
+		}
+	}
+
+	if key, ok := JwtHandler.verificationKeys[signingKid]; ok && key.private != nil {
+		JwtHandler.signingKey = key
+		utils.Logger.Info().Str("kid", signingKid).Msg("JWT signing key selected")
+	} else {
+		utils.Logger.Warn().Str("kid", signingKid).Msg("No JWT signing key found! Tokens cannot be issued.")
 	}
 }
 
-// Generate and sign jwt, returning a token as a string
+// Read a PEM encoded private or public key from path.
+func loadJwtKey(path string, kid string) (*jwtKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("no PEM data found in %s", path)
+	}
+
+	var parsed any
+	switch block.Type {
+	case "PRIVATE KEY":
+		parsed, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	case "RSA PRIVATE KEY":
+		parsed, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "PUBLIC KEY":
+		parsed, err = x509.ParsePKIXPublicKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("unsupported PEM block type %s", block.Type)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	key := &jwtKey{kid: kid}
+	switch k := parsed.(type) {
+	case *rsa.PrivateKey:
+		key.method, key.private, key.public = jwt.SigningMethodRS256, k, &k.PublicKey
+	case ed25519.PrivateKey:
+		key.method, key.private, key.public = jwt.SigningMethodEdDSA, k, k.Public()
+	case *rsa.PublicKey:
+		key.method, key.public = jwt.SigningMethodRS256, k
+	case ed25519.PublicKey:
+		key.method, key.public = jwt.SigningMethodEdDSA, k
+	default:
+		return nil, fmt.Errorf("unsupported key type %T, only RSA and Ed25519 keys are supported", parsed)
+	}
+
+	return key, nil
+}
+
+// Generate and sign jwt, returning a token as a string.
+// Issued at and expiry are set on the claim, so the caller can read the expiry after signing. Expiry defaults to
+// ACCESS_TOKEN_TTL if not already set.
 func (j *Jwt) GenerateJwt(claim *models.JwtClaim) (string, error) {
-	secretKey := j.secretKey
+	if j.signingKey == nil {
+		utils.Logger.Error().Msg("No JWT signing key loaded")
+		return "", errors.New("no JWT signing key loaded")
+	}
+
+	now := time.Now()
+	claim.IssuedAt = jwt.NewNumericDate(now)
+	if claim.ExpiresAt == nil {
+		claim.ExpiresAt = jwt.NewNumericDate(now.Add(constants.ACCESS_TOKEN_TTL))
+	}
 
 	// Generate JWT
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
-		"uid": claim.Uid,
-		"iat": time.Now().Unix(),
-	})
+	token := jwt.NewWithClaims(j.signingKey.method, claim)
+	token.Header["kid"] = j.signingKey.kid
 
 	// Sign key
-	tokenString, err := token.SignedString(secretKey)
+	tokenString, err := token.SignedString(j.signingKey.private)
 	if err != nil {
 		utils.Logger.Error().Err(err).Msg("Error signing JWT token")
 		return "", err
@@ -73,29 +183,29 @@ func (j *Jwt) GenerateJwt(claim *models.JwtClaim) (string, error) {
 	return tokenString, nil
 }
 
-// Get uid from JWT token in request.
-// This acts as a middleware, so it automatically returns a 401 Unauthorized response if the JWT is invalid or missing.
-func (j *Jwt) GetUidFromJwt(c *gin.Context) string {
-	// Get Uid from JWT token
-	jwt := c.Request.Header.Get("Authorization")
-	claim, err := j.ParseJwt(jwt)
-	if err != nil {
-		utils.Logger.Error().Err(err).Msg("Error parsing JWT token")
-		c.JSON(http.StatusUnauthorized, gin.H{
-			"success": false,
-			"message": "Error parsing JWT token",
-			"error":   err.Error(),
-		})
-		return ""
+// Retrieve all verification keys as a JWK set, to be served publicly so that other services can verify our tokens.
+func (j *Jwt) GetJwks() *models.JwkSet {
+	set := &models.JwkSet{Keys: []models.Jwk{}}
+
+	for kid, key := range j.verificationKeys {
+		jwk, err := models.NewJwk(kid, key.public)
+		if err != nil {
+			utils.Logger.Error().Err(err).Str("kid", kid).Msg("Error converting key to JWK")
+			continue
+		}
+		set.Keys = append(set.Keys, *jwk)
 	}
 
-	return claim.Uid
+	// Stable ordering for caching
+	slices.SortFunc(set.Keys, func(a, b models.Jwk) int {
+		return strings.Compare(a.Kid, b.Kid)
+	})
+
+	return set
 }
 
 // Parse signed jwt string
 func (j *Jwt) ParseJwt(tokenString string) (*models.JwtClaim, error) {
-	secretKey := j.secretKey
-
 	// Remove "Bearer " prefix if included
 	if len(tokenString) > 7 && tokenString[:7] == "Bearer " {
 		tokenString = tokenString[7:]
@@ -103,12 +213,22 @@ func (j *Jwt) ParseJwt(tokenString string) (*models.JwtClaim, error) {
 
 	// Parse and verify the token
 	token, err := jwt.ParseWithClaims(tokenString, &models.JwtClaim{}, func(token *jwt.Token) (interface{}, error) {
-		// Validate the signing method
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+		// Look up verification key by kid
+		kid, _ := token.Header["kid"].(string)
+		key, ok := j.verificationKeys[kid]
+		if !ok {
+			return nil, fmt.Errorf("unknown key id: %v", token.Header["kid"])
+		}
+
+		// Validate the signing method matches the key
+		if token.Method.Alg() != key.method.Alg() {
 			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
 		}
-		return secretKey, nil
-	})
+		return key.public, nil
+	},
+		jwt.WithValidMethods([]string{jwt.SigningMethodRS256.Alg(), jwt.SigningMethodEdDSA.Alg()}),
+		jwt.WithExpirationRequired(), // Tokens issued before expiry was introduced are rejected
+	)
 
 	if err != nil {
 		return nil, err
@@ -2,6 +2,7 @@ package services
 
 import (
 	"github.com/ntu-onemdp/onemdp-backend/internal/models"
+	"github.com/ntu-onemdp/onemdp-backend/internal/notification"
 	"github.com/ntu-onemdp/onemdp-backend/internal/repositories"
 )
 
@@ -15,7 +16,12 @@ var Likes *LikeService
 func (s *LikeService) CreateLike(uid string, contentID string) error {
 	like := models.NewLike(uid, contentID)
 
-	return s.likesRepository.Insert(like)
+	if err := s.likesRepository.Insert(like); err != nil {
+		return err
+	}
+
+	go notifyAuthor(contentID, notification.TypeLike, uid)
+	return nil
 }
 
 // Check if uid has liked a content
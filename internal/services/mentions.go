@@ -0,0 +1,36 @@
+package services
+
+import (
+	"github.com/ntu-onemdp/onemdp-backend/internal/models"
+	"github.com/ntu-onemdp/onemdp-backend/internal/notification"
+	"github.com/ntu-onemdp/onemdp-backend/internal/repositories"
+)
+
+type MentionService struct {
+	repo *repositories.MentionsRepository
+}
+
+var Mentions *MentionService
+
+// Record the users mentioned in content, which must already be sanitized, replacing the mentions recorded for it
+// before. Users who were not mentioned in it before are notified, except the author.
+// Errors are logged, as mentions are recorded after the content has been saved.
+func (s *MentionService) Record(source models.MentionSource, content string) {
+	uids := []string{}
+	if ids := models.MentionedIDs(content); len(ids) > 0 {
+		resolved, err := s.repo.Resolve(ids)
+		if err != nil {
+			return
+		}
+		uids = resolved
+	}
+
+	added, err := s.repo.Set(source, uids)
+	if err != nil {
+		return
+	}
+
+	for _, uid := range added {
+		notify(uid, notification.TypeMention, source.Author, source.IsAnon, source.LinkID, source.Title)
+	}
+}
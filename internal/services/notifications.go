@@ -0,0 +1,82 @@
+package services
+
+import (
+	"fmt"
+
+	constants "github.com/ntu-onemdp/onemdp-backend/config"
+	"github.com/ntu-onemdp/onemdp-backend/internal/notification"
+	"github.com/ntu-onemdp/onemdp-backend/internal/repositories"
+	"github.com/ntu-onemdp/onemdp-backend/internal/utils"
+)
+
+// Notify recipient of activity of actor on the thread or article linkID. Users are not notified of their own activity,
+// and actor is hidden from the recipient if isAnon. Errors are logged, as notifications are sent after the activity
+// has been saved.
+func notify(recipient string, notificationType string, actor string, isAnon bool, linkID string, message string) {
+	if recipient == "" || recipient == actor || recipient == constants.DELETED_USER_UID {
+		return
+	}
+
+	var actorUid *string
+	if !isAnon {
+		actorUid = &actor
+	}
+
+	if err := notification.Service.Notify(recipient, notificationType, actorUid, linkID, message); err != nil {
+		utils.Logger.Warn().Err(err).Str("uid", recipient).Str("type", notificationType).Msg("Error sending notification")
+	}
+}
+
+// Notify the author of a thread, post, article or comment of activity of actor on it
+func notifyAuthor(contentID string, notificationType string, actor string) {
+	author, linkID, title, err := contentSummary(contentID)
+	if err != nil {
+		utils.Logger.Warn().Err(err).Str("content id", contentID).Msg("Error retrieving content to notify its author")
+		return
+	}
+
+	notify(author, notificationType, actor, false, linkID, title)
+}
+
+// Retrieve the author of content, and the thread or article it belongs to with its title
+func contentSummary(contentID string) (string, string, string, error) {
+	if contentID == "" {
+		return "", "", "", fmt.Errorf("empty content id")
+	}
+
+	switch contentID[0] {
+	case 't':
+		author, err := Threads.threadRepo.GetAuthor(contentID)
+		if err != nil {
+			return "", "", "", err
+		}
+		title, err := Threads.threadRepo.GetTitle(contentID)
+		return author, contentID, title, err
+
+	case 'p':
+		post, err := Posts.postRepo.Get(contentID)
+		if err != nil {
+			return "", "", "", err
+		}
+		return post.AuthorUid, post.ThreadId, post.Title, nil
+
+	case 'a':
+		author, err := repositories.Articles.GetAuthor(contentID)
+		if err != nil {
+			return "", "", "", err
+		}
+		title, err := repositories.Articles.GetTitle(contentID)
+		return author, contentID, title, err
+
+	case 'c':
+		comment, err := repositories.Comments.Get(contentID)
+		if err != nil {
+			return "", "", "", err
+		}
+		title, err := repositories.Articles.GetTitle(comment.ArticleID)
+		return comment.AuthorUID, comment.ArticleID, title, err
+
+	default:
+		return "", "", "", fmt.Errorf("unknown content type: %c", contentID[0])
+	}
+}
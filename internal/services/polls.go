@@ -0,0 +1,150 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	c "github.com/ntu-onemdp/onemdp-backend/config"
+	"github.com/ntu-onemdp/onemdp-backend/internal/models"
+	"github.com/ntu-onemdp/onemdp-backend/internal/repositories"
+	"github.com/ntu-onemdp/onemdp-backend/internal/utils"
+)
+
+type PollService struct {
+	repo *repositories.PollsRepository
+}
+
+var Polls *PollService
+
+// Add a poll to a thread. Only the author of the thread and moderators can add polls, and a thread has at most one poll.
+func (s *PollService) Create(threadID string, question string, options []string, multipleChoice bool, isAnon bool, closesAt *time.Time, p *models.Principal) (*models.Poll, error) {
+	author, err := Threads.threadRepo.GetAuthor(threadID)
+	if err != nil {
+		return nil, err
+	}
+	if author != p.Uid && !p.Can(models.PermContentModerate) {
+		return nil, utils.NewErrUnauthorized()
+	}
+
+	if strings.TrimSpace(question) == "" {
+		return nil, errors.New("question is required")
+	}
+	if len(options) < c.MIN_POLL_OPTIONS || len(options) > c.MAX_POLL_OPTIONS {
+		return nil, fmt.Errorf("polls must have between %d and %d options", c.MIN_POLL_OPTIONS, c.MAX_POLL_OPTIONS)
+	}
+	for _, option := range options {
+		if strings.TrimSpace(option) == "" {
+			return nil, errors.New("options cannot be blank")
+		}
+	}
+	if closesAt != nil && closesAt.Before(time.Now()) {
+		return nil, errors.New("closing time must be in the future")
+	}
+
+	poll, pollOptions := models.NewPoll(threadID, question, options, multipleChoice, isAnon, closesAt, p.Uid)
+	if err := s.repo.Insert(poll, pollOptions); err != nil {
+		return nil, err
+	}
+
+	return poll, nil
+}
+
+// Get the poll of a thread as seen by the user. Votes are hidden until the user has voted or the poll has closed,
+// except from the creator of the poll and moderators. Returns pgx.ErrNoRows if the thread has no poll.
+func (s *PollService) GetResults(threadID string, p *models.Principal) (*models.PollResults, error) {
+	poll, err := s.repo.GetByThread(threadID)
+	if err != nil {
+		return nil, err
+	}
+
+	votes, err := s.repo.GetVotes(poll.PollID, p.Uid)
+	if err != nil {
+		return nil, err
+	}
+
+	options, err := s.repo.GetOptions(poll.PollID)
+	if err != nil {
+		return nil, err
+	}
+
+	results := &models.PollResults{
+		Poll:     *poll,
+		IsClosed: poll.Closed(),
+		Votes:    votes,
+		Options:  options,
+	}
+	results.ResultsVisible = results.IsClosed || len(votes) > 0 || poll.CreatedBy == p.Uid || p.Can(models.PermContentModerate)
+
+	if !results.ResultsVisible {
+		for i := range results.Options {
+			results.Options[i].NumVotes = 0
+		}
+		return results, nil
+	}
+
+	numVoters, err := s.repo.CountVoters(poll.PollID)
+	if err != nil {
+		return nil, err
+	}
+	results.NumVoters = &numVoters
+
+	if !poll.IsAnon {
+		voters, err := s.repo.GetVoters(poll.PollID)
+		if err != nil {
+			return nil, err
+		}
+
+		index := make(map[string]int, len(results.Options))
+		for i, option := range results.Options {
+			index[option.OptionID] = i
+		}
+		for _, voter := range voters {
+			if i, ok := index[voter.OptionID]; ok {
+				results.Options[i].Voters = append(results.Options[i].Voters, voter)
+			}
+		}
+	}
+
+	return results, nil
+}
+
+// Vote in the poll of a thread, replacing any previous votes of the user. Single choice polls take exactly one option.
+func (s *PollService) Vote(threadID string, optionIDs []string, p *models.Principal) error {
+	poll, err := s.repo.GetByThread(threadID)
+	if err != nil {
+		return err
+	}
+
+	if poll.Closed() {
+		return errors.New("poll is closed")
+	}
+	if !Threads.ThreadExists(threadID) {
+		return errors.New("thread is no longer available")
+	}
+
+	// Each option is counted once
+	unique := removeDuplicates(optionIDs)
+	if len(unique) == 0 {
+		return errors.New("at least one option is required")
+	}
+	if !poll.MultipleChoice && len(unique) > 1 {
+		return errors.New("only one option can be chosen in this poll")
+	}
+
+	return s.repo.Vote(poll.PollID, p.Uid, unique)
+}
+
+// Returns values in order with duplicates removed
+func removeDuplicates(values []string) []string {
+	unique := []string{}
+	seen := map[string]bool{}
+	for _, v := range values {
+		if !seen[v] {
+			seen[v] = true
+			unique = append(unique, v)
+		}
+	}
+	return unique
+}
@@ -1,10 +1,13 @@
 package services
 
 import (
+	"errors"
+	"fmt"
 	"strings"
 
 	constants "github.com/ntu-onemdp/onemdp-backend/config"
 	"github.com/ntu-onemdp/onemdp-backend/internal/models"
+	"github.com/ntu-onemdp/onemdp-backend/internal/notification"
 	"github.com/ntu-onemdp/onemdp-backend/internal/repositories"
 	"github.com/ntu-onemdp/onemdp-backend/internal/utils"
 )
@@ -16,6 +19,9 @@ type PostService struct {
 
 var Posts *PostService
 
+// Returned when posting in a locked thread
+var ErrThreadLocked = errors.New("thread is locked and no longer accepts new posts")
+
 func NewPostService(postRepo *repositories.PostsRepository) *PostService {
 	return &PostService{
 		postRepo:    postRepo,
@@ -23,13 +29,33 @@ func NewPostService(postRepo *repositories.PostsRepository) *PostService {
 	}
 }
 
-func (s *PostService) CreateNewPost(author string, replyTo *string, threadId string, title string, content string, isAnon bool) error {
+// Create new post and insert into the repository
+// Returns post id on success
+func (s *PostService) CreateNewPost(author string, replyTo *string, threadId string, title string, content string, isAnon bool) (string, error) {
+	locked, err := Threads.IsLocked(threadId)
+	if err != nil {
+		return "", errors.New("thread does not exist")
+	}
+	if locked {
+		return "", ErrThreadLocked
+	}
+
 	post := s.postFactory.New(author, threadId, title, content, replyTo, false, isAnon)
 
-	err := s.postRepo.Create(post)
+	if replyTo != nil {
+		parent, err := s.postRepo.Get(*replyTo)
+		if err != nil || parent.ThreadId != threadId {
+			return "", errors.New("post being replied to does not exist in this thread")
+		}
+	}
+	if err := s.checkQuotes(post, threadId); err != nil {
+		return "", err
+	}
+
+	err = s.postRepo.Create(post)
 	if err != nil {
 		utils.Logger.Warn().Msg("Error encountered inserting post to DB. Eduvisor will not be triggered.")
-		return err
+		return "", err
 	}
 
 	// Check if eduvisor bot is mentioned
@@ -41,8 +67,30 @@ func (s *PostService) CreateNewPost(author string, replyTo *string, threadId str
 		}()
 	}
 
-	// Error should be nil here already.
-	return err
+	go Mentions.Record(postMentionSource(post), post.PostContent)
+	go s.notifyReply(post)
+
+	return post.PostID, nil
+}
+
+// Notify the author of the thread and the author of the post being replied to of a new post. Answers from Eduvisor
+// are sent to the author of the thread as Eduvisor notifications.
+func (s *PostService) notifyReply(post *models.DbPost) {
+	threadAuthor, err := Threads.threadRepo.GetAuthor(post.ThreadId)
+	if err == nil {
+		notificationType := notification.TypeReply
+		if Eduvisor != nil && post.AuthorUid == Eduvisor.EduvisorModel.Uid {
+			notificationType = notification.TypeEduvisor
+		}
+		notify(threadAuthor, notificationType, post.AuthorUid, post.IsAnon, post.ThreadId, post.Title)
+	}
+
+	if post.ReplyTo != nil {
+		parent, err := s.postRepo.Get(*post.ReplyTo)
+		if err == nil && parent.AuthorUid != threadAuthor {
+			notify(parent.AuthorUid, notification.TypeReply, post.AuthorUid, post.IsAnon, post.ThreadId, post.Title)
+		}
+	}
 }
 
 // Retrieve post by post_id
@@ -55,16 +103,10 @@ func (s *PostService) PostExists(postID string) bool {
 	return s.postRepo.IsAvailable(postID)
 }
 
-// Update post. Only the content and the title can be updated.
-// Post can only be updated by the author of the post or by admin or staff
-func (s *PostService) UpdatePost(updated_post models.DbPost, uid string) error {
-	hasStaffPermission, err := Users.HasStaffPermission(uid)
-	if err != nil {
-		utils.Logger.Error().Err(err).Msg("Error checking staff permission")
-		return err
-	}
-
-	if !hasStaffPermission {
+// Update post. Only the content and the title can be updated, and the previous version is kept as a revision.
+// Post can only be updated by the author of the post or by users who can moderate content
+func (s *PostService) UpdatePost(updated_post models.DbPost, p *models.Principal) error {
+	if !p.Can(models.PermContentModerate) {
 		author, err := s.postRepo.GetAuthor(updated_post.PostID)
 		if author == "" || err != nil {
 			utils.Logger.Error().Err(err).Msg("Error getting author of post")
@@ -72,23 +114,100 @@ func (s *PostService) UpdatePost(updated_post models.DbPost, uid string) error {
 		}
 
 		// Check if author of post matches the author in JWT claim
-		if author != uid {
+		if author != p.Uid {
 			return utils.NewErrUnauthorized()
 		}
 	}
 
-	return s.postRepo.Update(updated_post.PostID, updated_post)
+	current, err := s.postRepo.Get(updated_post.PostID)
+	if err != nil {
+		return err
+	}
+
+	updated_post.PostContent = utils.SanitizeContent(updated_post.PostContent)
+	if err := s.checkQuotes(&updated_post, current.ThreadId); err != nil {
+		return err
+	}
+
+	if err := s.postRepo.Update(updated_post.PostID, updated_post, p.Uid); err != nil {
+		return err
+	}
+
+	// Mentions are made by the author of the post, not by the moderator editing it
+	current.Title = updated_post.Title
+	go Mentions.Record(postMentionSource(current), updated_post.PostContent)
+
+	return nil
+}
+
+// Source of the mentions in post. Mentions in the header post of a thread are mentions in the thread.
+func postMentionSource(post *models.DbPost) models.MentionSource {
+	contentType := models.TrashPost
+	if post.IsHeader {
+		contentType = models.TrashThread
+	}
+
+	return models.MentionSource{
+		ContentID:   post.PostID,
+		ContentType: contentType,
+		Author:      post.AuthorUid,
+		IsAnon:      post.IsAnon,
+		LinkID:      post.ThreadId,
+		Title:       post.Title,
+	}
 }
 
-// Delete post only if author matches the author of the post or if user is admin or staff
-func (s *PostService) DeletePost(postID string, uid string) error {
-	hasStaffPermission, err := Users.HasStaffPermission(uid)
+// Check that every post quoted in post is an available post in the thread
+func (s *PostService) checkQuotes(post *models.DbPost, threadID string) error {
+	for _, id := range post.QuotedPostIDs() {
+		quoted, err := s.postRepo.Get(id)
+		if err != nil || quoted.ThreadId != threadID {
+			return fmt.Errorf("quoted post %s does not exist in this thread", id)
+		}
+	}
+	return nil
+}
+
+// Retrieve all previous versions of a post, newest first
+func (s *PostService) GetRevisions(postID string) ([]models.PostRevision, error) {
+	return s.postRepo.GetRevisions(postID)
+}
+
+// Compare two versions of a post. Revision 0 is the current version.
+func (s *PostService) DiffRevisions(postID string, from int, to int) (*models.RevisionDiff, error) {
+	fromRev, err := s.getVersion(postID, from)
 	if err != nil {
-		utils.Logger.Error().Err(err).Msg("Error checking staff permission")
-		return err
+		return nil, err
+	}
+	toRev, err := s.getVersion(postID, to)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.RevisionDiff{
+		From:      from,
+		To:        to,
+		FromTitle: fromRev.Title,
+		ToTitle:   toRev.Title,
+		Lines:     utils.LineDiff(splitTags(fromRev.Content), splitTags(toRev.Content)),
+	}, nil
+}
+
+func (s *PostService) getVersion(postID string, revision int) (*models.PostRevision, error) {
+	if revision != 0 {
+		return s.postRepo.GetRevision(postID, revision)
 	}
 
-	if !hasStaffPermission {
+	post, err := s.postRepo.Get(postID)
+	if err != nil {
+		return nil, err
+	}
+	return &models.PostRevision{PostID: postID, Title: post.Title, Content: post.PostContent}, nil
+}
+
+// Delete post only if author matches the author of the post or if user can hide content
+func (s *PostService) DeletePost(postID string, p *models.Principal) error {
+	if !p.Can(models.PermContentHide) {
 		author, err := s.postRepo.GetAuthor(postID)
 		if author == "" || err != nil {
 			utils.Logger.Error().Err(err).Msg("Error getting author of post")
@@ -96,9 +215,91 @@ func (s *PostService) DeletePost(postID string, uid string) error {
 		}
 
 		// Check if author of post matches the author in JWT claim
-		if author != uid {
+		if author != p.Uid {
 			return utils.NewErrUnauthorized()
 		}
 	}
-	return s.postRepo.Delete(postID)
+	return s.postRepo.Delete(postID, p.Uid)
+}
+
+// Staff: endorse a post as an instructor
+func (s *PostService) Endorse(postID string, p *models.Principal) error {
+	if err := s.postRepo.Endorse(postID, p.Uid); err != nil {
+		return err
+	}
+
+	go notifyAuthor(postID, notification.TypeEndorsement, p.Uid)
+	return nil
+}
+
+// Staff: remove endorsement of a post
+func (s *PostService) Unendorse(postID string) error {
+	return s.postRepo.Unendorse(postID)
+}
+
+// Arrange posts of a thread for the given view. Posts must be in the order they were created.
+//
+// PostViewFlat returns every post in the order they were created. PostViewThreaded returns every post in thread order:
+// each post is followed by its replies, and posts which are not replies keep their order. PostViewTree returns only posts
+// which are not replies, with replies nested under them. Replies to posts which are no longer available are treated as
+// posts which are not replies. Depth is set in all views.
+func arrangePosts(posts []models.Post, view string) []models.Post {
+	ids := make(map[string]bool, len(posts))
+	for _, post := range posts {
+		ids[post.PostID] = true
+	}
+
+	children := make(map[string][]int)
+	for i, post := range posts {
+		parent := ""
+		if post.ReplyTo != nil && ids[*post.ReplyTo] {
+			parent = *post.ReplyTo
+		}
+		children[parent] = append(children[parent], i)
+	}
+
+	arranged := []models.Post{}
+
+	var flatten func(parent string, depth int)
+	flatten = func(parent string, depth int) {
+		for _, i := range children[parent] {
+			post := posts[i]
+			post.Depth = depth
+			arranged = append(arranged, post)
+			flatten(post.PostID, depth+1)
+		}
+	}
+
+	var nest func(parent string, depth int) []models.Post
+	nest = func(parent string, depth int) []models.Post {
+		tree := []models.Post{}
+		for _, i := range children[parent] {
+			post := posts[i]
+			post.Depth = depth
+			post.Replies = nest(post.PostID, depth+1)
+			tree = append(tree, post)
+		}
+		return tree
+	}
+
+	switch view {
+	case models.PostViewTree:
+		return nest("", 0)
+	case models.PostViewThreaded:
+		flatten("", 0)
+		return arranged
+	}
+
+	flatten("", 0)
+	depths := make(map[string]int, len(arranged))
+	for _, post := range arranged {
+		depths[post.PostID] = post.Depth
+	}
+
+	chronological := make([]models.Post, len(posts))
+	for i, post := range posts {
+		post.Depth = depths[post.PostID]
+		chronological[i] = post
+	}
+	return chronological
 }
@@ -0,0 +1,69 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/ntu-onemdp/onemdp-backend/internal/models"
+)
+
+// Posts in the order they were created. Each post is "id" or "id>parent" for a reply to parent.
+func testPosts(specs ...string) []models.Post {
+	posts := make([]models.Post, len(specs))
+	for i, spec := range specs {
+		id, parent, isReply := strings.Cut(spec, ">")
+		posts[i].PostID = id
+		if isReply {
+			posts[i].ReplyTo = &parent
+		}
+	}
+	return posts
+}
+
+// Format posts as "id:depth", with replies of the tree view in brackets
+func formatPosts(posts []models.Post) string {
+	parts := make([]string, len(posts))
+	for i, post := range posts {
+		parts[i] = fmt.Sprintf("%s:%d", post.PostID, post.Depth)
+		if len(post.Replies) > 0 {
+			parts[i] += "[" + formatPosts(post.Replies) + "]"
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+func TestArrangePosts(t *testing.T) {
+	// b replies to a, c is a new post, d replies to b, e replies to a, f replies to a post that is no longer available
+	posts := testPosts("a", "b>a", "c", "d>b", "e>a", "f>gone")
+
+	tests := []struct {
+		view string
+		want string
+	}{
+		{models.PostViewFlat, "a:0 b:1 c:0 d:2 e:1 f:0"},
+		{"", "a:0 b:1 c:0 d:2 e:1 f:0"},
+		{models.PostViewThreaded, "a:0 b:1 d:2 e:1 c:0 f:0"},
+		{models.PostViewTree, "a:0[b:1[d:2] e:1] c:0 f:0"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.view, func(t *testing.T) {
+			if got := formatPosts(arrangePosts(posts, tt.view)); got != tt.want {
+				t.Errorf("arrangePosts(%q) = %s; want %s", tt.view, got, tt.want)
+			}
+		})
+	}
+
+	if got := formatPosts(posts); got != "a:0 b:0 c:0 d:0 e:0 f:0" {
+		t.Errorf("arrangePosts modified its input: %s", got)
+	}
+}
+
+func TestArrangePostsEmpty(t *testing.T) {
+	for _, view := range []string{models.PostViewFlat, models.PostViewThreaded, models.PostViewTree} {
+		if got := arrangePosts(nil, view); len(got) != 0 {
+			t.Errorf("arrangePosts(nil, %q) = %v; want empty", view, got)
+		}
+	}
+}
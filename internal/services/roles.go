@@ -0,0 +1,195 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	c "github.com/ntu-onemdp/onemdp-backend/config"
+	"github.com/ntu-onemdp/onemdp-backend/internal/models"
+	"github.com/ntu-onemdp/onemdp-backend/internal/repositories"
+	"github.com/ntu-onemdp/onemdp-backend/internal/utils"
+)
+
+// Role names are lowercase and may contain digits, underscores and hyphens
+var roleNamePattern = regexp.MustCompile(`^[a-z][a-z0-9_-]{1,31}$`)
+
+// Roles are checked on every request, so they are cached. The cache is reloaded after every change and at least every
+// ROLE_CACHE_TTL, so that changes made by other instances are picked up.
+type RoleService struct {
+	repo *repositories.RolesRepository
+
+	mu       sync.RWMutex
+	roles    map[string]*models.Role
+	loadedAt time.Time
+}
+
+var Roles *RoleService
+
+// Returned when a principal acts on a role with permissions it does not hold
+var ErrRoleNotHeld = errors.New("role has permissions you do not have")
+
+// Returned when giving users a role which does not exist or is reserved for special users
+var ErrRoleNotAssignable = errors.New("invalid role provided")
+
+func NewRoleService(repo *repositories.RolesRepository) *RoleService {
+	return &RoleService{repo: repo}
+}
+
+// Retrieve role by name. Returns an error if the role does not exist.
+func (s *RoleService) Get(role string) (*models.Role, error) {
+	s.mu.RLock()
+	r, found := s.roles[role]
+	stale := time.Since(s.loadedAt) > c.ROLE_CACHE_TTL
+	s.mu.RUnlock()
+
+	if stale || !found {
+		if err := s.reload(); err != nil {
+			return nil, err
+		}
+
+		s.mu.RLock()
+		r, found = s.roles[role]
+		s.mu.RUnlock()
+	}
+
+	if !found {
+		return nil, fmt.Errorf("unknown role: %s", role)
+	}
+	return r, nil
+}
+
+// Retrieve all roles with their permissions
+func (s *RoleService) GetAll() ([]models.Role, error) {
+	return s.repo.GetAll()
+}
+
+// Retrieve role if p holds every permission of it. Returns ErrRoleNotHeld otherwise, so that principals can never give
+// or act as a role with more access than they have.
+func (s *RoleService) HeldBy(p *models.Principal, role string) (*models.Role, error) {
+	r, err := s.Get(role)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, perm := range r.Permissions {
+		if !p.Can(perm) {
+			return nil, ErrRoleNotHeld
+		}
+	}
+	return r, nil
+}
+
+// Retrieve role if p may give it to users: the role must be assignable and held by p.
+// Returns ErrRoleNotAssignable or ErrRoleNotHeld otherwise.
+func (s *RoleService) CheckGrantable(p *models.Principal, role string) (*models.Role, error) {
+	r, err := s.Get(role)
+	if err != nil || !r.IsAssignable() {
+		return nil, ErrRoleNotAssignable
+	}
+
+	return s.HeldBy(p, role)
+}
+
+// Create a role or replace the description and permissions of an existing role.
+// The admin role always has every permission, so that admins cannot lock themselves out.
+func (s *RoleService) Save(role string, description string, permissions []models.Permission) (*models.Role, error) {
+	if !roleNamePattern.MatchString(role) {
+		return nil, errors.New("role must be 2 to 32 lowercase letters, digits, underscores or hyphens")
+	}
+
+	if role == models.RoleAdmin {
+		return nil, errors.New("admin role cannot be changed")
+	}
+
+	for _, perm := range permissions {
+		if !models.IsValidPermission(perm) {
+			return nil, fmt.Errorf("unknown permission: %s", perm)
+		}
+	}
+
+	// Store each permission once, in a stable order
+	permissions = slices.Clone(permissions)
+	slices.Sort(permissions)
+	permissions = slices.Compact(permissions)
+	if permissions == nil {
+		permissions = []models.Permission{}
+	}
+
+	r := &models.Role{
+		Role:        role,
+		Description: description,
+		Permissions: permissions,
+	}
+	if err := s.repo.Upsert(r); err != nil {
+		return nil, err
+	}
+
+	if err := s.reload(); err != nil {
+		utils.Logger.Warn().Err(err).Msg("Role saved but role cache could not be reloaded")
+	}
+
+	return s.Get(role)
+}
+
+// Delete a role which is not built in and not held by any user, enrolment code or api key
+func (s *RoleService) Delete(role string) error {
+	if err := s.repo.Delete(role); err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23503" { // foreign_key_violation
+			return errors.New("role is still held by users, enrolment codes or api keys")
+		}
+		return err
+	}
+
+	if err := s.reload(); err != nil {
+		utils.Logger.Warn().Err(err).Msg("Role deleted but role cache could not be reloaded")
+	}
+
+	return nil
+}
+
+// Permissions held by both roles, used to cap api keys at their max role
+func (s *RoleService) CommonPermissions(role string, other string) ([]models.Permission, error) {
+	r, err := s.Get(role)
+	if err != nil {
+		return nil, err
+	}
+	o, err := s.Get(other)
+	if err != nil {
+		return nil, err
+	}
+
+	var common []models.Permission
+	for _, perm := range r.Permissions {
+		if o.Can(perm) {
+			common = append(common, perm)
+		}
+	}
+	return common, nil
+}
+
+func (s *RoleService) reload() error {
+	roles, err := s.repo.GetAll()
+	if err != nil {
+		utils.Logger.Error().Err(err).Msg("Error loading roles")
+		return err
+	}
+
+	cache := make(map[string]*models.Role, len(roles))
+	for i := range roles {
+		cache[roles[i].Role] = &roles[i]
+	}
+
+	s.mu.Lock()
+	s.roles = cache
+	s.loadedAt = time.Now()
+	s.mu.Unlock()
+
+	utils.Logger.Debug().Int("num roles", len(roles)).Msg("Roles loaded")
+	return nil
+}
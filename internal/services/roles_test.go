@@ -0,0 +1,54 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ntu-onemdp/onemdp-backend/internal/models"
+)
+
+// Role service with a fresh cache of roles, so that the database is never queried
+func newTestRoleService(roles ...models.Role) *RoleService {
+	cache := make(map[string]*models.Role, len(roles))
+	for i := range roles {
+		cache[roles[i].Role] = &roles[i]
+	}
+	return &RoleService{roles: cache, loadedAt: time.Now()}
+}
+
+func TestRoleHeldByAndGrantable(t *testing.T) {
+	s := newTestRoleService(
+		models.Role{Role: models.RoleStudent},
+		models.Role{Role: models.RoleTA, Permissions: []models.Permission{models.PermContentHide}},
+		models.Role{Role: models.RoleStaff, Permissions: []models.Permission{models.PermContentHide, models.PermUsersManage}},
+		models.Role{Role: models.RoleBot},
+	)
+	staff := &models.Principal{Uid: "staff", Role: models.RoleStaff, Permissions: []models.Permission{models.PermContentHide, models.PermUsersManage}}
+	ta := &models.Principal{Uid: "ta", Role: models.RoleTA, Permissions: []models.Permission{models.PermContentHide}}
+
+	tests := []struct {
+		name         string
+		p            *models.Principal
+		role         string
+		heldErr      error
+		grantableErr error
+	}{
+		{"role without permissions", ta, models.RoleStudent, nil, nil},
+		{"own role", ta, models.RoleTA, nil, nil},
+		{"role with fewer permissions", staff, models.RoleTA, nil, nil},
+		{"role with more permissions", ta, models.RoleStaff, ErrRoleNotHeld, ErrRoleNotHeld},
+		{"unassignable role", staff, models.RoleBot, nil, ErrRoleNotAssignable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := s.HeldBy(tt.p, tt.role); !errors.Is(err, tt.heldErr) {
+				t.Errorf("HeldBy() error = %v; want %v", err, tt.heldErr)
+			}
+			if _, err := s.CheckGrantable(tt.p, tt.role); !errors.Is(err, tt.grantableErr) {
+				t.Errorf("CheckGrantable() error = %v; want %v", err, tt.grantableErr)
+			}
+		})
+	}
+}
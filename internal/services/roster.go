@@ -0,0 +1,251 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"mime/multipart"
+	"net/mail"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	c "github.com/ntu-onemdp/onemdp-backend/config"
+	"github.com/ntu-onemdp/onemdp-backend/internal/models"
+	"github.com/ntu-onemdp/onemdp-backend/internal/semester"
+	"github.com/ntu-onemdp/onemdp-backend/internal/utils"
+)
+
+// Accepted headers of each roster column. Headers are matched case-insensitively.
+var rosterColumns = map[string][]string{
+	"email":          {"email", "email address"},
+	"name":           {"name", "full name"},
+	"role":           {"role"},
+	"semester":       {"semester", "sem"},
+	"tutorial_group": {"tutorial_group", "tutorial group", "group"},
+}
+
+// Read roster rows from an uploaded CSV or XLSX file. The first row must be a header row with at least an email column.
+// Only the file format is checked here, the contents of each row are validated by ImportRoster.
+func (s *UserService) ParseRoster(file *multipart.FileHeader) ([]models.RosterRow, error) {
+	if file.Size > c.MAX_ROSTER_SIZE {
+		return nil, fmt.Errorf("file size exceeds %d MB", c.MAX_ROSTER_SIZE/(1024*1024))
+	}
+
+	f, err := file.Open()
+	if err != nil {
+		utils.Logger.Error().Err(err).Msg("Error opening roster file")
+		return nil, err
+	}
+	defer f.Close()
+
+	var records [][]string
+	switch strings.ToLower(filepath.Ext(file.Filename)) {
+	case ".csv":
+		records, err = utils.ReadCSV(f, c.MAX_ROSTER_ROWS+1) // Header row is not counted
+	case ".xlsx":
+		records, err = utils.ReadXLSX(f, file.Size, c.MAX_ROSTER_ROWS+1)
+	default:
+		return nil, errors.New("unsupported file type, only .csv and .xlsx files are accepted")
+	}
+	if err != nil {
+		utils.Logger.Warn().Err(err).Str("filename", file.Filename).Msg("Error reading roster file")
+		return nil, err
+	}
+
+	if len(records) == 0 {
+		return nil, errors.New("file is empty")
+	}
+
+	// Map each column to its index in the file
+	index := make(map[string]int)
+	for i, header := range records[0] {
+		header = strings.ToLower(strings.TrimSpace(header))
+		for col, names := range rosterColumns {
+			if _, found := index[col]; !found && slices.Contains(names, header) {
+				index[col] = i
+			}
+		}
+	}
+	if _, found := index["email"]; !found {
+		return nil, errors.New("missing email column")
+	}
+
+	get := func(record []string, col string) string {
+		i, found := index[col]
+		if !found || i >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[i])
+	}
+
+	rows := make([]models.RosterRow, 0, len(records)-1)
+	for i, record := range records[1:] {
+		// Skip blank lines
+		if !slices.ContainsFunc(record, func(v string) bool { return strings.TrimSpace(v) != "" }) {
+			continue
+		}
+
+		rows = append(rows, models.RosterRow{
+			Row:           i + 2, // Header is row 1
+			Email:         get(record, "email"),
+			Name:          optional(get(record, "name")),
+			Role:          get(record, "role"),
+			Semester:      get(record, "semester"),
+			TutorialGroup: optional(get(record, "tutorial_group")),
+		})
+	}
+
+	if len(rows) > c.MAX_ROSTER_ROWS {
+		return nil, fmt.Errorf("file has %d rows, at most %d rows can be imported at once", len(rows), c.MAX_ROSTER_ROWS)
+	}
+
+	return rows, nil
+}
+
+// Validate roster rows and add valid rows as pending users. Rows are validated independently, and all valid rows are
+// saved in a single transaction. Pending users with the same email are overwritten.
+//
+// Missing roles default to student and missing semesters default to the current semester. On dry runs, rows are only
+// validated and nothing is saved. Rows can only give roles whose permissions are all held by p.
+func (s *UserService) ImportRoster(rows []models.RosterRow, dryRun bool, p *models.Principal) (*models.RosterImport, error) {
+	result := &models.RosterImport{
+		DryRun:  dryRun,
+		Results: make([]models.RosterResult, len(rows)),
+	}
+
+	// Normalise emails before checking for duplicates and registered users
+	emails := make([]string, len(rows))
+	for i := range rows {
+		rows[i].Email = strings.ToLower(rows[i].Email)
+		emails[i] = rows[i].Email
+	}
+
+	registered, err := s.UsersRepo.GetRegisteredEmails(emails)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]int)       // Email to row where it first appeared
+	semesters := make(map[string]bool) // Cache of semesters checked
+	allowedDomains := getAllowedDomains()
+
+	var pending []models.PendingUser
+	var pendingIdx []int // Index in results of each pending user
+	for i, row := range rows {
+		res := &result.Results[i]
+		res.Row, res.Email = row.Row, row.Email
+
+		// Email
+		if row.Email == "" {
+			res.Errors = append(res.Errors, "missing email")
+		} else if addr, err := mail.ParseAddress(row.Email); err != nil || addr.Address != row.Email {
+			res.Errors = append(res.Errors, "invalid email")
+		} else if !isAllowedDomain(row.Email, allowedDomains) {
+			res.Errors = append(res.Errors, "email domain not allowed")
+		} else if first, found := seen[row.Email]; found {
+			res.Errors = append(res.Errors, fmt.Sprintf("duplicate of row %d", first))
+		} else if slices.Contains(registered, row.Email) {
+			res.Errors = append(res.Errors, "already registered")
+		}
+		if _, found := seen[row.Email]; !found && row.Email != "" {
+			seen[row.Email] = row.Row
+		}
+
+		// Role
+		role := strings.ToLower(row.Role)
+		if role == "" {
+			role = models.RoleStudent
+		}
+		if _, err := Roles.CheckGrantable(p, role); errors.Is(err, ErrRoleNotHeld) {
+			res.Errors = append(res.Errors, fmt.Sprintf("role %s has permissions you do not have", role))
+		} else if err != nil {
+			res.Errors = append(res.Errors, fmt.Sprintf("invalid role %s", row.Role))
+		}
+
+		// Semester
+		sem := row.Semester
+		if sem == "" {
+			sem = *semester.Service.GetCurrentSem()
+		}
+		if _, checked := semesters[sem]; !checked {
+			exists, err := semester.Service.Exists(sem)
+			if err != nil {
+				return nil, err
+			}
+			semesters[sem] = exists
+		}
+		if !semesters[sem] {
+			res.Errors = append(res.Errors, fmt.Sprintf("unknown semester %s", sem))
+		}
+
+		if len(res.Errors) > 0 {
+			res.Result = models.RosterFailed
+			result.NumFailed++
+			continue
+		}
+
+		res.Result = models.RosterValid
+		result.NumSuccess++
+		pending = append(pending, models.PendingUser{
+			Email:         row.Email,
+			Role:          role,
+			Semester:      &sem,
+			Name:          row.Name,
+			TutorialGroup: row.TutorialGroup,
+		})
+		pendingIdx = append(pendingIdx, i)
+	}
+
+	if dryRun || len(pending) == 0 {
+		return result, nil
+	}
+
+	created, err := s.UsersRepo.AddPendingUsers(pending)
+	if err != nil {
+		utils.Logger.Error().Err(err).Msg("Error saving roster")
+		return nil, err
+	}
+
+	for i, idx := range pendingIdx {
+		if created[i] {
+			result.Results[idx].Result = models.RosterCreated
+		} else {
+			result.Results[idx].Result = models.RosterUpdated
+		}
+	}
+
+	utils.Logger.Info().Int("num success", result.NumSuccess).Int("num failed", result.NumFailed).Msg("Roster imported")
+	return result, nil
+}
+
+// Read comma-separated list of allowed email domains from env. Returns nil if all domains are allowed.
+func getAllowedDomains() []string {
+	var domains []string
+	for _, domain := range strings.Split(os.Getenv("ALLOWED_EMAIL_DOMAINS"), ",") {
+		if domain = strings.ToLower(strings.TrimSpace(domain)); domain != "" {
+			domains = append(domains, domain)
+		}
+	}
+	return domains
+}
+
+// Email is allowed if its domain is, or is a subdomain of, an allowed domain. All emails are allowed if domains is empty.
+func isAllowedDomain(email string, domains []string) bool {
+	if len(domains) == 0 {
+		return true
+	}
+
+	domain := email[strings.LastIndex(email, "@")+1:]
+	return slices.ContainsFunc(domains, func(allowed string) bool {
+		return domain == allowed || strings.HasSuffix(domain, "."+allowed)
+	})
+}
+
+// Convert empty strings to nil
+func optional(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
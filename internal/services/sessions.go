@@ -0,0 +1,73 @@
+package services
+
+import (
+	"time"
+
+	constants "github.com/ntu-onemdp/onemdp-backend/config"
+	"github.com/ntu-onemdp/onemdp-backend/internal/models"
+	"github.com/ntu-onemdp/onemdp-backend/internal/repositories"
+	"github.com/ntu-onemdp/onemdp-backend/internal/utils"
+)
+
+type SessionService struct {
+	repo *repositories.SessionsRepository
+}
+
+var Sessions *SessionService
+
+// Start a new session for uid. Returns the session and the plaintext refresh token.
+func (s *SessionService) Create(uid string, userAgent string) (*models.Session, string, error) {
+	session, token := models.NewSession(uid, userAgent)
+
+	if err := s.repo.Insert(session); err != nil {
+		return nil, "", err
+	}
+
+	return session, token, nil
+}
+
+// Exchange a refresh token for a new one. The old refresh token is invalidated.
+// Returns the session and the new plaintext refresh token.
+func (s *SessionService) Refresh(refreshToken string) (*models.Session, string, error) {
+	oldHash := utils.HashToken(refreshToken)
+
+	session, err := s.repo.GetActiveByTokenHash(oldHash)
+	if err != nil {
+		return nil, "", err
+	}
+
+	token := utils.GenerateToken()
+	session.TokenHash = utils.HashToken(token)
+	session.ExpiresAt = time.Now().Add(constants.REFRESH_TOKEN_TTL)
+
+	if err := s.repo.Rotate(session.SessionID, oldHash, session.TokenHash, session.ExpiresAt); err != nil {
+		return nil, "", err
+	}
+
+	utils.Logger.Debug().Str("uid", session.Uid).Str("session id", session.SessionID).Msg("Refresh token rotated")
+	return session, token, nil
+}
+
+// Returns true if the session has not been revoked and has not expired.
+func (s *SessionService) IsActive(sessionID string) bool {
+	if sessionID == "" {
+		return false
+	}
+
+	return s.repo.IsActive(sessionID)
+}
+
+// List all sessions of a user
+func (s *SessionService) GetSessions(uid string) ([]models.Session, error) {
+	return s.repo.GetByUid(uid)
+}
+
+// Revoke a single session (logout)
+func (s *SessionService) Revoke(sessionID string) error {
+	return s.repo.Revoke(sessionID)
+}
+
+// Revoke every session of a user. Returns number of sessions revoked.
+func (s *SessionService) RevokeAll(uid string) (int64, error) {
+	return s.repo.RevokeAllByUid(uid)
+}
@@ -0,0 +1,203 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/ntu-onemdp/onemdp-backend/internal/models"
+	"github.com/ntu-onemdp/onemdp-backend/internal/utils"
+)
+
+// Minimum time between two fetches of the JWKS, so that tokens with unknown kids cannot be used to flood the identity provider.
+const ssoJwksMinRefetchInterval = time.Minute
+
+// Verifies tokens issued by the SSO identity provider against its JWKS, issuer and audience.
+type SsoVerifier struct {
+	jwksSource string // URL or local file path of the JWKS
+	issuer     string
+	audience   string
+
+	mu          sync.RWMutex
+	keys        map[string]any // Keyed by kid
+	lastFetched time.Time
+	client      *http.Client
+}
+
+var Sso *SsoVerifier
+
+// Create the SSO verifier from environment variables.
+//
+// SSO_JWKS_URL is either an http(s) URL or a path to a local JWKS file (useful for tests).
+// SSO_ISSUER and SSO_AUDIENCE are checked against the iss and aud claims. SSO stays disabled unless both are set, as
+// tokens the identity provider issued to other clients would otherwise be accepted.
+func NewSsoVerifier() *SsoVerifier {
+	jwksSource, found := os.LookupEnv("SSO_JWKS_URL")
+	if !found {
+		utils.Logger.Warn().Msg("SSO_JWKS_URL is not set in .env. SSO login is disabled.")
+	}
+
+	issuer, found := os.LookupEnv("SSO_ISSUER")
+	if !found {
+		utils.Logger.Warn().Msg("SSO_ISSUER is not set in .env")
+	}
+
+	audience, found := os.LookupEnv("SSO_AUDIENCE")
+	if !found {
+		utils.Logger.Warn().Msg("SSO_AUDIENCE is not set in .env")
+	}
+
+	if jwksSource != "" && (issuer == "" || audience == "") {
+		utils.Logger.Error().Msg("SSO_ISSUER and SSO_AUDIENCE must both be set when SSO_JWKS_URL is set. SSO login is disabled.")
+		jwksSource = ""
+	}
+
+	s := &SsoVerifier{
+		jwksSource: jwksSource,
+		issuer:     issuer,
+		audience:   audience,
+		keys:       make(map[string]any),
+		client:     &http.Client{Timeout: 10 * time.Second},
+	}
+
+	if jwksSource != "" {
+		s.lastFetched = time.Now()
+		if err := s.fetchKeys(); err != nil {
+			utils.Logger.Error().Err(err).Str("source", jwksSource).Msg("Error fetching SSO JWKS. Will retry on first login.")
+		}
+	}
+
+	utils.Logger.Info().Str("jwks", jwksSource).Str("issuer", issuer).Str("audience", audience).Msg("SSO verifier initialized.")
+	return s
+}
+
+// Verify token issued by the identity provider and return its claims.
+// The uid of the user is the subject of the returned claim.
+func (s *SsoVerifier) Verify(tokenString string) (*models.SsoClaim, error) {
+	if s.jwksSource == "" {
+		return nil, errors.New("SSO is not configured")
+	}
+
+	opts := []jwt.ParserOption{
+		jwt.WithValidMethods([]string{jwt.SigningMethodRS256.Alg(), jwt.SigningMethodES256.Alg(), jwt.SigningMethodEdDSA.Alg()}),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuer(s.issuer),
+		jwt.WithAudience(s.audience),
+	}
+
+	token, err := jwt.ParseWithClaims(tokenString, &models.SsoClaim{}, func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		return s.getKey(kid)
+	}, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	claim, ok := token.Claims.(*models.SsoClaim)
+	if !ok || !token.Valid {
+		return nil, errors.New("error extracting claim from SSO token")
+	}
+
+	if claim.Subject == "" || claim.Email == "" {
+		return nil, errors.New("SSO token is missing sub or email claim")
+	}
+
+	return claim, nil
+}
+
+// Get verification key by kid. The JWKS is refetched once if the kid is unknown, to pick up rotated keys.
+func (s *SsoVerifier) getKey(kid string) (any, error) {
+	s.mu.RLock()
+	key, ok := s.keys[kid]
+	s.mu.RUnlock()
+
+	if ok {
+		return key, nil
+	}
+
+	// Claim the refetch so that concurrent logins with unknown kids do not all fetch the JWKS
+	s.mu.Lock()
+	canRefetch := time.Since(s.lastFetched) > ssoJwksMinRefetchInterval
+	if canRefetch {
+		s.lastFetched = time.Now()
+	}
+	s.mu.Unlock()
+
+	if canRefetch {
+		utils.Logger.Info().Str("kid", kid).Msg("Unknown SSO key id, refetching JWKS")
+		if err := s.fetchKeys(); err != nil {
+			return nil, err
+		}
+
+		s.mu.RLock()
+		key, ok = s.keys[kid]
+		s.mu.RUnlock()
+		if ok {
+			return key, nil
+		}
+	}
+
+	return nil, fmt.Errorf("unknown SSO key id: %s", kid)
+}
+
+// Fetch the JWKS from the configured URL or file and replace the cached keys.
+// The lock is only held to swap the keys, so logins are not blocked while the JWKS is downloaded.
+func (s *SsoVerifier) fetchKeys() error {
+	var data []byte
+	var err error
+	if strings.HasPrefix(s.jwksSource, "http://") || strings.HasPrefix(s.jwksSource, "https://") {
+		data, err = s.download()
+	} else {
+		data, err = os.ReadFile(s.jwksSource)
+	}
+	if err != nil {
+		return err
+	}
+
+	var set models.JwkSet
+	if err := json.Unmarshal(data, &set); err != nil {
+		return err
+	}
+
+	keys := make(map[string]any, len(set.Keys))
+	for _, jwk := range set.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+
+		key, err := jwk.PublicKey()
+		if err != nil {
+			utils.Logger.Warn().Err(err).Str("kid", jwk.Kid).Msg("Skipping SSO key")
+			continue
+		}
+		keys[jwk.Kid] = key
+	}
+
+	s.mu.Lock()
+	s.keys = keys
+	s.mu.Unlock()
+
+	utils.Logger.Debug().Int("num keys", len(keys)).Msg("SSO JWKS loaded")
+	return nil
+}
+
+func (s *SsoVerifier) download() ([]byte, error) {
+	resp, err := s.client.Get(s.jwksSource)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code %d fetching JWKS", resp.StatusCode)
+	}
+
+	return io.ReadAll(resp.Body)
+}
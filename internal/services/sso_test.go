@@ -0,0 +1,125 @@
+package services
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/ntu-onemdp/onemdp-backend/internal/models"
+)
+
+const (
+	testSsoIssuer   = "https://idp.example.com"
+	testSsoAudience = "onemdp"
+)
+
+// Write a JWKS containing key to a local file and point the SSO verifier at it
+func newTestSsoVerifier(t *testing.T, kid string, key ed25519.PublicKey) *SsoVerifier {
+	t.Helper()
+
+	jwk, err := models.NewJwk(kid, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := json.Marshal(models.JwkSet{Keys: []models.Jwk{*jwk}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv("SSO_JWKS_URL", path)
+	t.Setenv("SSO_ISSUER", testSsoIssuer)
+	t.Setenv("SSO_AUDIENCE", testSsoAudience)
+	return NewSsoVerifier()
+}
+
+func signSsoToken(t *testing.T, kid string, key ed25519.PrivateKey, claim *models.SsoClaim) string {
+	t.Helper()
+
+	token := jwt.NewWithClaims(jwt.SigningMethodEdDSA, claim)
+	token.Header["kid"] = kid
+	signed, err := token.SignedString(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return signed
+}
+
+func TestSsoVerify(t *testing.T) {
+	publicKey, privateKey, _ := ed25519.GenerateKey(rand.Reader)
+	_, otherKey, _ := ed25519.GenerateKey(rand.Reader)
+	s := newTestSsoVerifier(t, "key-1", publicKey)
+
+	claim := func(issuer string, audience string, expiresAt time.Time) *models.SsoClaim {
+		return &models.SsoClaim{
+			Email: "student@e.ntu.edu.sg",
+			RegisteredClaims: jwt.RegisteredClaims{
+				Subject:   "student",
+				Issuer:    issuer,
+				Audience:  jwt.ClaimStrings{audience},
+				ExpiresAt: jwt.NewNumericDate(expiresAt),
+			},
+		}
+	}
+	expiresAt := time.Now().Add(time.Hour)
+
+	tests := []struct {
+		name  string
+		token string
+		valid bool
+	}{
+		{"valid", signSsoToken(t, "key-1", privateKey, claim(testSsoIssuer, testSsoAudience, expiresAt)), true},
+		{"wrong issuer", signSsoToken(t, "key-1", privateKey, claim("https://evil.example.com", testSsoAudience, expiresAt)), false},
+		{"wrong audience", signSsoToken(t, "key-1", privateKey, claim(testSsoIssuer, "other-client", expiresAt)), false},
+		{"expired", signSsoToken(t, "key-1", privateKey, claim(testSsoIssuer, testSsoAudience, time.Now().Add(-time.Minute))), false},
+		{"unknown kid", signSsoToken(t, "key-2", otherKey, claim(testSsoIssuer, testSsoAudience, expiresAt)), false},
+		{"wrong key", signSsoToken(t, "key-1", otherKey, claim(testSsoIssuer, testSsoAudience, expiresAt)), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := s.Verify(tt.token)
+			if tt.valid {
+				if err != nil {
+					t.Fatalf("Verify() error = %v", err)
+				}
+				if got.Subject != "student" || got.Email != "student@e.ntu.edu.sg" {
+					t.Errorf("Verify() = %+v", got)
+				}
+				return
+			}
+			if err == nil {
+				t.Errorf("Verify() accepted invalid token")
+			}
+		})
+	}
+}
+
+func TestSsoDisabledWithoutIssuerAndAudience(t *testing.T) {
+	publicKey, privateKey, _ := ed25519.GenerateKey(rand.Reader)
+	newTestSsoVerifier(t, "key-1", publicKey)
+
+	t.Setenv("SSO_AUDIENCE", "")
+	s := NewSsoVerifier()
+
+	token := signSsoToken(t, "key-1", privateKey, &models.SsoClaim{
+		Email: "student@e.ntu.edu.sg",
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   "student",
+			Issuer:    testSsoIssuer,
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+		},
+	})
+	if _, err := s.Verify(token); err == nil {
+		t.Errorf("Verify() accepted token with SSO_AUDIENCE unset")
+	}
+}
@@ -0,0 +1,95 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	c "github.com/ntu-onemdp/onemdp-backend/config"
+	"github.com/ntu-onemdp/onemdp-backend/internal/models"
+	"github.com/ntu-onemdp/onemdp-backend/internal/repositories"
+)
+
+type TagService struct {
+	repo *repositories.TagsRepository
+}
+
+var Tags *TagService
+
+// Get all tags with the number of threads labelled with each
+func (s *TagService) GetAll() ([]models.TagCount, error) {
+	return s.repo.GetAll()
+}
+
+// Staff: create a new tag
+func (s *TagService) Create(name string, colour string, description string) (*models.Tag, error) {
+	tag := models.NewTag(name, colour, description)
+	if err := validateTag(tag); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Insert(tag); err != nil {
+		return nil, err
+	}
+
+	return tag, nil
+}
+
+// Staff: update name, colour and description of a tag
+func (s *TagService) Update(tagID string, name string, colour string, description string) (*models.Tag, error) {
+	tag, err := s.repo.Get(tagID)
+	if err != nil {
+		return nil, err
+	}
+
+	tag.Name = strings.TrimSpace(name)
+	tag.Description = strings.TrimSpace(description)
+	if colour != "" {
+		tag.Colour = colour
+	}
+	if err := validateTag(tag); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Update(tag); err != nil {
+		return nil, err
+	}
+
+	return tag, nil
+}
+
+// Staff: delete a tag and remove it from all threads
+func (s *TagService) Delete(tagID string) error {
+	return s.repo.Delete(tagID)
+}
+
+// Remove duplicate tag ids and check that there are at most MAX_THREAD_TAGS and that all of them exist
+func (s *TagService) ValidateThreadTags(tagIDs []string) ([]string, error) {
+	unique := removeDuplicates(tagIDs)
+	if len(unique) > c.MAX_THREAD_TAGS {
+		return nil, fmt.Errorf("threads can have at most %d tags", c.MAX_THREAD_TAGS)
+	}
+	if len(unique) == 0 {
+		return unique, nil
+	}
+
+	count, err := s.repo.CountExisting(unique)
+	if err != nil {
+		return nil, err
+	}
+	if count != len(unique) {
+		return nil, errors.New("tag does not exist")
+	}
+
+	return unique, nil
+}
+
+func validateTag(tag *models.Tag) error {
+	if tag.Name == "" {
+		return errors.New("tag name is required")
+	}
+	if !models.IsValidTagColour(tag.Colour) {
+		return errors.New("colour must be a hex colour, e.g. #1e90ff")
+	}
+	return nil
+}
@@ -1,7 +1,11 @@
 package services
 
 import (
+	"errors"
+
+	"github.com/ntu-onemdp/onemdp-backend/internal/karma"
 	"github.com/ntu-onemdp/onemdp-backend/internal/models"
+	"github.com/ntu-onemdp/onemdp-backend/internal/notification"
 	"github.com/ntu-onemdp/onemdp-backend/internal/repositories"
 	"github.com/ntu-onemdp/onemdp-backend/internal/utils"
 )
@@ -27,13 +31,18 @@ func NewThreadService(threadRepo *repositories.ThreadsRepository, postRepo *repo
 	}
 }
 
-// Create new thread and insert into the repository
+// Create new thread labelled with the given tags and insert into the repository
 // Returns thread id on success
-func (s *ThreadService) CreateNewThread(author string, title string, content string, isAnon bool) (string, error) {
+func (s *ThreadService) CreateNewThread(author string, title string, content string, isAnon bool, tagIDs []string) (string, error) {
+	tagIDs, err := Tags.ValidateThreadTags(tagIDs)
+	if err != nil {
+		return "", err
+	}
+
 	utils.Logger.Trace().Str("raw content", content).Msg("Content before sanitization")
 	thread := s.threadFactory.New(author, title, content, isAnon)
 
-	err := s.threadRepo.Insert(thread)
+	err = s.threadRepo.Insert(thread, tagIDs)
 	if err != nil {
 		return "", err
 	}
@@ -41,6 +50,9 @@ func (s *ThreadService) CreateNewThread(author string, title string, content str
 	post := s.postFactory.New(thread.AuthorUid, thread.ThreadID, thread.Title, content, nil, true, isAnon)
 
 	err = s.postRepo.Create(post)
+	if err == nil {
+		go Mentions.Record(postMentionSource(post), post.PostContent)
+	}
 
 	go func() {
 		Eduvisor.SendThread(thread.ThreadID)
@@ -49,13 +61,13 @@ func (s *ThreadService) CreateNewThread(author string, title string, content str
 	return thread.ThreadID, err
 }
 
-// Retrieve all threads in given page
-func (s *ThreadService) GetThreads(sort string, size int, descending bool, page int, uid string, searchKeyword string) ([]models.Thread, error) {
+// Retrieve all threads matching filter in given page
+func (s *ThreadService) GetThreads(sort string, size int, descending bool, page int, uid string, filter models.ThreadFilter) ([]models.Thread, error) {
 	// Convert sort string to ThreadColumn
-	column := models.StrToSortColumn(sort)
+	column := models.StrToThreadSortColumn(sort)
 
 	// Retrieve threads from db
-	threads, err := s.threadRepo.GetAll(column, uid, page, size, descending, searchKeyword)
+	threads, err := s.threadRepo.GetAll(column, uid, page, size, descending, filter)
 	if err != nil {
 		utils.Logger.Trace().Msg("Error getting threads from db")
 		return nil, err
@@ -64,13 +76,14 @@ func (s *ThreadService) GetThreads(sort string, size int, descending bool, page
 	return threads, nil
 }
 
-// Retrieve threads metadata
-func (s *ThreadService) GetMetadata(searchKeyword string) (*models.ContentMetadata, error) {
-	return s.threadRepo.GetMetadata(searchKeyword)
+// Retrieve metadata of threads matching filter
+func (s *ThreadService) GetMetadata(filter models.ThreadFilter) (*models.ContentMetadata, error) {
+	return s.threadRepo.GetMetadata(filter)
 }
 
-// Retrieve thread and all associated posts
-func (s *ThreadService) GetThread(threadID string, uid string) (*models.Thread, []models.Post, error) {
+// Retrieve thread and all associated posts, arranged as a flat list or a tree
+// (see models.PostViewFlat, models.PostViewThreaded and models.PostViewTree)
+func (s *ThreadService) GetThread(threadID string, uid string, view string) (*models.Thread, []models.Post, error) {
 	// Retrieve thread from db
 	thread, err := s.threadRepo.GetByID(threadID, uid)
 	if err != nil {
@@ -85,7 +98,7 @@ func (s *ThreadService) GetThread(threadID string, uid string) (*models.Thread,
 		return nil, nil, err
 	}
 
-	return thread, posts, nil
+	return thread, arrangePosts(posts, view), nil
 }
 
 // Check if thread exists
@@ -93,21 +106,19 @@ func (s *ThreadService) ThreadExists(threadID string) bool {
 	return s.threadRepo.IsAvailable(threadID)
 }
 
+// Check if thread is locked
+func (s *ThreadService) IsLocked(threadID string) (bool, error) {
+	return s.threadRepo.IsLocked(threadID)
+}
+
 // Update thread's last activity
 func (s *ThreadService) UpdateThreadLastActivity(threadID string) error {
 	return s.threadRepo.UpdateActivity(threadID)
 }
 
 // Update thread's title and preview
-func (s *ThreadService) UpdateThread(threadID string, title string, content string, uid string) error {
-	// Check if role is admin or staff
-	hasStaffPermission, err := Users.HasStaffPermission(uid)
-	if err != nil {
-		utils.Logger.Error().Err(err).Msg("Error checking staff permission")
-		return err
-	}
-
-	if !hasStaffPermission {
+func (s *ThreadService) UpdateThread(threadID string, title string, content string, p *models.Principal) error {
+	if !p.Can(models.PermContentModerate) {
 		author, err := s.threadRepo.GetAuthor(threadID)
 		if author == "" || err != nil {
 			utils.Logger.Error().Err(err).Msg("Error getting author of thread")
@@ -115,7 +126,7 @@ func (s *ThreadService) UpdateThread(threadID string, title string, content stri
 		}
 
 		// Check if author of thread matches the author in JWT claim
-		if author != uid {
+		if author != p.Uid {
 			return utils.NewErrUnauthorized()
 		}
 	}
@@ -123,16 +134,100 @@ func (s *ThreadService) UpdateThread(threadID string, title string, content stri
 	return s.threadRepo.Update(threadID, title, models.GetPreview(content))
 }
 
-// Delete thread and all associated posts
-func (s *ThreadService) DeleteThread(threadID string, uid string) error {
-	// Check if role is admin or staff
-	hasStaffPermission, err := Users.HasStaffPermission(uid)
+// Replace the tags of a thread. Only the author and moderators can change tags.
+func (s *ThreadService) UpdateTags(threadID string, tagIDs []string, p *models.Principal) error {
+	// Also checks that the thread exists
+	author, err := s.threadRepo.GetAuthor(threadID)
+	if err != nil {
+		return err
+	}
+
+	// Check if author of thread matches the author in JWT claim
+	if author != p.Uid && !p.Can(models.PermContentModerate) {
+		return utils.NewErrUnauthorized()
+	}
+
+	tagIDs, err = Tags.ValidateThreadTags(tagIDs)
+	if err != nil {
+		return err
+	}
+
+	return Tags.repo.SetThreadTags(threadID, tagIDs)
+}
+
+// Staff: pin, lock or announce a thread. Threads becoming announcements are pushed to the notification feed of all users.
+func (s *ThreadService) UpdateState(threadID string, state models.ThreadState, p *models.Principal) error {
+	wasAnnouncement, title, err := s.threadRepo.UpdateState(threadID, state)
 	if err != nil {
-		utils.Logger.Error().Err(err).Msg("Error checking staff permission")
 		return err
 	}
 
-	if !hasStaffPermission {
+	if state.Announcement != nil && *state.Announcement && !wasAnnouncement {
+		go func() {
+			if err := notification.Service.Announce(threadID, title, p.Uid); err != nil {
+				utils.Logger.Error().Err(err).Str("thread id", threadID).Msg("Error pushing announcement to users")
+			}
+		}()
+	}
+
+	return nil
+}
+
+// Accept a reply as the answer of a thread, marking the thread as resolved. Only the author of the thread and moderators
+// can accept answers. The author of the post is given karma, unless they accepted their own post.
+func (s *ThreadService) AcceptAnswer(threadID string, postID string, p *models.Principal) error {
+	author, err := s.threadRepo.GetAuthor(threadID)
+	if err != nil {
+		return err
+	}
+	if author != p.Uid && !p.Can(models.PermContentModerate) {
+		return utils.NewErrUnauthorized()
+	}
+
+	post, err := s.postRepo.Get(postID)
+	if err != nil || post.ThreadId != threadID || post.IsHeader {
+		return errors.New("answer must be a reply in this thread")
+	}
+
+	pts := karma.Service.GetSettings().AcceptedAnswerPts
+	if post.AuthorUid == p.Uid {
+		pts = 0
+	}
+
+	return s.threadRepo.AcceptAnswer(threadID, postID, post.AuthorUid, p.Uid, pts)
+}
+
+// Remove the accepted answer of a thread. Only the author of the thread and moderators can do this.
+func (s *ThreadService) UnacceptAnswer(threadID string, p *models.Principal) error {
+	author, err := s.threadRepo.GetAuthor(threadID)
+	if err != nil {
+		return err
+	}
+	if author != p.Uid && !p.Can(models.PermContentModerate) {
+		return utils.NewErrUnauthorized()
+	}
+
+	return s.threadRepo.UnacceptAnswer(threadID)
+}
+
+// Staff: endorse a thread as an instructor
+func (s *ThreadService) Endorse(threadID string, p *models.Principal) error {
+	if err := s.threadRepo.Endorse(threadID, p.Uid); err != nil {
+		return err
+	}
+
+	go notifyAuthor(threadID, notification.TypeEndorsement, p.Uid)
+	return nil
+}
+
+// Staff: remove endorsement of a thread
+func (s *ThreadService) Unendorse(threadID string) error {
+	return s.threadRepo.Unendorse(threadID)
+}
+
+// Delete thread and all associated posts
+func (s *ThreadService) DeleteThread(threadID string, p *models.Principal) error {
+	if !p.Can(models.PermContentHide) {
 		author, err := s.threadRepo.GetAuthor(threadID)
 		if author == "" || err != nil {
 			utils.Logger.Error().Err(err).Msg("Error getting author of thread")
@@ -140,10 +235,10 @@ func (s *ThreadService) DeleteThread(threadID string, uid string) error {
 		}
 
 		// Check if author of thread matches the author in JWT claim
-		if author != uid {
+		if author != p.Uid {
 			return utils.NewErrUnauthorized()
 		}
 	}
 
-	return s.threadRepo.Delete(threadID)
+	return s.threadRepo.Delete(threadID, p.Uid)
 }
@@ -0,0 +1,94 @@
+package services
+
+import (
+	"errors"
+	"math"
+	"os"
+	"strconv"
+	"time"
+
+	c "github.com/ntu-onemdp/onemdp-backend/config"
+	"github.com/ntu-onemdp/onemdp-backend/internal/models"
+	"github.com/ntu-onemdp/onemdp-backend/internal/repositories"
+	"github.com/ntu-onemdp/onemdp-backend/internal/utils"
+)
+
+type TrashService struct {
+	repo *repositories.TrashRepository
+}
+
+var Trash *TrashService
+
+// Staff: get a page of deleted content, most recently deleted first. All types of content are returned if contentType is empty.
+func (s *TrashService) GetAll(contentType string, page int, size int) ([]models.TrashItem, *models.ContentMetadata, error) {
+	switch contentType {
+	case "", models.TrashThread, models.TrashPost, models.TrashArticle, models.TrashComment:
+	default:
+		return nil, nil, errors.New("invalid content type")
+	}
+
+	items, err := s.repo.GetAll(contentType, page, size)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	metadata, err := s.repo.GetMetadata(contentType)
+	if err != nil {
+		return nil, nil, err
+	}
+	metadata.NumPages = int(math.Ceil(float64(metadata.Total) / float64(size)))
+
+	return items, metadata, nil
+}
+
+// Staff: restore deleted content. Threads come back with the posts deleted together with them and articles with their
+// comments. Karma deducted on deletion is given back to the authors.
+func (s *TrashService) Restore(contentID string) error {
+	if contentID == "" {
+		return errors.New("content id is required")
+	}
+
+	// Type of content is given by the prefix of its id
+	switch contentID[0] {
+	case 't':
+		return repositories.Threads.Restore(contentID)
+	case 'p':
+		return repositories.Posts.Restore(contentID)
+	case 'a':
+		return repositories.Articles.Restore(contentID)
+	case 'c':
+		return repositories.Comments.Restore(contentID)
+	default:
+		return errors.New("invalid content id")
+	}
+}
+
+// Permanently delete content which has been in the trash for longer than the retention period. Returns number of
+// threads, posts, articles and comments deleted.
+func (s *TrashService) Purge() (int64, error) {
+	return s.repo.Purge(time.Now().Add(-getTrashRetention()))
+}
+
+// Start background job purging the trash every TRASH_CLEANUP_INTERVAL
+func (s *TrashService) StartCleanup() {
+	go func() {
+		ticker := time.NewTicker(c.TRASH_CLEANUP_INTERVAL)
+		defer ticker.Stop()
+
+		for {
+			if n, err := s.Purge(); err == nil && n > 0 {
+				utils.Logger.Info().Int64("deleted", n).Msg("Deleted content purged from trash")
+			}
+			<-ticker.C
+		}
+	}()
+}
+
+// Read trash retention period from env. Falls back to DEFAULT_TRASH_RETENTION if it is not set or invalid.
+func getTrashRetention() time.Duration {
+	days, err := strconv.Atoi(os.Getenv("TRASH_RETENTION_DAYS"))
+	if err != nil || days <= 0 {
+		return c.DEFAULT_TRASH_RETENTION
+	}
+	return time.Duration(days) * 24 * time.Hour
+}
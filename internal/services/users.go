@@ -4,7 +4,8 @@ import (
 	"errors"
 	"fmt"
 	"mime/multipart"
-	"slices"
+	"strings"
+	"time"
 
 	c "github.com/ntu-onemdp/onemdp-backend/config"
 	"github.com/ntu-onemdp/onemdp-backend/internal/models"
@@ -38,7 +39,16 @@ func (s *UserService) RegisterUserFromPending(uid string, email string, name str
 
 // Register user from scratch. Default role of student is given.
 func (s *UserService) RegisterUser(uid string, email string, name string) error {
-	user := models.CreateUser(uid, name, email, models.Student.String())
+	user := models.CreateUser(uid, name, email, models.RoleStudent)
+
+	return s.UsersRepo.RegisterUser(user)
+}
+
+// Register user with the role, semester and tutorial group granted by an enrolment code.
+func (s *UserService) RegisterUserWithCode(uid string, email string, name string, role string, semester string, tutorialGroup *string) error {
+	user := models.CreateUser(uid, name, email, role)
+	user.Semester = &semester
+	user.TutorialGroup = tutorialGroup
 
 	return s.UsersRepo.RegisterUser(user)
 }
@@ -69,58 +79,172 @@ func (s *UserService) GetAllUsersAdmin() ([]models.User, error) {
 }
 
 // Get user role
-func (s *UserService) GetRole(uid string) (models.UserRole, error) {
+func (s *UserService) GetRole(uid string) (string, error) {
 	role, err := s.UsersRepo.GetUserRole(uid)
 	if err != nil {
 		utils.Logger.Error().Err(err).Msg("Error getting role by UID")
-		return models.Unknown, err
+		return "", err
 	}
 
-	return models.ParseRole(role)
+	return role, nil
 }
 
-// Get top 10 students with highest karma for given semester
-func (s *UserService) GetTopKarma() ([]models.UserProfile, error) {
-	TOP_N := 10 // Top 10 students
+// Get user's account status. Suspensions which have expired are lifted here, so the returned status is always current.
+func (s *UserService) GetAccountStatus(uid string) (*models.AccountStatus, error) {
+	status, err := s.UsersRepo.GetAccountStatus(uid)
+	if err != nil {
+		return nil, err
+	}
 
-	return s.UsersRepo.GetTopKarma(TOP_N)
+	if status.SuspensionExpired() {
+		utils.Logger.Info().Str("uid", uid).Msg("Suspension has expired, reinstating user")
+		if err := s.Reinstate(uid); err != nil {
+			return nil, err
+		}
+		return s.UsersRepo.GetAccountStatus(uid)
+	}
+
+	return status, nil
 }
 
-// Check if user has staff permission
-func (s *UserService) HasStaffPermission(uid string) (bool, error) {
-	roleStr, err := s.UsersRepo.GetUserRole(uid)
+// Returned when a principal changes the account status of their own account
+var ErrOwnAccount = errors.New("you cannot change the status of your own account")
 
-	if err != nil {
-		utils.Logger.Error().Err(err).Msg("Error getting user role")
-		return false, err
+// Check that p may change the account status of uid. Users cannot change their own status, or the status of users whose
+// role has permissions p does not hold. Returns ErrOwnAccount or ErrRoleNotHeld otherwise.
+func (s *UserService) checkManageable(uid string, p *models.Principal) error {
+	if uid == p.Uid {
+		return ErrOwnAccount
 	}
 
-	role, err := models.ParseRole(roleStr)
+	role, err := s.GetRole(uid)
 	if err != nil {
-		utils.Logger.Error().Err(err).Msg("Error parsing user role")
-		return false, err
+		return err
 	}
 
-	return role >= models.Staff, nil
+	_, err = Roles.HeldBy(p, role)
+	return err
 }
 
-// Check if user has admin permission
-func (s *UserService) HasAdminPermission(uid string) (bool, error) {
-	roleStr, err := s.UsersRepo.GetUserRole(uid)
+// Suspend user. The suspension is lifted automatically after until, or never if until is nil.
+// All sessions of the user are revoked.
+func (s *UserService) Suspend(uid string, reason string, until *time.Time, p *models.Principal) error {
+	if until != nil && until.Before(time.Now()) {
+		return errors.New("suspension end must be in the future")
+	}
+	if err := s.checkManageable(uid, p); err != nil {
+		return err
+	}
 
-	if err != nil {
-		utils.Logger.Error().Err(err).Msg("Error getting user role")
-		return false, err
+	return s.updateStatusAndRevoke(uid, &models.AccountStatus{
+		Status:         models.StatusSuspended,
+		StatusReason:   &reason,
+		SuspendedUntil: until,
+	})
+}
+
+// Ban user permanently. All sessions of the user are revoked.
+func (s *UserService) Ban(uid string, reason string, p *models.Principal) error {
+	if err := s.checkManageable(uid, p); err != nil {
+		return err
+	}
+
+	return s.updateStatusAndRevoke(uid, &models.AccountStatus{
+		Status:       models.StatusBanned,
+		StatusReason: &reason,
+	})
+}
+
+// Deactivate user, e.g. after they have completed the course. All sessions of the user are revoked.
+func (s *UserService) Deactivate(uid string, reason string, p *models.Principal) error {
+	if err := s.checkManageable(uid, p); err != nil {
+		return err
 	}
 
-	role, err := models.ParseRole(roleStr)
+	return s.updateStatusAndRevoke(uid, &models.AccountStatus{
+		Status:       models.StatusDeactivated,
+		StatusReason: &reason,
+	})
+}
+
+// Deactivate all active students of a semester at the end of the semester. Returns number of users deactivated.
+// Sessions are not revoked, as AuthGuard rejects deactivated users on their next request.
+func (s *UserService) DeactivateSemester(semester string, reason string) (int64, error) {
+	return s.UsersRepo.DeactivateSemester(semester, &reason)
+}
+
+// Reinstate a suspended, banned or deactivated user. Removed users cannot be reinstated.
+func (s *UserService) Reinstate(uid string) error {
+	return s.UsersRepo.UpdateAccountStatus(uid, &models.AccountStatus{
+		Status: models.StatusActive,
+	})
+}
+
+// Remove user and erase their personal data. Their content is either anonymised or reassigned to another active user,
+// depending on policy. Sessions and api keys are revoked in the same transaction. Removal cannot be undone.
+func (s *UserService) Remove(uid string, policy string, reassignTo string, reason string, p *models.Principal) error {
+	newAuthor := c.DELETED_USER_UID
+
+	switch policy {
+	case models.RemovalAnonymise:
+	case models.RemovalReassign:
+		if reassignTo == "" || reassignTo == uid {
+			return errors.New("another user to reassign content to is required")
+		}
+		if _, err := s.UsersRepo.GetUserByUid(reassignTo); err != nil {
+			return errors.New("user to reassign content to must be an active user")
+		}
+		newAuthor = reassignTo
+	default:
+		return fmt.Errorf("invalid policy %s", policy)
+	}
+
+	if uid == c.DELETED_USER_UID {
+		return errors.New("placeholder user cannot be removed")
+	}
+	if err := s.checkManageable(uid, p); err != nil {
+		return err
+	}
+
+	return s.UsersRepo.RemoveUser(uid, newAuthor, &reason)
+}
+
+// Export everything stored about a user
+func (s *UserService) Export(uid string) (*models.UserExport, error) {
+	export, err := s.UsersRepo.ExportUser(uid)
 	if err != nil {
-		utils.Logger.Error().Err(err).Msg("Error parsing user role")
-		return false, err
+		return nil, err
+	}
+
+	export.ExportedAt = time.Now()
+	return export, nil
+}
+
+func (s *UserService) updateStatusAndRevoke(uid string, status *models.AccountStatus) error {
+	if err := s.UsersRepo.UpdateAccountStatus(uid, status); err != nil {
+		return err
 	}
 
-	return role >= models.Admin, nil
+	if _, err := Sessions.RevokeAll(uid); err != nil {
+		utils.Logger.Error().Err(err).Str("uid", uid).Msg("Account status updated but sessions could not be revoked")
+		return err
+	}
+
+	return nil
+}
+
+// Get top 10 students with highest karma for given semester
+func (s *UserService) GetTopKarma() ([]models.UserProfile, error) {
+	TOP_N := 10 // Top 10 students
+
+	return s.UsersRepo.GetTopKarma(TOP_N)
+}
+
+// Search active users by name for the mention picker
+func (s *UserService) Search(q string) ([]models.MentionCandidate, error) {
+	MAX_RESULTS := 10
 
+	return s.UsersRepo.Search(strings.TrimSpace(q), MAX_RESULTS)
 }
 
 // Update user's profile photo
@@ -153,11 +277,19 @@ func (s *UserService) UpdateProfilePhoto(uid string, file *multipart.FileHeader)
 	return s.UsersRepo.UpdateProfilePhoto(uid, image)
 }
 
-// Admin: update user's role
-func (s *UserService) UpdateRole(uid string, role string) error {
-	valid_roles := []string{"student", "staff", "admin"}
-	if !slices.Contains(valid_roles, role) {
-		return errors.New("invalid role provided")
+// Admin: update user's role. p must hold every permission of both the new role and the user's current role, so that
+// users cannot be promoted beyond, or demoted from, the access p has.
+func (s *UserService) UpdateRole(uid string, role string, p *models.Principal) error {
+	if _, err := Roles.CheckGrantable(p, role); err != nil {
+		return err
+	}
+
+	current, err := s.GetRole(uid)
+	if err != nil {
+		return err
+	}
+	if _, err := Roles.HeldBy(p, current); err != nil {
+		return err
 	}
 
 	return s.UsersRepo.UpdateUserRole(uid, role)
@@ -0,0 +1,104 @@
+package utils
+
+import "strings"
+
+// Operations of a line in a diff
+const (
+	DiffEqual  = "equal"
+	DiffInsert = "insert"
+	DiffDelete = "delete"
+)
+
+// Diffs of more lines than this (after removing the common prefix and suffix) are not computed line by line, as the
+// table used grows with the product of the line counts. All old lines are shown as deleted and all new lines as inserted.
+const MAX_DIFF_CELLS = 4_000_000
+
+// A line of a diff
+type DiffLine struct {
+	Op   string `json:"op"`
+	Text string `json:"text"`
+}
+
+// Compute a line diff turning a into b, using the longest common subsequence of their lines.
+func LineDiff(a string, b string) []DiffLine {
+	return diffLines(splitLines(a), splitLines(b))
+}
+
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(strings.ReplaceAll(s, "\r\n", "\n"), "\n")
+}
+
+func diffLines(a []string, b []string) []DiffLine {
+	diff := []DiffLine{}
+
+	// Common prefix
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		diff = append(diff, DiffLine{DiffEqual, a[prefix]})
+		prefix++
+	}
+	a, b = a[prefix:], b[prefix:]
+
+	// Common suffix, appended at the end
+	suffix := 0
+	for suffix < len(a) && suffix < len(b) && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+	tail := a[len(a)-suffix:]
+	a, b = a[:len(a)-suffix], b[:len(b)-suffix]
+
+	if len(a)*len(b) > MAX_DIFF_CELLS {
+		for _, line := range a {
+			diff = append(diff, DiffLine{DiffDelete, line})
+		}
+		for _, line := range b {
+			diff = append(diff, DiffLine{DiffInsert, line})
+		}
+	} else {
+		// lcs[i][j] is the length of the longest common subsequence of a[i:] and b[j:]
+		lcs := make([][]int, len(a)+1)
+		for i := range lcs {
+			lcs[i] = make([]int, len(b)+1)
+		}
+		for i := len(a) - 1; i >= 0; i-- {
+			for j := len(b) - 1; j >= 0; j-- {
+				if a[i] == b[j] {
+					lcs[i][j] = lcs[i+1][j+1] + 1
+				} else {
+					lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+				}
+			}
+		}
+
+		i, j := 0, 0
+		for i < len(a) && j < len(b) {
+			switch {
+			case a[i] == b[j]:
+				diff = append(diff, DiffLine{DiffEqual, a[i]})
+				i++
+				j++
+			case lcs[i+1][j] >= lcs[i][j+1]:
+				diff = append(diff, DiffLine{DiffDelete, a[i]})
+				i++
+			default:
+				diff = append(diff, DiffLine{DiffInsert, b[j]})
+				j++
+			}
+		}
+		for ; i < len(a); i++ {
+			diff = append(diff, DiffLine{DiffDelete, a[i]})
+		}
+		for ; j < len(b); j++ {
+			diff = append(diff, DiffLine{DiffInsert, b[j]})
+		}
+	}
+
+	for _, line := range tail {
+		diff = append(diff, DiffLine{DiffEqual, line})
+	}
+
+	return diff
+}
@@ -0,0 +1,57 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+)
+
+// Format a diff as one line per entry, prefixed with " ", "+" or "-"
+func formatDiff(diff []DiffLine) string {
+	prefixes := map[string]string{DiffEqual: " ", DiffInsert: "+", DiffDelete: "-"}
+
+	lines := make([]string, len(diff))
+	for i, line := range diff {
+		lines[i] = prefixes[line.Op] + line.Text
+	}
+	return strings.Join(lines, "\n")
+}
+
+func TestLineDiff(t *testing.T) {
+	tests := []struct {
+		name string
+		a    string
+		b    string
+		want string
+	}{
+		{"both empty", "", "", ""},
+		{"identical", "a\nb", "a\nb", " a\n b"},
+		{"from empty", "", "a\nb", "+a\n+b"},
+		{"to empty", "a\nb", "", "-a\n-b"},
+		{"line changed", "a\nb\nc", "a\nx\nc", " a\n-b\n+x\n c"},
+		{"line inserted", "a\nc", "a\nb\nc", " a\n+b\n c"},
+		{"line deleted", "a\nb\nc", "a\nc", " a\n-b\n c"},
+		{"common lines kept in the middle", "a\nb\nc\nd", "x\nb\nc\ny", "-a\n+x\n b\n c\n-d\n+y"},
+		{"crlf line endings", "a\r\nb", "a\nb", " a\n b"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := formatDiff(LineDiff(tt.a, tt.b)); got != tt.want {
+				t.Errorf("LineDiff(%q, %q) =\n%s\nwant\n%s", tt.a, tt.b, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLineDiffTooLarge(t *testing.T) {
+	var a, b []string
+	for i := 0; i < 2001; i++ {
+		a = append(a, "a")
+		b = append(b, "b")
+	}
+
+	diff := LineDiff("same\n"+strings.Join(a, "\n"), "same\n"+strings.Join(b, "\n"))
+	if len(diff) != 1+2*2001 || diff[0].Op != DiffEqual || diff[1].Op != DiffDelete || diff[len(diff)-1].Op != DiffInsert {
+		t.Errorf("expected common prefix, then all old lines deleted and all new lines inserted")
+	}
+}
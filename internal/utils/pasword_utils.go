@@ -3,6 +3,9 @@ package utils
 
 import (
 	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
 	"math/big"
 	"strings"
 
@@ -37,3 +40,19 @@ func GenerateSalt() []byte {
 
 	return salt
 }
+
+// Generate a random opaque token (e.g. refresh tokens). The token is URL safe.
+func GenerateToken() string {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		Logger.Panic().Err(err).Msg("Error generating random token")
+	}
+
+	return base64.RawURLEncoding.EncodeToString(buf)
+}
+
+// Hash token with SHA-256. Only hashes of tokens should be stored in the database.
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -20,6 +20,9 @@ func SanitizeContent(content string) string {
 	// Original data looks something like this (if we tag Eduvisor): <span class=\"mention\" data-type=\"mention\" data-id=\"Eduvisor\" data-mention-suggestion-char=\"@\">@Eduvisor</span>
 	policy.AllowAttrs("class", "data-type", "data-id").OnElements("span")
 
+	// Preserve the id of the quoted post in quote blocks, e.g. <blockquote class="quote" data-post-id="pAbc123">
+	policy.AllowAttrs("class", "data-post-id").OnElements("blockquote")
+
 	// Add breakspace to newlines
 	content = strings.ReplaceAll(content, "<p></p>", "<p>&nbsp;</p>")
 	return policy.Sanitize(content)
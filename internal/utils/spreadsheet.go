@@ -0,0 +1,256 @@
+package utils
+
+import (
+	"archive/zip"
+	"encoding/csv"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Bytes read from each file inside an XLSX archive once decompressed, so that zip bombs are rejected
+const xlsxMaxEntrySize = 32 << 20 // 32 MiB
+
+// Number of columns of an XLSX worksheet (A to XFD)
+const xlsxMaxColumns = 16384
+
+// Columns of the header row of an XLSX worksheet. Rows are never wider than the header row, so this bounds the memory
+// used by each row regardless of the cell references in the file.
+const xlsxMaxHeaderColumns = 256
+
+// Read all rows of a CSV file. Rows may have different numbers of columns.
+// Returns an error if the file has more than maxRows rows.
+func ReadCSV(r io.Reader, maxRows int) ([][]string, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	var rows [][]string
+	for {
+		row, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if len(rows) == maxRows {
+			return nil, fmt.Errorf("file has more than %d rows", maxRows)
+		}
+		rows = append(rows, row)
+	}
+
+	// Strip byte order mark added by Excel
+	if len(rows) > 0 && len(rows[0]) > 0 {
+		rows[0][0] = strings.TrimPrefix(rows[0][0], "\ufeff")
+	}
+
+	return rows, nil
+}
+
+// Read all rows of the first worksheet of an XLSX file. Only cell values are read; formulas, styles and dates are not
+// interpreted, so dates are returned as serial numbers. Returns an error if the worksheet has more than maxRows rows.
+//
+// The first row is the header row, which may have at most xlsxMaxHeaderColumns columns. Cells of later rows past the last
+// column of the header row are dropped.
+func ReadXLSX(r io.ReaderAt, size int64, maxRows int) ([][]string, error) {
+	archive, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, fmt.Errorf("not a valid xlsx file: %w", err)
+	}
+
+	files := make(map[string]*zip.File, len(archive.File))
+	for _, f := range archive.File {
+		files[f.Name] = f
+	}
+
+	sharedStrings, err := readSharedStrings(files["xl/sharedStrings.xml"])
+	if err != nil {
+		return nil, err
+	}
+
+	sheet := firstWorksheet(files)
+	if sheet == nil {
+		return nil, errors.New("xlsx file has no worksheets")
+	}
+
+	return readWorksheet(sheet, sharedStrings, maxRows)
+}
+
+// Decode XML file inside an XLSX archive into v, reading at most xlsxMaxEntrySize bytes of it
+func decodeXLSXEntry(f *zip.File, v any) error {
+	rc, err := f.Open()
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+
+	limited := &io.LimitedReader{R: rc, N: xlsxMaxEntrySize}
+	if err := xml.NewDecoder(limited).Decode(v); err != nil {
+		if limited.N <= 0 {
+			return fmt.Errorf("%s exceeds %d MB once decompressed", f.Name, xlsxMaxEntrySize/(1024*1024))
+		}
+		return err
+	}
+
+	return nil
+}
+
+type xlsxText struct {
+	T string     `xml:"t"`
+	R []xlsxText `xml:"r"` // Rich text runs
+}
+
+func (t xlsxText) String() string {
+	if len(t.R) == 0 {
+		return t.T
+	}
+
+	var b strings.Builder
+	for _, run := range t.R {
+		b.WriteString(run.T)
+	}
+	return b.String()
+}
+
+func readSharedStrings(f *zip.File) ([]string, error) {
+	// Workbooks with no text cells have no shared strings
+	if f == nil {
+		return nil, nil
+	}
+
+	var sst struct {
+		Items []xlsxText `xml:"si"`
+	}
+	if err := decodeXLSXEntry(f, &sst); err != nil {
+		return nil, fmt.Errorf("error reading shared strings: %w", err)
+	}
+
+	strs := make([]string, len(sst.Items))
+	for i, item := range sst.Items {
+		strs[i] = item.String()
+	}
+	return strs, nil
+}
+
+// Worksheets are named sheet1.xml, sheet2.xml, ... in the order they were created.
+func firstWorksheet(files map[string]*zip.File) *zip.File {
+	if f, ok := files["xl/worksheets/sheet1.xml"]; ok {
+		return f
+	}
+
+	var names []string
+	for name := range files {
+		if path.Dir(name) == "xl/worksheets" && strings.HasSuffix(name, ".xml") {
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		return nil
+	}
+
+	slices.Sort(names)
+	return files[names[0]]
+}
+
+func readWorksheet(f *zip.File, sharedStrings []string, maxRows int) ([][]string, error) {
+	var ws struct {
+		Rows []struct {
+			Cells []struct {
+				Ref    string   `xml:"r,attr"`
+				Type   string   `xml:"t,attr"`
+				Value  string   `xml:"v"`
+				Inline xlsxText `xml:"is"`
+			} `xml:"c"`
+		} `xml:"sheetData>row"`
+	}
+	if err := decodeXLSXEntry(f, &ws); err != nil {
+		return nil, fmt.Errorf("error reading worksheet: %w", err)
+	}
+	if len(ws.Rows) > maxRows {
+		return nil, fmt.Errorf("file has more than %d rows", maxRows)
+	}
+
+	rows := make([][]string, 0, len(ws.Rows))
+	width := xlsxMaxHeaderColumns // Width of the header row once read
+	for r, row := range ws.Rows {
+		var values []string
+
+		for i, cell := range row.Cells {
+			// Empty cells are omitted, so place each cell by its reference (e.g. C4) where available
+			col := i
+			if cell.Ref != "" {
+				var ok bool
+				if col, ok = columnIndex(cell.Ref); !ok {
+					return nil, fmt.Errorf("invalid cell reference %q", cell.Ref)
+				}
+			}
+			if col >= xlsxMaxColumns {
+				return nil, fmt.Errorf("row has more than %d columns", xlsxMaxColumns)
+			}
+
+			var value string
+			switch cell.Type {
+			case "s":
+				idx, err := strconv.Atoi(cell.Value)
+				if err != nil || idx < 0 || idx >= len(sharedStrings) {
+					return nil, fmt.Errorf("invalid shared string in cell %s", cell.Ref)
+				}
+				value = sharedStrings[idx]
+			case "inlineStr":
+				value = cell.Inline.String()
+			default:
+				value = cell.Value
+			}
+
+			// Cells past the header row are not under any column. Empty ones are common, as Excel writes styled cells.
+			if col >= width {
+				if r == 0 && value != "" {
+					return nil, fmt.Errorf("header row has more than %d columns", xlsxMaxHeaderColumns)
+				}
+				continue
+			}
+
+			for len(values) <= col {
+				values = append(values, "")
+			}
+			values[col] = value
+		}
+
+		if r == 0 {
+			width = len(values)
+		}
+		rows = append(rows, values)
+	}
+
+	return rows, nil
+}
+
+// Zero-based column index of a cell reference, e.g. A1 -> 0, AB12 -> 27. Returns false unless ref is one to three
+// letters followed by a row number, within the xlsxMaxColumns columns of a worksheet.
+func columnIndex(ref string) (int, bool) {
+	col, i := 0, 0
+	for ; i < len(ref) && ref[i] >= 'A' && ref[i] <= 'Z'; i++ {
+		if i == 3 {
+			return 0, false
+		}
+		col = col*26 + int(ref[i]-'A'+1)
+	}
+
+	if i == 0 || i == len(ref) || col > xlsxMaxColumns {
+		return 0, false
+	}
+	for _, ch := range ref[i:] {
+		if ch < '0' || ch > '9' {
+			return 0, false
+		}
+	}
+
+	return col - 1, true
+}
@@ -0,0 +1,144 @@
+package utils
+
+import (
+	"archive/zip"
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestColumnIndex(t *testing.T) {
+	tests := []struct {
+		ref  string
+		col  int
+		want bool
+	}{
+		{"A1", 0, true},
+		{"Z9", 25, true},
+		{"AB12", 27, true},
+		{"XFD1048576", 16383, true},
+		{"1", 0, false},
+		{"", 0, false},
+		{"A", 0, false},
+		{"a1", 0, false},
+		{"A1B", 0, false},
+		{"XFE1", 0, false},
+		{"ZZZ1", 0, false},
+		{"AAAA1", 0, false},
+		{"ZZZZZZZZZZZZ1", 0, false},
+	}
+
+	for _, tt := range tests {
+		col, ok := columnIndex(tt.ref)
+		if ok != tt.want || (ok && col != tt.col) {
+			t.Errorf("columnIndex(%q) = %d, %v; want %d, %v", tt.ref, col, ok, tt.col, tt.want)
+		}
+	}
+}
+
+// Build an XLSX file whose first worksheet has the given sheetData
+func buildXLSX(t *testing.T, sheetData string) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	f, err := w.Create("xl/worksheets/sheet1.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.Write([]byte(`<worksheet><sheetData>` + sheetData + `</sheetData></worksheet>`)); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	return buf.Bytes()
+}
+
+func TestReadXLSXCellRefs(t *testing.T) {
+	tests := []struct {
+		name    string
+		cells   string
+		want    []string
+		wantErr bool
+	}{
+		{"placed by ref", `<c r="A1"><v>a</v></c><c r="C1"><v>c</v></c>`, []string{"a", "", "c"}, false},
+		{"no ref", `<c><v>a</v></c><c><v>b</v></c>`, []string{"a", "b"}, false},
+		{"letterless ref", `<c r="1"><v>a</v></c>`, nil, true},
+		{"lowercase ref", `<c r="b1"><v>a</v></c>`, nil, true},
+		{"column beyond XFD", `<c r="XFE1"><v>a</v></c>`, nil, true},
+		{"huge column", `<c r="ZZZZZZZZZZZZZZ1"><v>a</v></c>`, nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := buildXLSX(t, `<row>`+tt.cells+`</row>`)
+
+			rows, err := ReadXLSX(bytes.NewReader(data), int64(len(data)), 10)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected error, got rows %q", rows)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(rows) != 1 || strings.Join(rows[0], ",") != strings.Join(tt.want, ",") {
+				t.Errorf("got %q, want %q", rows, tt.want)
+			}
+		})
+	}
+}
+
+func TestReadXLSXRowLimit(t *testing.T) {
+	data := buildXLSX(t, strings.Repeat(`<row><c r="A1"><v>a</v></c></row>`, 3))
+
+	if _, err := ReadXLSX(bytes.NewReader(data), int64(len(data)), 3); err != nil {
+		t.Errorf("3 rows with limit 3: %v", err)
+	}
+	if _, err := ReadXLSX(bytes.NewReader(data), int64(len(data)), 2); err == nil {
+		t.Error("3 rows with limit 2: expected error")
+	}
+}
+
+func TestReadXLSXRowWidth(t *testing.T) {
+	header := `<row><c r="A1"><v>email</v></c><c r="B1"><v>name</v></c><c r="XFD1" s="1"/></row>`
+	data := buildXLSX(t, header+strings.Repeat(`<row><c r="A2"><v>a</v></c><c r="XFD2"><v>x</v></c></row>`, 5000))
+
+	rows, err := ReadXLSX(bytes.NewReader(data), int64(len(data)), 5001)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, row := range rows {
+		if len(row) > 2 {
+			t.Fatalf("row %d has %d columns, want at most 2", i, len(row))
+		}
+	}
+	if rows[1][0] != "a" {
+		t.Errorf("got %q, want a", rows[1][0])
+	}
+
+	wide := buildXLSX(t, `<row><c r="A1"><v>email</v></c><c r="XFD1"><v>x</v></c></row>`)
+	if _, err := ReadXLSX(bytes.NewReader(wide), int64(len(wide)), 10); err == nil {
+		t.Error("header with a value at XFD: expected error")
+	}
+}
+
+func TestReadXLSXEntrySizeLimit(t *testing.T) {
+	data := buildXLSX(t, `<row><c r="A1"><v>`+strings.Repeat("a", xlsxMaxEntrySize)+`</v></c></row>`)
+
+	if _, err := ReadXLSX(bytes.NewReader(data), int64(len(data)), 10); err == nil || !strings.Contains(err.Error(), "decompressed") {
+		t.Errorf("expected decompressed size error, got %v", err)
+	}
+}
+
+func TestReadCSVRowLimit(t *testing.T) {
+	if _, err := ReadCSV(strings.NewReader("a\nb\nc\n"), 3); err != nil {
+		t.Errorf("3 rows with limit 3: %v", err)
+	}
+	if _, err := ReadCSV(strings.NewReader("a\nb\nc\n"), 2); err == nil {
+		t.Error("3 rows with limit 2: expected error")
+	}
+}
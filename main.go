@@ -3,12 +3,16 @@ package main
 import (
 	"fmt"
 	"net/http"
+	"os"
+	"strings"
 	"time"
 
 	"github.com/ntu-onemdp/onemdp-backend/internal/api/middlewares"
 	"github.com/ntu-onemdp/onemdp-backend/internal/db"
 	"github.com/ntu-onemdp/onemdp-backend/internal/karma"
+	"github.com/ntu-onemdp/onemdp-backend/internal/limiter"
 	"github.com/ntu-onemdp/onemdp-backend/internal/models"
+	"github.com/ntu-onemdp/onemdp-backend/internal/notification"
 	"github.com/ntu-onemdp/onemdp-backend/internal/repositories"
 	"github.com/ntu-onemdp/onemdp-backend/internal/semester"
 	"github.com/ntu-onemdp/onemdp-backend/internal/services"
@@ -28,6 +32,18 @@ func main() {
 
 	r := gin.Default()
 
+	// Only trust X-Forwarded-For from the configured proxies, so that clients cannot pick the ip used for lockouts.
+	// No proxies are trusted if TRUSTED_PROXIES is empty.
+	var trustedProxies []string
+	for _, proxy := range strings.Split(os.Getenv("TRUSTED_PROXIES"), ",") {
+		if proxy = strings.TrimSpace(proxy); proxy != "" {
+			trustedProxies = append(trustedProxies, proxy)
+		}
+	}
+	if err := r.SetTrustedProxies(trustedProxies); err != nil {
+		utils.Logger.Fatal().Err(err).Msg("Invalid TRUSTED_PROXIES")
+	}
+
 	// Reduce max memory limit for multipart form data
 	r.MaxMultipartMemory = 4 << 20 // 4 MiB
 
@@ -40,59 +56,113 @@ func main() {
 	services.Init()
 	semester.Init(db.Pool)
 	karma.Init(db.Pool)
+	notification.Init(db.Pool)
+
+	// Initialize login and registration attempt limiter
+	limiter.Init()
+
+	// Delete drafts which have not been edited for a long time
+	services.Drafts.StartCleanup()
+	services.Trash.StartCleanup()
+
+	// Initialize SSO token verifier
+	services.Sso = services.NewSsoVerifier()
 
 	// Initialize eduvisor service
 	services.Eduvisor = services.NewEduvisorService()
 
+	// Register public key set for jwt verification
+	wellKnownRoutes := r.Group("/.well-known")
+	routes.RegisterWellKnownRoutes(wellKnownRoutes)
+
 	// Register auth routes
 	authRoutes := r.Group("/api/v1/auth")
 	routes.RegisterAuthRoutes(authRoutes)
 
 	// Register student routes
-	studentRoutes := r.Group("/api/v1/users", middlewares.AuthGuard(models.Student))
+	studentRoutes := r.Group("/api/v1/users", middlewares.AuthGuard())
 	routes.RegisterStudentUserRoutes(studentRoutes)
 
 	// Register thread routes
-	threadRoutes := r.Group("/api/v1/threads", middlewares.AuthGuard(models.Student))
+	threadRoutes := r.Group("/api/v1/threads", middlewares.AuthGuard())
 	routes.RegisterThreadRoutes(threadRoutes)
 
+	// Register tag routes
+	tagRoutes := r.Group("/api/v1/tags", middlewares.AuthGuard())
+	routes.RegisterTagRoutes(tagRoutes)
+
+	// Register notification routes
+	notificationRoutes := r.Group("/api/v1/notifications", middlewares.AuthGuard())
+	routes.RegisterNotificationRoutes(notificationRoutes)
+
 	// Register post routes
-	postRoutes := r.Group("/api/v1/posts", middlewares.AuthGuard(models.Student))
+	postRoutes := r.Group("/api/v1/posts", middlewares.AuthGuard())
 	routes.RegisterPostRoutes(postRoutes)
 
 	// Register article routes
-	articleRoutes := r.Group("/api/v1/articles", middlewares.AuthGuard(models.Student))
+	articleRoutes := r.Group("/api/v1/articles", middlewares.AuthGuard())
 	routes.RegisterArticleRoutes(articleRoutes)
 
 	// Register comment routes
-	commentRoutes := r.Group("/api/v1/comments", middlewares.AuthGuard(models.Student))
+	commentRoutes := r.Group("/api/v1/comments", middlewares.AuthGuard())
 	routes.RegisterCommentRoutes(commentRoutes)
 
+	// Register draft routes
+	draftRoutes := r.Group("/api/v1/drafts", middlewares.AuthGuard())
+	routes.RegisterDraftRoutes(draftRoutes)
+
 	// Register image routes
-	imageRoutes := r.Group("/api/v1/images", middlewares.AuthGuard(models.Student))
+	imageRoutes := r.Group("/api/v1/images", middlewares.AuthGuard())
 	routes.RegisterImageRoutes(imageRoutes)
 
 	// Register like content routes
-	likeRoutes := r.Group("/api/v1/like", middlewares.AuthGuard(models.Student))
+	likeRoutes := r.Group("/api/v1/like", middlewares.AuthGuard())
 	routes.RegisterLikeRoutes(likeRoutes)
 
 	// Register favorite content routes
-	favoriteRoutes := r.Group("/api/v1/saved", middlewares.AuthGuard(models.Student))
+	favoriteRoutes := r.Group("/api/v1/saved", middlewares.AuthGuard())
 	routes.RegisterSavedRoutes(favoriteRoutes)
 
 	// Register student file routes
-	fileRoutes := r.Group("/api/v1/files", middlewares.AuthGuard(models.Student))
+	fileRoutes := r.Group("/api/v1/files", middlewares.AuthGuard())
 	routes.RegisterFileRoutes(fileRoutes)
 
 	// Register staff file routes
-	staffFileRoutes := r.Group("/api/v1/staff/files", middlewares.AuthGuard(models.Staff))
+	staffFileRoutes := r.Group("/api/v1/staff/files", middlewares.AuthGuard(models.PermFilesManage))
 	routes.RegisterFileMgmtRoutes(staffFileRoutes)
 
-	// Register admin routes
-	adminRoutes := r.Group("/api/v1/admin", middlewares.AuthGuard(models.Admin))
-	routes.RegisterAdminUserRoutes(adminRoutes)
-	karmaRoutes := adminRoutes.Group("/karma")
+	// Register staff thread routes
+	staffThreadRoutes := r.Group("/api/v1/staff/threads", middlewares.AuthGuard(models.PermContentModerate))
+	routes.RegisterThreadMgmtRoutes(staffThreadRoutes)
+
+	// Register staff post routes
+	staffPostRoutes := r.Group("/api/v1/staff/posts", middlewares.AuthGuard(models.PermContentModerate))
+	routes.RegisterPostMgmtRoutes(staffPostRoutes)
+
+	// Register staff endorsement routes
+	endorsementRoutes := r.Group("/api/v1/staff/endorsements", middlewares.AuthGuard(models.PermContentEndorse))
+	routes.RegisterEndorsementRoutes(endorsementRoutes)
+
+	// Register staff trash routes
+	staffTrashRoutes := r.Group("/api/v1/staff/trash", middlewares.AuthGuard(models.PermContentHide))
+	routes.RegisterTrashRoutes(staffTrashRoutes)
+
+	// Register staff tag routes
+	staffTagRoutes := r.Group("/api/v1/staff/tags", middlewares.AuthGuard(models.PermContentModerate))
+	routes.RegisterTagMgmtRoutes(staffTagRoutes)
+
+	// Register admin routes. Each group is guarded by the permission it needs.
+	adminRoutes := r.Group("/api/v1/admin")
+	adminUserRoutes := adminRoutes.Group("", middlewares.AuthGuard(models.PermUsersManage))
+	routes.RegisterAdminUserRoutes(adminUserRoutes)
+	apiKeyRoutes := adminRoutes.Group("/api-keys", middlewares.AuthGuard(models.PermApiKeysManage))
+	routes.RegisterAdminApiKeyRoutes(apiKeyRoutes)
+	roleRoutes := adminRoutes.Group("/roles", middlewares.AuthGuard(models.PermRolesManage))
+	routes.RegisterAdminRoleRoutes(roleRoutes)
+	karmaRoutes := adminRoutes.Group("/karma", middlewares.AuthGuard(models.PermKarmaConfigure))
 	routes.RegisterAdminKarmaRoutes(karmaRoutes)
+	enrolmentRoutes := adminRoutes.Group("/enrolment-codes", middlewares.AuthGuard(models.PermEnrolmentManage))
+	routes.RegisterAdminEnrolmentRoutes(enrolmentRoutes)
 
 	utils.Logger.Warn().Msg("/ping routes are active. Remove them for production")
 
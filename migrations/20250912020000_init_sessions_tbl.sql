@@ -0,0 +1,24 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS public.sessions (
+    session_id text NOT NULL,
+    uid text NOT NULL,
+    token_hash text NOT NULL,
+    user_agent text NOT NULL DEFAULT '',
+    time_created timestamp with time zone NOT NULL DEFAULT now(),
+    last_used timestamp with time zone NOT NULL DEFAULT now(),
+    expires_at timestamp with time zone NOT NULL,
+    revoked_at timestamp with time zone,
+    PRIMARY KEY (session_id),
+    CONSTRAINT sessions_token_hash_key UNIQUE (token_hash),
+    CONSTRAINT fk_sessions_uid_users_uid FOREIGN KEY (uid) REFERENCES public.users (uid) MATCH SIMPLE ON UPDATE CASCADE ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS sessions_uid_idx ON public.sessions (uid);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS sessions_uid_idx;
+DROP TABLE IF EXISTS public.sessions;
+-- +goose StatementEnd
@@ -0,0 +1,23 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS public.api_keys (
+    key_id text NOT NULL,
+    key_hash text NOT NULL,
+    name text NOT NULL,
+    owner_uid text NOT NULL,
+    max_role text NOT NULL DEFAULT 'student',
+    scopes text[] NOT NULL DEFAULT '{}',
+    time_created timestamp with time zone NOT NULL DEFAULT now(),
+    expires_at timestamp with time zone,
+    last_used timestamp with time zone,
+    revoked_at timestamp with time zone,
+    PRIMARY KEY (key_id),
+    CONSTRAINT api_keys_key_hash_key UNIQUE (key_hash),
+    CONSTRAINT fk_api_keys_owner_uid_users_uid FOREIGN KEY (owner_uid) REFERENCES public.users (uid) MATCH SIMPLE ON UPDATE CASCADE ON DELETE CASCADE
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS public.api_keys;
+-- +goose StatementEnd
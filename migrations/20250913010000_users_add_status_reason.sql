@@ -0,0 +1,13 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE public.users
+    ADD COLUMN IF NOT EXISTS status_reason text,
+    ADD COLUMN IF NOT EXISTS suspended_until timestamp with time zone;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE public.users
+    DROP COLUMN IF EXISTS status_reason,
+    DROP COLUMN IF EXISTS suspended_until;
+-- +goose StatementEnd
@@ -0,0 +1,57 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS public.enrolment_codes (
+    code_id text NOT NULL,
+    code text NOT NULL,
+    semester text NOT NULL,
+    role text NOT NULL DEFAULT 'student',
+    tutorial_group text,
+    max_uses integer,
+    num_uses integer NOT NULL DEFAULT 0,
+    expires_at timestamp with time zone,
+    is_default boolean NOT NULL DEFAULT FALSE,
+    disabled boolean NOT NULL DEFAULT FALSE,
+    created_by text,
+    time_created timestamp with time zone NOT NULL DEFAULT now(),
+    PRIMARY KEY (code_id),
+    CONSTRAINT enrolment_codes_code_key UNIQUE (code),
+    CONSTRAINT enrolment_codes_num_uses_check CHECK (num_uses >= 0 AND (max_uses IS NULL OR num_uses <= max_uses)),
+    CONSTRAINT fk_enrolment_codes_semester_semesters_semester FOREIGN KEY (semester) REFERENCES public.semesters (semester) MATCH SIMPLE ON UPDATE CASCADE ON DELETE CASCADE,
+    CONSTRAINT fk_enrolment_codes_created_by_users_uid FOREIGN KEY (created_by) REFERENCES public.users (uid) MATCH SIMPLE ON UPDATE CASCADE ON DELETE SET NULL
+);
+
+-- Only one default code per semester. The default code is returned by GET /auth/enrolment-code.
+CREATE UNIQUE INDEX IF NOT EXISTS unique_default_enrolment_code ON public.enrolment_codes (semester)
+WHERE is_default;
+
+CREATE TABLE IF NOT EXISTS public.enrolment_code_redemptions (
+    code_id text NOT NULL,
+    uid text NOT NULL,
+    time_redeemed timestamp with time zone NOT NULL DEFAULT now(),
+    PRIMARY KEY (code_id, uid),
+    CONSTRAINT fk_redemptions_code_id_enrolment_codes_code_id FOREIGN KEY (code_id) REFERENCES public.enrolment_codes (code_id) MATCH SIMPLE ON UPDATE CASCADE ON DELETE CASCADE,
+    CONSTRAINT fk_redemptions_uid_users_uid FOREIGN KEY (uid) REFERENCES public.users (uid) MATCH SIMPLE ON UPDATE CASCADE ON DELETE CASCADE
+);
+
+-- Move existing semester codes into enrolment_codes as the default code of each semester
+INSERT INTO public.enrolment_codes (code_id, code, semester, is_default)
+SELECT 'e' || substr(md5(semester), 1, 8), code, semester, TRUE FROM public.semesters;
+
+ALTER TABLE public.semesters DROP COLUMN IF EXISTS code;
+
+ALTER TABLE public.users ADD COLUMN IF NOT EXISTS tutorial_group text;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE public.users DROP COLUMN IF EXISTS tutorial_group;
+
+ALTER TABLE public.semesters ADD COLUMN IF NOT EXISTS code text;
+UPDATE public.semesters s SET code = e.code FROM public.enrolment_codes e WHERE e.semester = s.semester AND e.is_default;
+UPDATE public.semesters SET code = '' WHERE code IS NULL;
+ALTER TABLE public.semesters ALTER COLUMN code SET NOT NULL;
+
+DROP TABLE IF EXISTS public.enrolment_code_redemptions;
+DROP INDEX IF EXISTS unique_default_enrolment_code;
+DROP TABLE IF EXISTS public.enrolment_codes;
+-- +goose StatementEnd
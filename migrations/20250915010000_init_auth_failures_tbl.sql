@@ -0,0 +1,21 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS public.auth_failures (
+    failure_id bigserial NOT NULL,
+    endpoint text NOT NULL,
+    ip text NOT NULL,
+    email text,
+    reason text NOT NULL,
+    user_agent text NOT NULL DEFAULT '',
+    time_created timestamp with time zone NOT NULL DEFAULT now(),
+    PRIMARY KEY (failure_id)
+);
+
+CREATE INDEX IF NOT EXISTS auth_failures_time_created_idx ON public.auth_failures (time_created DESC);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS auth_failures_time_created_idx;
+DROP TABLE IF EXISTS public.auth_failures;
+-- +goose StatementEnd
@@ -0,0 +1,19 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE public.pending_users
+    ADD COLUMN IF NOT EXISTS name text,
+    ADD COLUMN IF NOT EXISTS tutorial_group text;
+
+-- Emails from SSO are lowercase. Normalise pending emails so that they can be matched, skipping any that would collide.
+UPDATE public.pending_users p
+SET email = lower(p.email)
+WHERE p.email <> lower(p.email)
+  AND NOT EXISTS (SELECT 1 FROM public.pending_users q WHERE q.email = lower(p.email));
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE public.pending_users
+    DROP COLUMN IF EXISTS name,
+    DROP COLUMN IF EXISTS tutorial_group;
+-- +goose StatementEnd
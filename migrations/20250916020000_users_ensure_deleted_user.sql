@@ -0,0 +1,30 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Content of removed users is reassigned to this placeholder user. Author columns default to it, so it must exist.
+INSERT INTO public.users (uid, name, email, role, status, date_removed)
+VALUES ('(deleted)', '[deleted user]', 'N.A.', 'deleted', 'removed', now())
+ON CONFLICT (uid) DO UPDATE
+SET name = EXCLUDED.name, role = EXCLUDED.role, status = EXCLUDED.status;
+
+ALTER TABLE public.posts
+ALTER COLUMN author
+SET DEFAULT '(deleted)';
+ALTER TABLE public.comments
+ALTER COLUMN author
+SET DEFAULT '(deleted)';
+ALTER TABLE public.articles
+ALTER COLUMN author
+SET DEFAULT '(deleted)';
+ALTER TABLE public.threads
+ALTER COLUMN author
+SET DEFAULT '(deleted)';
+ALTER TABLE public.files
+ALTER COLUMN author
+SET DEFAULT '(deleted)';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+-- Placeholder user is kept as content may reference it
+UPDATE public.users SET status = 'active' WHERE uid = '(deleted)';
+-- +goose StatementEnd
@@ -0,0 +1,81 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS public.roles (
+    role text NOT NULL,
+    description text NOT NULL DEFAULT '',
+    is_system boolean NOT NULL DEFAULT false, -- Built-in roles cannot be deleted
+    time_created timestamp with time zone NOT NULL DEFAULT now(),
+    PRIMARY KEY (role)
+);
+
+CREATE TABLE IF NOT EXISTS public.role_permissions (
+    role text NOT NULL,
+    permission text NOT NULL,
+    PRIMARY KEY (role, permission),
+    CONSTRAINT fk_role_permissions_role_roles_role FOREIGN KEY (role) REFERENCES public.roles (role) MATCH SIMPLE ON UPDATE CASCADE ON DELETE CASCADE
+);
+
+INSERT INTO public.roles (role, description, is_system) VALUES
+    ('student', 'Can read and post content', true),
+    ('bot', 'Automated users such as Eduvisor', true),
+    ('moderator', 'Student moderator who can hide content', true),
+    ('ta', 'Teaching assistant who can moderate content', true),
+    ('staff', 'Course staff who can moderate content and manage files', true),
+    ('admin', 'Full access', true),
+    ('deleted', 'Placeholder user of removed content', true)
+ON CONFLICT (role) DO NOTHING;
+
+INSERT INTO public.role_permissions (role, permission) VALUES
+    ('moderator', 'content.hide'),
+    ('ta', 'content.hide'),
+    ('ta', 'content.moderate'),
+    ('ta', 'enrolment.view'),
+    ('staff', 'content.hide'),
+    ('staff', 'content.moderate'),
+    ('staff', 'files.manage'),
+    ('staff', 'enrolment.view'),
+    ('admin', 'content.hide'),
+    ('admin', 'content.moderate'),
+    ('admin', 'files.manage'),
+    ('admin', 'karma.configure'),
+    ('admin', 'users.manage'),
+    ('admin', 'enrolment.view'),
+    ('admin', 'enrolment.manage'),
+    ('admin', 'api_keys.manage'),
+    ('admin', 'roles.manage')
+ON CONFLICT DO NOTHING;
+
+-- Roles of users, enrolment codes and api keys must exist
+ALTER TABLE public.users
+ADD CONSTRAINT fk_users_role_roles_role
+FOREIGN KEY (role)
+REFERENCES public.roles(role)
+ON UPDATE CASCADE
+ON DELETE RESTRICT
+NOT VALID;
+
+ALTER TABLE public.enrolment_codes
+ADD CONSTRAINT fk_enrolment_codes_role_roles_role
+FOREIGN KEY (role)
+REFERENCES public.roles(role)
+ON UPDATE CASCADE
+ON DELETE RESTRICT
+NOT VALID;
+
+ALTER TABLE public.api_keys
+ADD CONSTRAINT fk_api_keys_max_role_roles_role
+FOREIGN KEY (max_role)
+REFERENCES public.roles(role)
+ON UPDATE CASCADE
+ON DELETE RESTRICT
+NOT VALID;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE public.api_keys DROP CONSTRAINT IF EXISTS fk_api_keys_max_role_roles_role;
+ALTER TABLE public.enrolment_codes DROP CONSTRAINT IF EXISTS fk_enrolment_codes_role_roles_role;
+ALTER TABLE public.users DROP CONSTRAINT IF EXISTS fk_users_role_roles_role;
+DROP TABLE IF EXISTS public.role_permissions;
+DROP TABLE IF EXISTS public.roles;
+-- +goose StatementEnd
@@ -0,0 +1,26 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS public.impersonation_audit (
+    audit_id bigserial NOT NULL,
+    impersonator_uid text NOT NULL,
+    target_uid text NOT NULL,
+    session_id text NOT NULL, -- Session of the impersonator
+    method text NOT NULL,     -- HTTP method, or START when the token is issued
+    path text NOT NULL,
+    status integer NOT NULL,
+    ip text NOT NULL,
+    reason text,              -- Only set when the token is issued
+    time_created timestamp with time zone NOT NULL DEFAULT now(),
+    PRIMARY KEY (audit_id),
+    CONSTRAINT fk_impersonation_audit_impersonator_uid_users_uid FOREIGN KEY (impersonator_uid) REFERENCES public.users (uid) MATCH SIMPLE ON UPDATE CASCADE ON DELETE NO ACTION,
+    CONSTRAINT fk_impersonation_audit_target_uid_users_uid FOREIGN KEY (target_uid) REFERENCES public.users (uid) MATCH SIMPLE ON UPDATE CASCADE ON DELETE NO ACTION
+);
+
+CREATE INDEX IF NOT EXISTS impersonation_audit_time_created_idx ON public.impersonation_audit (time_created DESC);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS impersonation_audit_time_created_idx;
+DROP TABLE IF EXISTS public.impersonation_audit;
+-- +goose StatementEnd
@@ -0,0 +1,24 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE public.drafts
+    ADD COLUMN IF NOT EXISTS target_type text NOT NULL DEFAULT 'thread',
+    ADD COLUMN IF NOT EXISTS target_id text,  -- Parent of the content: thread_id for post drafts, article_id for comment drafts
+    ADD COLUMN IF NOT EXISTS reply_to text,   -- Post being replied to. Post drafts only.
+    ADD COLUMN IF NOT EXISTS is_anon boolean NOT NULL DEFAULT false,
+    ADD CONSTRAINT drafts_target_type_check CHECK (target_type IN ('thread', 'post', 'article', 'comment'));
+
+CREATE INDEX IF NOT EXISTS drafts_author_last_edited_idx ON public.drafts (author, last_edited DESC);
+CREATE INDEX IF NOT EXISTS drafts_last_edited_idx ON public.drafts (last_edited);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS drafts_last_edited_idx;
+DROP INDEX IF EXISTS drafts_author_last_edited_idx;
+ALTER TABLE public.drafts
+    DROP CONSTRAINT IF EXISTS drafts_target_type_check,
+    DROP COLUMN IF EXISTS is_anon,
+    DROP COLUMN IF EXISTS reply_to,
+    DROP COLUMN IF EXISTS target_id,
+    DROP COLUMN IF EXISTS target_type;
+-- +goose StatementEnd
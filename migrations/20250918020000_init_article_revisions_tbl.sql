@@ -0,0 +1,20 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Previous versions of articles. The current version is kept in the articles table.
+CREATE TABLE IF NOT EXISTS public.article_revisions (
+    article_id text NOT NULL,
+    revision integer NOT NULL,    -- Version number, starting from 1 for the version the article was created with
+    title text NOT NULL,
+    content text NOT NULL,
+    replaced_by text NOT NULL,    -- User whose edit or rollback replaced this version
+    time_replaced timestamp with time zone NOT NULL DEFAULT now(),
+    PRIMARY KEY (article_id, revision),
+    CONSTRAINT fk_article_revisions_article_id_articles_article_id FOREIGN KEY (article_id) REFERENCES public.articles (article_id) MATCH SIMPLE ON UPDATE CASCADE ON DELETE CASCADE,
+    CONSTRAINT fk_article_revisions_replaced_by_users_uid FOREIGN KEY (replaced_by) REFERENCES public.users (uid) MATCH SIMPLE ON UPDATE CASCADE ON DELETE NO ACTION
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS public.article_revisions;
+-- +goose StatementEnd
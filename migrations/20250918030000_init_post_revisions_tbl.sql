@@ -0,0 +1,23 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE public.posts ADD COLUMN IF NOT EXISTS num_edits integer NOT NULL DEFAULT 0;
+
+-- Previous versions of posts. The current version is kept in the posts table.
+CREATE TABLE IF NOT EXISTS public.post_revisions (
+    post_id text NOT NULL,
+    revision integer NOT NULL,    -- Version number, starting from 1 for the version the post was created with
+    title text NOT NULL,
+    content text NOT NULL,
+    replaced_by text NOT NULL,    -- Editor whose edit replaced this version. May be staff rather than the author.
+    time_replaced timestamp with time zone NOT NULL DEFAULT now(),
+    PRIMARY KEY (post_id, revision),
+    CONSTRAINT fk_post_revisions_post_id_posts_post_id FOREIGN KEY (post_id) REFERENCES public.posts (post_id) MATCH SIMPLE ON UPDATE CASCADE ON DELETE CASCADE,
+    CONSTRAINT fk_post_revisions_replaced_by_users_uid FOREIGN KEY (replaced_by) REFERENCES public.users (uid) MATCH SIMPLE ON UPDATE CASCADE ON DELETE NO ACTION
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS public.post_revisions;
+ALTER TABLE public.posts DROP COLUMN IF EXISTS num_edits;
+-- +goose StatementEnd
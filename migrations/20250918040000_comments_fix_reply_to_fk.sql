@@ -0,0 +1,36 @@
+-- +goose Up
+-- +goose StatementBegin
+-- reply_to holds the parent comment, but was created with a foreign key to users
+ALTER TABLE public.comments
+DROP CONSTRAINT IF EXISTS fk_articles_reply_users_uid;
+
+UPDATE public.comments
+SET reply_to = NULL
+WHERE reply_to IS NOT NULL
+AND reply_to NOT IN (SELECT comment_id FROM public.comments);
+
+ALTER TABLE public.comments
+ADD CONSTRAINT fk_comments_reply_to_comments_comment_id
+FOREIGN KEY (reply_to)
+REFERENCES public.comments(comment_id)
+ON UPDATE CASCADE
+ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS comments_reply_to_idx ON public.comments (reply_to);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS comments_reply_to_idx;
+
+ALTER TABLE public.comments
+DROP CONSTRAINT IF EXISTS fk_comments_reply_to_comments_comment_id;
+
+ALTER TABLE public.comments
+ADD CONSTRAINT fk_articles_reply_users_uid
+FOREIGN KEY (reply_to)
+REFERENCES public.users(uid)
+ON UPDATE CASCADE
+ON DELETE SET DEFAULT
+NOT VALID;
+-- +goose StatementEnd
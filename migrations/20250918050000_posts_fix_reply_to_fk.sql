@@ -0,0 +1,36 @@
+-- +goose Up
+-- +goose StatementBegin
+-- reply_to holds the post being replied to, but was created with a foreign key to users
+ALTER TABLE public.posts
+DROP CONSTRAINT IF EXISTS fk_posts_reply_users_uid;
+
+UPDATE public.posts
+SET reply_to = NULL
+WHERE reply_to IS NOT NULL
+AND reply_to NOT IN (SELECT post_id FROM public.posts);
+
+ALTER TABLE public.posts
+ADD CONSTRAINT fk_posts_reply_to_posts_post_id
+FOREIGN KEY (reply_to)
+REFERENCES public.posts(post_id)
+ON UPDATE CASCADE
+ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS posts_reply_to_idx ON public.posts (reply_to);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS posts_reply_to_idx;
+
+ALTER TABLE public.posts
+DROP CONSTRAINT IF EXISTS fk_posts_reply_to_posts_post_id;
+
+ALTER TABLE public.posts
+ADD CONSTRAINT fk_posts_reply_users_uid
+FOREIGN KEY (reply_to)
+REFERENCES public.users(uid)
+ON UPDATE CASCADE
+ON DELETE SET NULL
+NOT VALID;
+-- +goose StatementEnd
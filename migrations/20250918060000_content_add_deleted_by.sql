@@ -0,0 +1,48 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Record who deleted content and when, for the staff trash bin and the retention job.
+-- karma_taken is the karma actually taken from the author on deletion (less than the full amount if their karma reached
+-- 0), so that restoring gives back exactly that. It is 0 for content deleted before this migration.
+ALTER TABLE public.threads
+    ADD COLUMN IF NOT EXISTS deleted_by text,
+    ADD COLUMN IF NOT EXISTS time_deleted timestamp with time zone,
+    ADD COLUMN IF NOT EXISTS karma_taken integer NOT NULL DEFAULT 0;
+
+ALTER TABLE public.posts
+    ADD COLUMN IF NOT EXISTS deleted_by text,
+    ADD COLUMN IF NOT EXISTS time_deleted timestamp with time zone,
+    ADD COLUMN IF NOT EXISTS karma_taken integer NOT NULL DEFAULT 0;
+
+ALTER TABLE public.articles
+    ADD COLUMN IF NOT EXISTS deleted_by text,
+    ADD COLUMN IF NOT EXISTS time_deleted timestamp with time zone,
+    ADD COLUMN IF NOT EXISTS karma_taken integer NOT NULL DEFAULT 0;
+
+ALTER TABLE public.comments
+    ADD COLUMN IF NOT EXISTS deleted_by text,
+    ADD COLUMN IF NOT EXISTS time_deleted timestamp with time zone;
+
+-- Content deleted before this migration: use the time the deletion last touched
+UPDATE public.threads SET time_deleted = last_activity WHERE is_available = false AND time_deleted IS NULL;
+UPDATE public.posts SET time_deleted = last_edited WHERE is_available = false AND time_deleted IS NULL;
+UPDATE public.articles SET time_deleted = last_activity WHERE is_available = false AND time_deleted IS NULL;
+UPDATE public.comments SET time_deleted = last_edited WHERE is_available = false AND time_deleted IS NULL;
+
+CREATE INDEX IF NOT EXISTS threads_time_deleted_idx ON public.threads (time_deleted) WHERE is_available = false;
+CREATE INDEX IF NOT EXISTS posts_time_deleted_idx ON public.posts (time_deleted) WHERE is_available = false;
+CREATE INDEX IF NOT EXISTS articles_time_deleted_idx ON public.articles (time_deleted) WHERE is_available = false;
+CREATE INDEX IF NOT EXISTS comments_time_deleted_idx ON public.comments (time_deleted) WHERE is_available = false;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS comments_time_deleted_idx;
+DROP INDEX IF EXISTS articles_time_deleted_idx;
+DROP INDEX IF EXISTS posts_time_deleted_idx;
+DROP INDEX IF EXISTS threads_time_deleted_idx;
+
+ALTER TABLE public.comments DROP COLUMN IF EXISTS time_deleted, DROP COLUMN IF EXISTS deleted_by;
+ALTER TABLE public.articles DROP COLUMN IF EXISTS karma_taken, DROP COLUMN IF EXISTS time_deleted, DROP COLUMN IF EXISTS deleted_by;
+ALTER TABLE public.posts DROP COLUMN IF EXISTS karma_taken, DROP COLUMN IF EXISTS time_deleted, DROP COLUMN IF EXISTS deleted_by;
+ALTER TABLE public.threads DROP COLUMN IF EXISTS karma_taken, DROP COLUMN IF EXISTS time_deleted, DROP COLUMN IF EXISTS deleted_by;
+-- +goose StatementEnd
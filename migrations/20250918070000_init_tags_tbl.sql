@@ -0,0 +1,30 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Staff-managed tags, e.g. weekly topics, which threads can be labelled with
+CREATE TABLE IF NOT EXISTS public.tags (
+    tag_id text NOT NULL PRIMARY KEY,
+    name text NOT NULL,
+    colour text NOT NULL DEFAULT '#808080', -- Hex colour, e.g. #1e90ff
+    description text NOT NULL DEFAULT '',
+    time_created timestamp with time zone NOT NULL DEFAULT now()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_tags_name ON public.tags (lower(name));
+
+CREATE TABLE IF NOT EXISTS public.thread_tags (
+    thread_id text NOT NULL,
+    tag_id text NOT NULL,
+    PRIMARY KEY (thread_id, tag_id),
+    CONSTRAINT fk_thread_tags_thread_id_threads_thread_id FOREIGN KEY (thread_id) REFERENCES public.threads (thread_id) MATCH SIMPLE ON UPDATE CASCADE ON DELETE CASCADE,
+    CONSTRAINT fk_thread_tags_tag_id_tags_tag_id FOREIGN KEY (tag_id) REFERENCES public.tags (tag_id) MATCH SIMPLE ON UPDATE CASCADE ON DELETE CASCADE
+);
+
+-- Filtering threads by tag
+CREATE INDEX IF NOT EXISTS idx_thread_tags_tag_id ON public.thread_tags (tag_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS public.thread_tags;
+DROP TABLE IF EXISTS public.tags;
+-- +goose StatementEnd
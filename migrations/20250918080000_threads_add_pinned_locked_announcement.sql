@@ -0,0 +1,15 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE public.threads
+    ADD COLUMN IF NOT EXISTS pinned boolean NOT NULL DEFAULT false,       -- Listed before all other threads
+    ADD COLUMN IF NOT EXISTS locked boolean NOT NULL DEFAULT false,       -- No new posts allowed
+    ADD COLUMN IF NOT EXISTS announcement boolean NOT NULL DEFAULT false; -- Pushed to the notification feed of all users
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE public.threads
+    DROP COLUMN IF EXISTS pinned,
+    DROP COLUMN IF EXISTS locked,
+    DROP COLUMN IF EXISTS announcement;
+-- +goose StatementEnd
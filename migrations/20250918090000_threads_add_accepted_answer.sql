@@ -0,0 +1,30 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE public.threads
+    ADD COLUMN IF NOT EXISTS accepted_post text,                       -- Post accepted as the answer. Thread is resolved if set.
+    ADD COLUMN IF NOT EXISTS accepted_by text,
+    ADD COLUMN IF NOT EXISTS time_accepted timestamp with time zone,
+    ADD COLUMN IF NOT EXISTS accepted_karma integer NOT NULL DEFAULT 0, -- Karma given to the author of the accepted post, taken back if it is replaced
+    ADD CONSTRAINT fk_threads_accepted_post_posts_post_id FOREIGN KEY (accepted_post) REFERENCES public.posts (post_id) MATCH SIMPLE ON UPDATE CASCADE ON DELETE SET NULL,
+    ADD CONSTRAINT fk_threads_accepted_by_users_uid FOREIGN KEY (accepted_by) REFERENCES public.users (uid) MATCH SIMPLE ON UPDATE CASCADE ON DELETE SET NULL;
+
+-- Karma for having a post accepted as the answer of a thread
+ALTER TABLE public.karma
+    ADD COLUMN IF NOT EXISTS accepted_answer integer NOT NULL DEFAULT 0;
+
+UPDATE public.karma SET accepted_answer = 15;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE public.karma
+    DROP COLUMN IF EXISTS accepted_answer;
+
+ALTER TABLE public.threads
+    DROP CONSTRAINT IF EXISTS fk_threads_accepted_by_users_uid,
+    DROP CONSTRAINT IF EXISTS fk_threads_accepted_post_posts_post_id,
+    DROP COLUMN IF EXISTS accepted_karma,
+    DROP COLUMN IF EXISTS time_accepted,
+    DROP COLUMN IF EXISTS accepted_by,
+    DROP COLUMN IF EXISTS accepted_post;
+-- +goose StatementEnd
@@ -0,0 +1,39 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Endorsement of threads and posts by instructors. Content is endorsed if time_endorsed is set; endorsed_by is cleared
+-- if the endorsing user is removed.
+ALTER TABLE public.threads
+    ADD COLUMN IF NOT EXISTS endorsed_by text,
+    ADD COLUMN IF NOT EXISTS time_endorsed timestamp with time zone,
+    ADD CONSTRAINT fk_threads_endorsed_by_users_uid FOREIGN KEY (endorsed_by) REFERENCES public.users (uid) MATCH SIMPLE ON UPDATE CASCADE ON DELETE SET NULL;
+
+ALTER TABLE public.posts
+    ADD COLUMN IF NOT EXISTS endorsed_by text,
+    ADD COLUMN IF NOT EXISTS time_endorsed timestamp with time zone,
+    ADD CONSTRAINT fk_posts_endorsed_by_users_uid FOREIGN KEY (endorsed_by) REFERENCES public.users (uid) MATCH SIMPLE ON UPDATE CASCADE ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_threads_time_endorsed ON public.threads (time_endorsed) WHERE time_endorsed IS NOT NULL;
+
+INSERT INTO public.role_permissions (role, permission) VALUES
+    ('ta', 'content.endorse'),
+    ('staff', 'content.endorse'),
+    ('admin', 'content.endorse')
+ON CONFLICT DO NOTHING;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DELETE FROM public.role_permissions WHERE permission = 'content.endorse';
+
+DROP INDEX IF EXISTS idx_threads_time_endorsed;
+
+ALTER TABLE public.posts
+    DROP CONSTRAINT IF EXISTS fk_posts_endorsed_by_users_uid,
+    DROP COLUMN IF EXISTS time_endorsed,
+    DROP COLUMN IF EXISTS endorsed_by;
+
+ALTER TABLE public.threads
+    DROP CONSTRAINT IF EXISTS fk_threads_endorsed_by_users_uid,
+    DROP COLUMN IF EXISTS time_endorsed,
+    DROP COLUMN IF EXISTS endorsed_by;
+-- +goose StatementEnd
@@ -0,0 +1,46 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Poll carried by a thread. A thread has at most one poll.
+CREATE TABLE IF NOT EXISTS public.polls (
+    poll_id text NOT NULL PRIMARY KEY,
+    thread_id text NOT NULL UNIQUE,
+    question text NOT NULL,
+    multiple_choice boolean NOT NULL DEFAULT false,
+    is_anon boolean NOT NULL DEFAULT true,  -- Voters are hidden from results
+    closes_at timestamp with time zone,     -- Never closes if null
+    created_by text NOT NULL,
+    time_created timestamp with time zone NOT NULL DEFAULT now(),
+    CONSTRAINT fk_polls_thread_id_threads_thread_id FOREIGN KEY (thread_id) REFERENCES public.threads (thread_id) MATCH SIMPLE ON UPDATE CASCADE ON DELETE CASCADE,
+    CONSTRAINT fk_polls_created_by_users_uid FOREIGN KEY (created_by) REFERENCES public.users (uid) MATCH SIMPLE ON UPDATE CASCADE ON DELETE NO ACTION
+);
+
+CREATE TABLE IF NOT EXISTS public.poll_options (
+    option_id text NOT NULL PRIMARY KEY,
+    poll_id text NOT NULL,
+    position integer NOT NULL,
+    text text NOT NULL,
+    CONSTRAINT fk_poll_options_poll_id_polls_poll_id FOREIGN KEY (poll_id) REFERENCES public.polls (poll_id) MATCH SIMPLE ON UPDATE CASCADE ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_poll_options_poll_id ON public.poll_options (poll_id);
+
+CREATE TABLE IF NOT EXISTS public.poll_votes (
+    poll_id text NOT NULL,
+    option_id text NOT NULL,
+    uid text NOT NULL,
+    time_voted timestamp with time zone NOT NULL DEFAULT now(),
+    PRIMARY KEY (option_id, uid),
+    CONSTRAINT fk_poll_votes_poll_id_polls_poll_id FOREIGN KEY (poll_id) REFERENCES public.polls (poll_id) MATCH SIMPLE ON UPDATE CASCADE ON DELETE CASCADE,
+    CONSTRAINT fk_poll_votes_option_id_poll_options_option_id FOREIGN KEY (option_id) REFERENCES public.poll_options (option_id) MATCH SIMPLE ON UPDATE CASCADE ON DELETE CASCADE,
+    CONSTRAINT fk_poll_votes_uid_users_uid FOREIGN KEY (uid) REFERENCES public.users (uid) MATCH SIMPLE ON UPDATE CASCADE ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_poll_votes_poll_id_uid ON public.poll_votes (poll_id, uid);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS public.poll_votes;
+DROP TABLE IF EXISTS public.poll_options;
+DROP TABLE IF EXISTS public.polls;
+-- +goose StatementEnd
@@ -0,0 +1,21 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Users mentioned in a thread, post, article or comment
+CREATE TABLE IF NOT EXISTS public.mentions (
+    content_id text NOT NULL,
+    content_type text NOT NULL,
+    uid text NOT NULL,          -- Mentioned user
+    author text NOT NULL,       -- Author of the content
+    time_created timestamp with time zone NOT NULL DEFAULT now(),
+    PRIMARY KEY (content_id, uid),
+    CONSTRAINT fk_mentions_uid_users_uid FOREIGN KEY (uid) REFERENCES public.users (uid) MATCH SIMPLE ON UPDATE CASCADE ON DELETE CASCADE,
+    CONSTRAINT fk_mentions_author_users_uid FOREIGN KEY (author) REFERENCES public.users (uid) MATCH SIMPLE ON UPDATE CASCADE ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_mentions_uid ON public.mentions (uid);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS public.mentions;
+-- +goose StatementEnd
@@ -0,0 +1,34 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Notification feed of users. Notifications are created by the services that trigger them.
+CREATE TABLE IF NOT EXISTS public.notifications (
+    notification_id text NOT NULL PRIMARY KEY,
+    uid text NOT NULL,                -- Recipient
+    type text NOT NULL,
+    actor text,                       -- User who triggered the notification, if any
+    content_id text,                  -- Thread, post, article or comment the notification is about
+    message text NOT NULL DEFAULT '',
+    is_read boolean NOT NULL DEFAULT false,
+    time_created timestamp with time zone NOT NULL DEFAULT now(),
+    CONSTRAINT fk_notifications_uid_users_uid FOREIGN KEY (uid) REFERENCES public.users (uid) MATCH SIMPLE ON UPDATE CASCADE ON DELETE CASCADE,
+    CONSTRAINT fk_notifications_actor_users_uid FOREIGN KEY (actor) REFERENCES public.users (uid) MATCH SIMPLE ON UPDATE CASCADE ON DELETE SET NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_notifications_uid_time_created ON public.notifications (uid, time_created DESC);
+CREATE INDEX IF NOT EXISTS idx_notifications_uid_unread ON public.notifications (uid) WHERE NOT is_read;
+
+-- Types of notification each user has muted. Muted notifications are not created. Announcements always reach every user.
+CREATE TABLE IF NOT EXISTS public.notification_mutes (
+    uid text NOT NULL,
+    type text NOT NULL,
+    PRIMARY KEY (uid, type),
+    CONSTRAINT fk_notification_mutes_uid_users_uid FOREIGN KEY (uid) REFERENCES public.users (uid) MATCH SIMPLE ON UPDATE CASCADE ON DELETE CASCADE,
+    CONSTRAINT notification_mutes_type_check CHECK (type <> 'announcement')
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS public.notification_mutes;
+DROP TABLE IF EXISTS public.notifications;
+-- +goose StatementEnd